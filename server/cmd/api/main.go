@@ -1,12 +1,21 @@
 package main
 
 import (
+	"companion_server/internal/academic"
 	"companion_server/internal/api"
+	"companion_server/internal/notify"
+	"companion_server/internal/scraper"
 	"companion_server/internal/storage"
+	"companion_server/internal/tasks"
+	"companion_server/internal/webhooks"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func main() {
@@ -17,14 +26,110 @@ func main() {
 		log.Fatal("Tablo oluşturma hatası:", err)
 	}
 
-	// 2. Scraper & Scheduler Kurulumu (Her başlatmada uzun bi tarama yaptığı için çıkardım şimdilik. Veri DB'de mevcut.)
-	//ebsService := scraper.NewService()
-	//scheduler := tasks.NewScheduler(db, ebsService)
-	//scheduler.Start(24 * time.Hour) // Scraper çalışma sıklığı
+	// Yönetmelik kuralları (not katsayıları, AGNO eşikleri, AKTS limitleri) REGULATIONS_FILE ile
+	// verilen JSON dizisinden yürürlük yılına göre eklenir.
+	if path := os.Getenv("REGULATIONS_FILE"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			log.Fatal("Yönetmelik dosyası açılamadı:", err)
+		}
+		err = academic.LoadRegulations(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Bildirimler: NOTIFIER_WEBHOOK_URL verilmişse oraya gönderilir, yoksa sadece loglanır.
+	var notifier notify.Notifier = notify.LogNotifier{}
+	if endpoint := os.Getenv("NOTIFIER_WEBHOOK_URL"); endpoint != "" {
+		notifier = notify.NewWebhookNotifier(endpoint)
+	}
+	dispatcher := notify.NewDispatcher(db, notifier)
+
+	// Tarama ve duyuru olaylarının webhook'lara teslimi
+	webhooks.NewWorker(db).Start(30 * time.Second)
 
 	// 3. API Handler Kurulumu
 	handler := api.NewHandler(db)
-	router := api.SetupRoutes(handler)
+	// Akışlardaki mutlak bağlantıların kökü; verilmezse isteğin Host başlığı kullanılır.
+	if base := os.Getenv("PUBLIC_BASE_URL"); base != "" {
+		if u, err := url.Parse(base); err != nil || u.Scheme == "" || u.Host == "" {
+			log.Fatalf("PUBLIC_BASE_URL geçersiz: %q", base)
+		}
+		handler.PublicURL = base
+	}
+
+	// 4. Scraper & Scheduler Kurulumu. Tam tarama uzun sürdüğü için açılışta sadece
+	// SCRAPE_ON_START=true ise yapılır; aralık SCRAPE_INTERVAL ile değiştirilebilir.
+	scrapeInterval := 24 * time.Hour
+	if d, err := time.ParseDuration(os.Getenv("SCRAPE_INTERVAL")); err == nil && d > 0 {
+		scrapeInterval = d
+	}
+	// Tarama sonrası ders programı alımı da kaynak arar, bu yüzden aynı ayarı kullanır.
+	timetableScraper := scraper.NewService()
+	if units := os.Getenv("TIMETABLE_UNITS_URL"); units != "" {
+		timetableScraper.UnitsURL = units
+	}
+	scheduler := tasks.NewScheduler(db, timetableScraper)
+	scheduler.OnCommit = handler.Cache.Invalidate // Tarama sonrası yanıt önbelleğini temizler
+	scheduler.OnChanges = dispatcher.NotifyCourseChanges
+	handler.TriggerScrape = scheduler.Trigger
+	scheduler.Start(scrapeInterval, os.Getenv("SCRAPE_ON_START") == "true")
+
+	// Duyuru kaynakları "siteKey:kategori" çiftleri olarak virgülle ayrılmış verilir.
+	if sources := os.Getenv("ANNOUNCEMENT_SOURCES"); sources != "" {
+		parsed, err := tasks.ParseAnnouncementSources(sources)
+		if err != nil {
+			log.Fatal(err)
+		}
+		interval := 15 * time.Minute
+		if d, err := time.ParseDuration(os.Getenv("ANNOUNCEMENT_INTERVAL")); err == nil && d > 0 {
+			interval = d
+		}
+		poller := tasks.NewAnnouncementPoller(db, scraper.NewService(), parsed)
+		poller.OnCommit = func() { handler.Cache.InvalidateScope(storage.AnnouncementVersion) }
+		poller.OnAnnouncements = dispatcher.NotifyAnnouncements
+		poller.Start(interval)
+	}
+
+	// Akademik takvim EBS taramasından bağımsız günde bir alınır; CALENDAR_URL ile sayfa değiştirilebilir.
+	calendarScraper := scraper.NewService()
+	if page := os.Getenv("CALENDAR_URL"); page != "" {
+		calendarScraper.CalendarURL = page
+	}
+	calendar := tasks.NewCalendarPoller(db, calendarScraper)
+	calendar.OnCommit = handler.Cache.Invalidate
+	calendar.Start(24 * time.Hour)
+
+	// Bölümlerin ders programı PDF'leri. Kaynağı ayarlanmamış bölümlerin sayfaları TIMETABLE_UNITS_URL
+	// (varsayılan üniversite ana sayfası) üzerinden aranır; elle ayarlamak için
+	// bkz. /api/v1/admin/departments/{guid}/timetable-source.
+	timetables := tasks.NewTimetablePoller(db, timetableScraper)
+	timetables.OnCommit = handler.Cache.Invalidate
+	handler.TriggerTimetables = timetables.Trigger
+	timetables.Start(24 * time.Hour)
+
+	// Virgülle ayrılmış köken listesi, verilmezse tüm kökenlere izin verilir.
+	origins := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if origins == "" {
+		origins = "*"
+	}
+	cfg := api.Config{
+		AllowedOrigins:    strings.Split(origins, ","),
+		TrustProxyHeaders: os.Getenv("TRUST_PROXY") == "true",
+	}
+	if hops, err := strconv.Atoi(os.Getenv("TRUSTED_PROXY_HOPS")); err == nil && hops > 0 {
+		cfg.TrustedProxyHops = hops
+	}
+	// Virgülle ayrılmış IP, CIDR ya da API anahtarları
+	if allowlist := os.Getenv("RATE_LIMIT_ALLOWLIST"); allowlist != "" {
+		cfg.RateLimitAllowlist = strings.Split(allowlist, ",")
+	}
+	if rps, err := strconv.ParseFloat(os.Getenv("RATE_LIMIT_RPS"), 64); err == nil && rps > 0 {
+		cfg.DefaultRateLimit = api.RateLimit{Rate: rps, Burst: max(1, int(rps*4))}
+	}
+	router := api.SetupRoutes(handler, cfg)
 
 	port := os.Getenv("PORT")
 
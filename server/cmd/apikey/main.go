@@ -0,0 +1,99 @@
+package main
+
+import (
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+)
+
+const usage = `Kullanım:
+  apikey issue -name <ad> -role <reader|operator|admin>
+  apikey revoke <id>
+  apikey list`
+
+// Hatalı kullanım; kullanım metni yazılır ve 2 koduyla çıkılır.
+var errUsage = errors.New(usage)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+
+	db := storage.OpenDB()
+	if err := storage.CreateTables(db); err != nil {
+		log.Fatal("Tablo oluşturma hatası:", err)
+	}
+
+	err := run(db, os.Args[1:], os.Stdout)
+	if errors.Is(err, errUsage) {
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(db storage.DB, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "issue":
+		fs := flag.NewFlagSet("issue", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		name := fs.String("name", "", "anahtarın kime/neye ait olduğu")
+		role := fs.String("role", string(models.RoleReader), "reader, operator ya da admin")
+		if err := fs.Parse(args[1:]); err != nil {
+			return errUsage
+		}
+
+		if *name == "" || !models.Role(*role).Valid() {
+			return errUsage
+		}
+
+		key, k, err := storage.CreateAPIKey(db, *name, models.Role(*role))
+		if err != nil {
+			return fmt.Errorf("anahtar oluşturulamadı: %w", err)
+		}
+		fmt.Fprintf(out, "Anahtar oluşturuldu (id %d, rol %s). Bu anahtar bir daha gösterilmeyecek:\n%s\n", k.ID, k.Role, key)
+
+	case "revoke":
+		if len(args) < 2 {
+			return errUsage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("geçersiz id: %s", args[1])
+		}
+		if err := storage.RevokeAPIKey(db, id); err != nil {
+			return fmt.Errorf("anahtar iptal edilemedi (yok ya da zaten iptal edilmiş): %w", err)
+		}
+		fmt.Fprintf(out, "%d numaralı anahtar iptal edildi.\n", id)
+
+	case "list":
+		keys, err := storage.ListAPIKeys(db)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			state := "aktif"
+			if k.RevokedAt != nil {
+				state = "iptal " + k.RevokedAt.Format("2006-01-02")
+			}
+			fmt.Fprintf(out, "%d\t%s…\t%s\t%s\t%s\n", k.ID, k.Prefix, k.Role, k.Name, state)
+		}
+
+	default:
+		return errUsage
+	}
+	return nil
+}
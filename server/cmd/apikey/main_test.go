@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestRun(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"bilinmeyen"},
+		{"issue"},
+		{"issue", "-name", "panel", "-role", "root"},
+		{"issue", "-bilinmeyen"},
+		{"revoke"},
+	} {
+		if err := run(db, args, &bytes.Buffer{}); !errors.Is(err, errUsage) {
+			t.Errorf("%q: err = %v, want usage", args, err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := run(db, []string{"issue", "-name", "panel", "-role", "operator"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	key := regexp.MustCompile(`icm_\S+`).FindString(out.String())
+	k, err := storage.AuthenticateAPIKey(db, key)
+	if err != nil || k.Name != "panel" || k.Role != models.RoleOperator {
+		t.Fatalf("issued key = %+v, %v (output %q)", k, err, out.String())
+	}
+
+	out.Reset()
+	if err := run(db, []string{"revoke", "1"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.AuthenticateAPIKey(db, key); err != sql.ErrNoRows {
+		t.Errorf("revoked key still valid: %v", err)
+	}
+	if err := run(db, []string{"revoke", "1"}, &out); err == nil || errors.Is(err, errUsage) {
+		t.Errorf("second revoke: err = %v", err)
+	}
+	if err := run(db, []string{"revoke", "bir"}, &out); err == nil || errors.Is(err, errUsage) {
+		t.Errorf("invalid id: err = %v", err)
+	}
+
+	out.Reset()
+	if err := run(db, []string{"list"}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if line := out.String(); !strings.HasPrefix(line, "1\t"+k.Prefix+"…\toperator\tpanel\tiptal ") {
+		t.Errorf("list = %q", line)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"companion_server/internal/api"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+// OpenAPI belgesini rota tablosundan üretir. -check ile kayıtlı belge güncel değilse
+// sıfırdan farklı kodla çıkar; CI'da belge ile kodun ayrışmasını yakalamak için kullanılır.
+//
+//	go run ./cmd/openapi -o openapi.json
+//	go run ./cmd/openapi -check openapi.json
+func main() {
+	out := flag.String("o", "", "belgenin yazılacağı dosya (boşsa standart çıktı)")
+	check := flag.String("check", "", "karşılaştırılacak kayıtlı belge")
+	flag.Parse()
+
+	spec, err := api.OpenAPISpec()
+	if err != nil {
+		log.Fatal("OpenAPI belgesi üretilemedi:", err)
+	}
+	spec = append(spec, '\n')
+
+	if *check != "" {
+		saved, err := os.ReadFile(*check)
+		if err != nil {
+			log.Fatal("Kayıtlı belge okunamadı:", err)
+		}
+		if !bytes.Equal(saved, spec) {
+			fmt.Fprintf(os.Stderr, "%s güncel değil, go run ./cmd/openapi -o %s ile yeniden üretin\n", *check, *check)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *out == "" {
+		os.Stdout.Write(spec)
+		return
+	}
+	if err := os.WriteFile(*out, spec, 0644); err != nil {
+		log.Fatal("Belge yazılamadı:", err)
+	}
+}
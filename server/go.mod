@@ -4,6 +4,7 @@ go 1.25
 
 require (
 	github.com/PuerkitoBio/goquery v1.11.0
+	github.com/andybalholm/brotli v1.2.0
 	github.com/mattn/go-sqlite3 v1.14.33
 )
 
@@ -0,0 +1,245 @@
+package academic
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/models"
+)
+
+// Yarıyıl başına AKTS yükü (Bologna)
+const ECTSPerSemester = 30.0
+
+// Müfredat dışı koduyla alınmış ama müfredattaki bir dersin yerine sayılan ders
+type Equivalence struct {
+	CompletedCode string `json:"completed_code"`
+	CountsAs      string `json:"counts_as"`
+	Reason        string `json:"reason"`
+}
+
+// Seçmeli havuzunun doluluk durumu
+type PoolProgress struct {
+	GroupID        int      `json:"group_id"`
+	Name           string   `json:"name"`
+	Semester       string   `json:"semester"`
+	RequiredCount  int      `json:"required_count"`
+	RequiredECTS   float64  `json:"required_ects"`
+	CompletedCount int      `json:"completed_count"`
+	CompletedECTS  float64  `json:"completed_ects"`
+	Courses        []string `json:"courses"`
+	Fulfilled      bool     `json:"fulfilled"`
+}
+
+type AuditInput struct {
+	Curriculum     []models.Course
+	ElectiveGroups []models.ElectiveGroup
+	CurriculumYear int
+	Completed      []TranscriptEntry
+
+	// Bölüm dışından alınan derslerin bilgileri (koda göre)
+	External map[string]models.Course
+	// Onaylanmış eşdeğerlikler: alınan ders kodu -> müfredattaki ders kodu
+	Equivalences map[string]string
+}
+
+type AuditResult struct {
+	CurriculumYear   int             `json:"curriculum_year"`
+	EarnedECTS       float64         `json:"earned_ects"`
+	RequiredECTS     float64         `json:"required_ects"`
+	EarnedCredit     float64         `json:"earned_credit"`
+	RequiredCredit   float64         `json:"required_credit"`
+	GPA              float64         `json:"gpa"`
+	MissingMandatory []models.Course `json:"missing_mandatory"`
+	ElectivePools    []PoolProgress  `json:"elective_pools"`
+	Equivalents      []Equivalence   `json:"equivalents"`
+	Unmatched        []string        `json:"unmatched"`
+	Failed           []string        `json:"failed"`
+	Complete         bool            `json:"complete"`
+}
+
+func normalizeName(s string) string {
+	return strings.Join(strings.Fields(strings.ToLowerSpecial(unicode.TurkishCase, s)), " ")
+}
+
+// Müfredat yılına göre öğrencinin tabi olduğu dersler mi? Kaldırılmış dersler, öğrencinin
+// müfredat yılında hâlâ okutuluyorsa sayılır.
+func inCurriculum(c models.Course, curriculumYear int) bool {
+	if !c.IsRemoved {
+		return true
+	}
+	return curriculumYear > 0 && c.Year >= curriculumYear
+}
+
+// Öğrencinin mezuniyet şartlarına göre durumunu çıkarır.
+func Audit(in AuditInput) AuditResult {
+	result := AuditResult{
+		CurriculumYear:   in.CurriculumYear,
+		MissingMandatory: []models.Course{},
+		ElectivePools:    []PoolProgress{},
+		Equivalents:      []Equivalence{},
+		Unmatched:        []string{},
+		Failed:           []string{},
+	}
+
+	byCode := make(map[string]models.Course)
+	activeByName := make(map[string]models.Course)
+	for _, c := range in.Curriculum {
+		byCode[c.Code] = c
+		if !c.IsRemoved {
+			activeByName[normalizeName(c.Name)] = c
+		}
+	}
+
+	lookup := func(code string) (models.Course, bool) {
+		if c, ok := byCode[code]; ok {
+			return c, true
+		}
+		c, ok := in.External[code]
+		return c, ok
+	}
+
+	// Öğrencinin müfredat yılındaki dersler. Kodu değişerek kaldırılan ders, aynı adlı güncel
+	// dersle karşılandığı için ayrıca aranmaz.
+	required := func(c models.Course) bool {
+		if !inCurriculum(c, in.CurriculumYear) {
+			return false
+		}
+		if !c.IsRemoved {
+			return true
+		}
+		_, renamed := activeByName[normalizeName(c.Name)]
+		return !renamed
+	}
+
+	reg := RegulationFor(in.CurriculumYear)
+	courses := make(map[string]models.Course, len(byCode)+len(in.External))
+	for code, c := range in.External {
+		courses[code] = c
+	}
+	for code, c := range byCode {
+		courses[code] = c
+	}
+	gpa := reg.Calculate(in.Completed, courses)
+	result.GPA = gpa.AGNO
+
+	codes := make([]string, 0, len(gpa.Grades))
+	for code := range gpa.Grades {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	// Müfredat kodu -> tamamlandı
+	satisfied := make(map[string]bool)
+	for _, code := range codes {
+		if !reg.IsPassed(gpa.Grades[code], result.GPA) {
+			result.Failed = append(result.Failed, code)
+			continue
+		}
+
+		if target, ok := in.Equivalences[code]; ok {
+			if _, known := byCode[target]; known {
+				satisfied[target] = true
+				result.Equivalents = append(result.Equivalents, Equivalence{
+					CompletedCode: code, CountsAs: target, Reason: "onaylı eşdeğerlik",
+				})
+				continue
+			}
+		}
+
+		if c, ok := byCode[code]; ok && required(c) {
+			satisfied[code] = true
+			continue
+		}
+
+		// Kodu değişmiş ya da başka bölümden alınmış ders: aynı adlı güncel derse sayılır.
+		if c, ok := lookup(code); ok {
+			if target, ok := activeByName[normalizeName(c.Name)]; ok && target.Code != code {
+				satisfied[target.Code] = true
+				result.Equivalents = append(result.Equivalents, Equivalence{
+					CompletedCode: code, CountsAs: target.Code, Reason: "aynı ders adı",
+				})
+				continue
+			}
+		}
+
+		result.Unmatched = append(result.Unmatched, code)
+	}
+
+	maxSemester := 0
+	for _, c := range in.Curriculum {
+		if satisfied[c.Code] {
+			result.EarnedECTS += c.ECTS
+			result.EarnedCredit += c.Credit
+		}
+
+		if !required(c) {
+			continue
+		}
+		if sem := ParseSemester(c.Semester); sem > maxSemester && sem <= 12 {
+			maxSemester = sem
+		}
+		if c.IsMandatory {
+			result.RequiredCredit += c.Credit
+			if !satisfied[c.Code] {
+				result.MissingMandatory = append(result.MissingMandatory, c)
+			}
+		}
+	}
+
+	sort.SliceStable(result.MissingMandatory, func(i, j int) bool {
+		return ParseSemester(result.MissingMandatory[i].Semester) < ParseSemester(result.MissingMandatory[j].Semester)
+	})
+
+	poolsFulfilled := true
+	var poolECTS float64
+	for _, g := range in.ElectiveGroups {
+		p := PoolProgress{
+			GroupID:       g.ID,
+			Name:          g.Name,
+			Semester:      g.Semester,
+			RequiredCount: g.RequiredCount,
+			RequiredECTS:  g.RequiredECTS,
+			Courses:       []string{},
+		}
+
+		var memberCredit float64
+		for _, code := range g.CourseCodes {
+			c := byCode[code]
+			memberCredit += c.Credit
+			if satisfied[code] {
+				p.CompletedCount++
+				p.CompletedECTS += c.ECTS
+				p.Courses = append(p.Courses, code)
+			}
+		}
+		if len(g.CourseCodes) > 0 {
+			// Havuzdan alınacak derslerin kredisi üye derslerin ortalamasıyla tahmin edilir.
+			result.RequiredCredit += float64(g.RequiredCount) * memberCredit / float64(len(g.CourseCodes))
+		}
+
+		p.Fulfilled = p.CompletedCount >= p.RequiredCount && p.CompletedECTS >= p.RequiredECTS
+		if !p.Fulfilled {
+			poolsFulfilled = false
+		}
+		poolECTS += g.RequiredECTS
+		result.ElectivePools = append(result.ElectivePools, p)
+	}
+
+	result.RequiredECTS = float64(maxSemester) * ECTSPerSemester
+	if result.RequiredECTS == 0 {
+		for _, c := range in.Curriculum {
+			if c.IsMandatory && required(c) {
+				result.RequiredECTS += c.ECTS
+			}
+		}
+		result.RequiredECTS += poolECTS
+	}
+
+	result.Complete = len(result.MissingMandatory) == 0 &&
+		poolsFulfilled &&
+		result.EarnedECTS >= result.RequiredECTS &&
+		result.GPA >= reg.GraduationGPA
+
+	return result
+}
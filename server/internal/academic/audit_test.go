@@ -0,0 +1,80 @@
+package academic
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func auditCurriculum() []models.Course {
+	return []models.Course{
+		{Code: "MAT101", Name: "Matematik I", Credit: 4, ECTS: 6, IsMandatory: true, Semester: "1. Yarıyıl", Year: 2025},
+		{Code: "FIZ101", Name: "Fizik I", Credit: 3, ECTS: 5, IsMandatory: true, Semester: "1. Yarıyıl", Year: 2025},
+		{Code: "PRG201", Name: "Programlama", Credit: 3, ECTS: 5, IsMandatory: true, Semester: "2. Yarıyıl", Year: 2025},
+		{Code: "SEC101", Name: "Felsefe", Credit: 2, ECTS: 3, Semester: "1. Yarıyıl", Year: 2025},
+		{Code: "SEC102", Name: "Sanat", Credit: 2, ECTS: 3, Semester: "1. Yarıyıl", Year: 2025},
+		// Kodu değişen ders
+		{Code: "OLD100", Name: "Programlama", Credit: 3, ECTS: 5, IsMandatory: true, Semester: "2. Yarıyıl", Year: 2021, IsRemoved: true},
+		// 2022'de kaldırılan, 2020 girişlilerin müfredatındaki ders
+		{Code: "TAR101", Name: "Tarih", Credit: 2, ECTS: 2, IsMandatory: true, Semester: "2. Yarıyıl", Year: 2021, IsRemoved: true},
+	}
+}
+
+func auditGroups() []models.ElectiveGroup {
+	return []models.ElectiveGroup{
+		{ID: 1, Name: "Seçmeli I", Semester: "1. Yarıyıl", RequiredCount: 1, RequiredECTS: 3, CourseCodes: []string{"SEC101", "SEC102"}},
+	}
+}
+
+func missingCodes(r AuditResult) map[string]bool {
+	codes := make(map[string]bool)
+	for _, c := range r.MissingMandatory {
+		codes[c.Code] = true
+	}
+	return codes
+}
+
+func TestAuditCurriculumYear(t *testing.T) {
+	completed := []TranscriptEntry{
+		{Code: "MAT101", Grade: "BB"},
+		{Code: "FIZ101", Grade: "FF"},
+		{Code: "FIZ101", Grade: "CC"},
+		{Code: "OLD100", Grade: "AA"},
+		{Code: "SEC102", Grade: "dd"},
+		{Code: "XYZ999", Grade: "AA"},
+	}
+
+	current := Audit(AuditInput{Curriculum: auditCurriculum(), ElectiveGroups: auditGroups(), Completed: completed})
+	if len(current.MissingMandatory) != 0 {
+		t.Errorf("missing = %v, want none", missingCodes(current))
+	}
+	if len(current.Equivalents) != 1 || current.Equivalents[0].CompletedCode != "OLD100" || current.Equivalents[0].CountsAs != "PRG201" {
+		t.Errorf("equivalents = %+v", current.Equivalents)
+	}
+	if len(current.Unmatched) != 1 || current.Unmatched[0] != "XYZ999" {
+		t.Errorf("unmatched = %v", current.Unmatched)
+	}
+	if len(current.Failed) != 0 {
+		t.Errorf("failed = %v, want none (last grade counts)", current.Failed)
+	}
+	if p := current.ElectivePools[0]; !p.Fulfilled || p.CompletedCount != 1 {
+		t.Errorf("pool = %+v", p)
+	}
+	if current.EarnedECTS != 6+5+5+3 {
+		t.Errorf("earned ECTS = %v", current.EarnedECTS)
+	}
+	if current.RequiredECTS != 2*ECTSPerSemester || current.Complete {
+		t.Errorf("required ECTS = %v, complete = %v", current.RequiredECTS, current.Complete)
+	}
+
+	// 2020 girişli öğrencinin müfredatında kaldırılan Tarih dersi hâlâ zorunludur; kodu değişen
+	// Programlama ise güncel dersiyle karşılanır.
+	old := Audit(AuditInput{Curriculum: auditCurriculum(), ElectiveGroups: auditGroups(), CurriculumYear: 2020, Completed: completed})
+	missing := missingCodes(old)
+	if len(missing) != 1 || !missing["TAR101"] {
+		t.Errorf("missing = %v, want TAR101", missing)
+	}
+	if len(old.Equivalents) != 1 || old.Equivalents[0].CountsAs != "PRG201" {
+		t.Errorf("equivalents = %+v", old.Equivalents)
+	}
+}
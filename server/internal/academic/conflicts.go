@@ -0,0 +1,177 @@
+package academic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/models"
+	"companion_server/internal/timetable"
+)
+
+// Dersin çakışmayı çözen diğer şubesi
+type SectionOption struct {
+	Code    string         `json:"code"`
+	Section string         `json:"section"`
+	Slots   []ScheduleSlot `json:"slots"`
+}
+
+// Aynı gün kesişen iki ders saati
+type Conflict struct {
+	Day string `json:"day"`
+	// Kesişen aralık
+	Start  string       `json:"start"`
+	End    string       `json:"end"`
+	First  ScheduleSlot `json:"first"`
+	Second ScheduleSlot `json:"second"`
+	// Çakışan derslerin, seçilen diğer derslerle çakışmayan şubeleri
+	Alternatives []SectionOption `json:"alternatives"`
+}
+
+type ConflictResult struct {
+	Conflicts []Conflict `json:"conflicts"`
+	// Ders programında saati bulunamayan dersler ("KOD" ya da "KOD/şube")
+	Unscheduled []string `json:"unscheduled"`
+}
+
+// "2", "grup 2" ve "Grup 2" aynı şubedir.
+func NormalizeSection(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+		return "Grup " + s
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 2 && strings.EqualFold(fields[0], "grup") {
+		return "Grup " + fields[1]
+	}
+	return s
+}
+
+// Seçilen derslerin saatlerini karşılaştırır. Şubesi seçilen dersin sadece o şubesi ve
+// şubesiz (ortak) saatleri, seçilmeyen dersin bütün saatleri alınır. Aynı dersin saatleri
+// birbiriyle karşılaştırılmaz.
+func FindConflicts(selected []models.CourseSelection, schedule []ScheduleSlot) ConflictResult {
+	byCode := make(map[string][]ScheduleSlot)
+	for _, s := range schedule {
+		byCode[s.Code] = append(byCode[s.Code], s)
+	}
+
+	result := ConflictResult{Conflicts: []Conflict{}, Unscheduled: []string{}}
+	chosen := make(map[string][]ScheduleSlot)
+	var codes []string
+	for _, sel := range selected {
+		if _, dup := chosen[sel.Code]; dup {
+			continue
+		}
+		section := NormalizeSection(sel.Section)
+		var slots []ScheduleSlot
+		for _, s := range byCode[sel.Code] {
+			if section == "" || s.Section == "" || strings.EqualFold(s.Section, section) {
+				slots = append(slots, s)
+			}
+		}
+		if len(slots) == 0 || (section != "" && !hasSection(slots, section)) {
+			name := sel.Code
+			if section != "" {
+				name += "/" + section
+			}
+			result.Unscheduled = append(result.Unscheduled, name)
+			continue
+		}
+		chosen[sel.Code] = slots
+		codes = append(codes, sel.Code)
+	}
+
+	for i, a := range codes {
+		for _, b := range codes[i+1:] {
+			for _, x := range chosen[a] {
+				for _, y := range chosen[b] {
+					if !x.Overlaps(y) {
+						continue
+					}
+					c := overlap(x, y)
+					c.Alternatives = append(c.Alternatives, alternatives(x, byCode, chosen)...)
+					c.Alternatives = append(c.Alternatives, alternatives(y, byCode, chosen)...)
+					result.Conflicts = append(result.Conflicts, c)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(result.Conflicts, func(i, j int) bool {
+		a, b := result.Conflicts[i], result.Conflicts[j]
+		if da, db := timetable.DayIndex(a.Day), timetable.DayIndex(b.Day); da != db {
+			return da < db
+		}
+		return a.Start < b.Start
+	})
+	return result
+}
+
+func hasSection(slots []ScheduleSlot, section string) bool {
+	for _, s := range slots {
+		if strings.EqualFold(s.Section, section) {
+			return true
+		}
+	}
+	return false
+}
+
+func overlap(x, y ScheduleSlot) Conflict {
+	s1, e1, _ := x.minutes()
+	s2, e2, _ := y.minutes()
+	return Conflict{
+		Day:          x.Day,
+		Start:        formatClock(max(s1, s2)),
+		End:          formatClock(min(e1, e2)),
+		First:        x,
+		Second:       y,
+		Alternatives: []SectionOption{},
+	}
+}
+
+func formatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// Slotun dersinin, diğer seçilen derslerle hiç çakışmayan başka şubeleri
+func alternatives(slot ScheduleSlot, byCode map[string][]ScheduleSlot, chosen map[string][]ScheduleSlot) []SectionOption {
+	if slot.Section == "" {
+		return nil
+	}
+
+	sections := make(map[string][]ScheduleSlot)
+	var order []string
+	for _, s := range byCode[slot.Code] {
+		if s.Section == "" || strings.EqualFold(s.Section, slot.Section) {
+			continue
+		}
+		if _, ok := sections[s.Section]; !ok {
+			order = append(order, s.Section)
+		}
+		sections[s.Section] = append(sections[s.Section], s)
+	}
+
+	var options []SectionOption
+	for _, section := range order {
+		free := true
+		for code, slots := range chosen {
+			if code == slot.Code {
+				continue
+			}
+			for _, s := range sections[section] {
+				for _, other := range slots {
+					free = free && !s.Overlaps(other)
+				}
+			}
+		}
+		if free {
+			options = append(options, SectionOption{Code: slot.Code, Section: section, Slots: sections[section]})
+		}
+	}
+	return options
+}
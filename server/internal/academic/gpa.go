@@ -0,0 +1,285 @@
+package academic
+
+import (
+	"sort"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+type StudentStatus string
+
+const (
+	StatusSuccessful StudentStatus = "successful"
+	StatusProbation  StudentStatus = "probation"
+	StatusFailed     StudentStatus = "failed"
+)
+
+// Müfredatta yarıyılı bulunamayan derslerin toplandığı dönem
+const unknownTerm = 99
+
+// Transkriptteki bir ders denemesi
+type TranscriptEntry struct {
+	Code  string `json:"code"`
+	Grade string `json:"grade"`
+	// Dersin alındığı dönem sırası (1, 2, ...). Boşsa müfredattaki yarıyılı kullanılır.
+	Term int `json:"term,omitempty"`
+	// Ders veritabanında bulunamazsa kullanılacak kredi
+	Credit float64 `json:"credit,omitempty"`
+}
+
+// Dönem sonu ortalamaları
+type TermGPA struct {
+	Term    int           `json:"term"`
+	Credits float64       `json:"credits"`
+	YANO    float64       `json:"yano"`
+	AGNO    float64       `json:"agno"`
+	Status  StudentStatus `json:"status"`
+}
+
+type GPAResult struct {
+	Regulation   string        `json:"regulation"`
+	Terms        []TermGPA     `json:"terms"`
+	AGNO         float64       `json:"agno"`
+	TotalCredits float64       `json:"total_credits"`
+	EarnedECTS   float64       `json:"earned_ects"`
+	Status       StudentStatus `json:"status"`
+	MaxECTS      float64       `json:"max_ects"`
+	LimitReason  string        `json:"limit_reason"`
+
+	// Güz ve bahar için öğrencinin bir sonraki zorunlu ders yarıyılı
+	TargetFall   int `json:"target_fall"`
+	TargetSpring int `json:"target_spring"`
+
+	// Ders kodu -> geçerli (tekrar kuralına göre seçilmiş) not
+	Grades map[string]string `json:"-"`
+}
+
+type resolvedEntry struct {
+	TranscriptEntry
+	index  int
+	term   int
+	course models.Course
+	known  bool
+}
+
+func (r Regulation) resolve(entries []TranscriptEntry, courses map[string]models.Course) []resolvedEntry {
+	var resolved []resolvedEntry
+	for i, e := range entries {
+		e.Code = strings.TrimSpace(e.Code)
+		e.Grade = strings.ToUpper(strings.TrimSpace(e.Grade))
+		if e.Code == "" || e.Grade == "" {
+			continue
+		}
+
+		re := resolvedEntry{TranscriptEntry: e, index: i, term: e.Term}
+		re.course, re.known = courses[e.Code]
+		if !re.known {
+			re.course = models.Course{Code: e.Code, Credit: e.Credit}
+		} else if re.course.Credit <= 0 {
+			re.course.Credit = e.Credit
+		}
+		if re.term <= 0 {
+			re.term = ParseSemester(re.course.Semester)
+		}
+		if re.term <= 0 {
+			re.term = unknownTerm
+		}
+		resolved = append(resolved, re)
+	}
+
+	sort.SliceStable(resolved, func(i, j int) bool {
+		if resolved[i].term != resolved[j].term {
+			return resolved[i].term < resolved[j].term
+		}
+		return resolved[i].index < resolved[j].index
+	})
+	return resolved
+}
+
+// Tekrar kuralına göre her dersin geçerli denemesini seçer. entries dönem sırasına göre sıralı olmalı.
+// Sonuç ders koduna göre sıralıdır; kayan noktalı toplamlar her çalıştırmada aynı çıksın.
+func (r Regulation) effective(entries []resolvedEntry) []resolvedEntry {
+	byCode := make(map[string]resolvedEntry)
+	for _, e := range entries {
+		prev, seen := byCode[e.Code]
+		if !seen || r.Repeat != RepeatBest {
+			byCode[e.Code] = e
+			continue
+		}
+		if r.GradePoints[e.Grade] >= r.GradePoints[prev.Grade] {
+			byCode[e.Code] = e
+		}
+	}
+
+	result := make([]resolvedEntry, 0, len(byCode))
+	for _, e := range byCode {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result
+}
+
+func (r Regulation) average(entries []resolvedEntry) (float64, float64) {
+	var points, credits float64
+	for _, e := range entries {
+		if !r.CountsInGPA(e.Grade) || e.course.Credit <= 0 {
+			continue
+		}
+		points += r.GradePoints[e.Grade] * e.course.Credit
+		credits += e.course.Credit
+	}
+	if credits == 0 {
+		return 0, 0
+	}
+	return points / credits, credits
+}
+
+// Transkriptten dönem ortalamalarını (YANO), genel ortalamayı (AGNO) ve akademik durumu hesaplar.
+// courses, ders kodlarının kredi/AKTS/yarıyıl bilgisidir.
+func (r Regulation) Calculate(entries []TranscriptEntry, courses map[string]models.Course) GPAResult {
+	result := GPAResult{
+		Regulation: r.Name,
+		Terms:      []TermGPA{},
+		Status:     StatusSuccessful,
+		Grades:     make(map[string]string),
+	}
+
+	resolved := r.resolve(entries, courses)
+
+	var terms []int
+	byTerm := make(map[int][]resolvedEntry)
+	for _, e := range resolved {
+		if _, ok := byTerm[e.term]; !ok {
+			terms = append(terms, e.term)
+		}
+		byTerm[e.term] = append(byTerm[e.term], e)
+	}
+
+	consecutiveFailures := 0
+	var upTo []resolvedEntry
+	for _, term := range terms {
+		upTo = append(upTo, byTerm[term]...)
+
+		yano, credits := r.average(byTerm[term])
+		agno, _ := r.average(r.effective(upTo))
+
+		if agno >= r.ProbationGPA {
+			result.Status = StatusSuccessful
+			consecutiveFailures = 0
+		} else if credits > 0 {
+			consecutiveFailures++
+			if consecutiveFailures == 1 {
+				result.Status = StatusProbation
+			} else {
+				result.Status = StatusFailed
+			}
+		}
+
+		result.Terms = append(result.Terms, TermGPA{
+			Term:    term,
+			Credits: credits,
+			YANO:    yano,
+			AGNO:    agno,
+			Status:  result.Status,
+		})
+	}
+
+	effective := r.effective(resolved)
+	result.AGNO, result.TotalCredits = r.average(effective)
+
+	lastFall, lastSpring := -1, 0
+	hasDebt := false
+	for _, e := range effective {
+		result.Grades[e.Code] = e.Grade
+
+		if r.IsPassed(e.Grade, result.AGNO) {
+			result.EarnedECTS += e.course.ECTS
+		}
+		if IsFailure(e.Grade) || IsAttendanceFailure(e.Grade) || IsConditional(e.Grade) {
+			hasDebt = true
+		}
+
+		if sem := ParseSemester(e.course.Semester); e.course.IsMandatory && sem > 0 {
+			if sem%2 != 0 && sem > lastFall {
+				lastFall = sem
+			} else if sem%2 == 0 && sem > lastSpring {
+				lastSpring = sem
+			}
+		}
+	}
+
+	result.TargetFall = max(lastFall+2, 1)
+	result.TargetSpring = max(lastSpring+2, 2)
+
+	switch {
+	case result.TargetFall >= r.SeniorSemester || result.TargetSpring >= r.SeniorSemester+1:
+		result.MaxECTS = r.SeniorECTSLimit
+	case hasDebt:
+		result.MaxECTS = r.DebtECTSLimit
+	default:
+		result.MaxECTS = r.BaseECTSLimit
+	}
+
+	result.LimitReason = LimitReason(result.Status, models.LangTR)
+	return result
+}
+
+var limitReasons = map[StudentStatus]map[string]string{
+	StatusSuccessful: {
+		models.LangTR: "Başarılı. Tüm dersleri alabilirsiniz.",
+		models.LangEN: "Successful. You can take all courses.",
+	},
+	StatusProbation: {
+		models.LangTR: "Sınamalı. Yeni dönem derslerini alabilirsiniz.",
+		models.LangEN: "On probation. You can take the new term's courses.",
+	},
+	StatusFailed: {
+		models.LangTR: "Başarısız. Yeni ZORUNLU ders alamazsınız.",
+		models.LangEN: "Failed. You cannot take new MANDATORY courses.",
+	},
+}
+
+// Akademik durumun AKTS limiti açıklaması; bilinmeyen dilde Türkçe döner.
+func LimitReason(status StudentStatus, lang string) string {
+	if reason, ok := limitReasons[status][lang]; ok {
+		return reason
+	}
+	return limitReasons[status][models.LangTR]
+}
+
+// What-if senaryosu: verilen notları transkripte uygular. Dönemi belirtilmeyen mevcut derslerde
+// son denemenin notu değiştirilir, yeni dersler ayrı deneme olarak eklenir. Notu boş olan
+// override dersi transkriptten çıkarır.
+func ApplyWhatIf(entries []TranscriptEntry, overrides []TranscriptEntry) []TranscriptEntry {
+	result := make([]TranscriptEntry, len(entries))
+	copy(result, entries)
+
+	for _, o := range overrides {
+		if strings.TrimSpace(o.Grade) == "" {
+			kept := result[:0]
+			for _, e := range result {
+				if e.Code != o.Code {
+					kept = append(kept, e)
+				}
+			}
+			result = kept
+			continue
+		}
+
+		replaced := false
+		if o.Term <= 0 {
+			for i := len(result) - 1; i >= 0; i-- {
+				if result[i].Code == o.Code {
+					result[i].Grade = o.Grade
+					replaced = true
+					break
+				}
+			}
+		}
+		if !replaced {
+			result = append(result, o)
+		}
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package academic
+
+import (
+	"fmt"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestCalculateDeterministic(t *testing.T) {
+	// Ondalık kredilerde toplama sırası sonucu değiştirebilir.
+	courses := make(map[string]models.Course)
+	var entries []TranscriptEntry
+	grades := []string{"AA", "BA", "CB", "DD", "FF", "BB", "CC"}
+	for i := 0; i < 40; i++ {
+		code := fmt.Sprintf("DRS%02d", i)
+		courses[code] = models.Course{Code: code, Credit: 0.1 * float64(i+1), ECTS: 0.3 * float64(i+1), Semester: "1. Yarıyıl"}
+		entries = append(entries, TranscriptEntry{Code: code, Grade: grades[i%len(grades)]})
+	}
+
+	reg := RegulationFor(2025)
+	first := reg.Calculate(entries, courses)
+	for i := 0; i < 50; i++ {
+		got := reg.Calculate(entries, courses)
+		if got.AGNO != first.AGNO || got.TotalCredits != first.TotalCredits || got.EarnedECTS != first.EarnedECTS {
+			t.Fatalf("run %d: AGNO %v/%v, credits %v/%v, ECTS %v/%v", i,
+				got.AGNO, first.AGNO, got.TotalCredits, first.TotalCredits, got.EarnedECTS, first.EarnedECTS)
+		}
+	}
+}
+
+func TestLimitReason(t *testing.T) {
+	tests := []struct {
+		status StudentStatus
+		lang   string
+		want   string
+	}{
+		{StatusSuccessful, models.LangTR, "Başarılı. Tüm dersleri alabilirsiniz."},
+		{StatusProbation, models.LangEN, "On probation. You can take the new term's courses."},
+		{StatusFailed, "de", "Başarısız. Yeni ZORUNLU ders alamazsınız."},
+	}
+	for _, tt := range tests {
+		if got := LimitReason(tt.status, tt.lang); got != tt.want {
+			t.Errorf("LimitReason(%s, %s) = %q, want %q", tt.status, tt.lang, got, tt.want)
+		}
+	}
+}
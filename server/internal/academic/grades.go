@@ -0,0 +1,18 @@
+package academic
+
+var (
+	passingGrades     = map[string]bool{"AA": true, "BA": true, "BB": true, "CB": true, "CC": true, "G": true, "M": true}
+	conditionalGrades = map[string]bool{"DC": true, "DD": true}
+	failureGrades     = map[string]bool{"FD": true, "FF": true, "NA": true, "DZ": true}
+	attendanceGrades  = map[string]bool{"FD": true, "NA": true, "DZ": true}
+)
+
+// G (geçti) ve M (muaf) dersi tamamlatır ama ortalamaya girmez.
+func IsPassing(grade string) bool           { return passingGrades[grade] }
+func IsConditional(grade string) bool       { return conditionalGrades[grade] }
+func IsFailure(grade string) bool           { return failureGrades[grade] }
+func IsAttendanceFailure(grade string) bool { return attendanceGrades[grade] }
+
+func IsValidGrade(grade string) bool {
+	return passingGrades[grade] || conditionalGrades[grade] || failureGrades[grade]
+}
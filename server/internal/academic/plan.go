@@ -0,0 +1,262 @@
+package academic
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/models"
+)
+
+type Term string
+
+const (
+	TermFall   Term = "fall"
+	TermSpring Term = "spring"
+)
+
+// Önerilen dersin öncelik sınıfı. Küçük değer önce alınır.
+type PlanCategory string
+
+const (
+	CategoryFailed      PlanCategory = "failed"      // Kalınan ders (alttan)
+	CategoryBehind      PlanCategory = "behind"      // Alt yarıyılda hiç alınmamış ders
+	CategoryMandatory   PlanCategory = "mandatory"   // Hedef yarıyılın zorunlu dersi
+	CategoryElective    PlanCategory = "elective"    // Seçmeli ders
+	CategoryImprovement PlanCategory = "improvement" // DC/DD notunu yükseltmek için tekrar
+)
+
+var categoryRank = map[PlanCategory]int{
+	CategoryFailed:      0,
+	CategoryBehind:      1,
+	CategoryMandatory:   2,
+	CategoryElective:    3,
+	CategoryImprovement: 4,
+}
+
+func ParseTerm(s string) (Term, bool) {
+	switch strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(s)) {
+	case "fall", "güz", "guz":
+		return TermFall, true
+	case "spring", "bahar":
+		return TermSpring, true
+	}
+	return "", false
+}
+
+type PlanInput struct {
+	Curriculum     []models.Course
+	ElectiveGroups []models.ElectiveGroup
+	Prerequisites  map[string][]string
+	Transcript     []TranscriptEntry
+	Courses        map[string]models.Course
+	Term           Term
+
+	// Doluysa çakışan dersler önerilmez.
+	Schedule       []ScheduleSlot
+	AvoidConflicts bool
+}
+
+type Recommendation struct {
+	Rank     int           `json:"rank"`
+	Course   models.Course `json:"course"`
+	Category PlanCategory  `json:"category"`
+	Reason   string        `json:"reason"`
+}
+
+type SkippedCourse struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+type PlanResult struct {
+	Status         StudentStatus    `json:"status"`
+	AGNO           float64          `json:"agno"`
+	MaxECTS        float64          `json:"max_ects"`
+	TotalECTS      float64          `json:"total_ects"`
+	TargetSemester int              `json:"target_semester"`
+	Recommended    []Recommendation `json:"recommended"`
+	Skipped        []SkippedCourse  `json:"skipped"`
+}
+
+type candidate struct {
+	course   models.Course
+	semester int
+	category PlanCategory
+	reason   string
+}
+
+// Öğrencinin durumuna, AKTS limitine, ön koşullara ve seçmeli kotalarına göre hedef dönem
+// için alınabilecek dersleri öncelik sırasıyla döner.
+func (r Regulation) Recommend(in PlanInput) PlanResult {
+	gpa := r.Calculate(in.Transcript, in.Courses)
+
+	result := PlanResult{
+		Status:      gpa.Status,
+		AGNO:        gpa.AGNO,
+		MaxECTS:     gpa.MaxECTS,
+		Recommended: []Recommendation{},
+		Skipped:     []SkippedCourse{},
+	}
+
+	parity := 1
+	result.TargetSemester = gpa.TargetFall
+	if in.Term == TermSpring {
+		parity = 0
+		result.TargetSemester = gpa.TargetSpring
+	}
+
+	passed := func(code string) bool {
+		grade, ok := gpa.Grades[code]
+		return ok && r.IsPassed(grade, gpa.AGNO)
+	}
+
+	poolOf := make(map[string]int)
+	poolRemaining := make(map[int]int)
+	for i, g := range in.ElectiveGroups {
+		done := 0
+		for _, code := range g.CourseCodes {
+			poolOf[code] = i
+			if passed(code) {
+				done++
+			}
+		}
+		poolRemaining[i] = g.RequiredCount - done
+	}
+
+	var candidates []candidate
+	for _, c := range in.Curriculum {
+		if c.IsRemoved {
+			continue
+		}
+		sem := ParseSemester(c.Semester)
+		if sem == 0 || sem%2 != parity {
+			continue
+		}
+
+		grade, taken := gpa.Grades[c.Code]
+		cand := candidate{course: c, semester: sem}
+
+		switch {
+		case taken && r.IsPassed(grade, gpa.AGNO):
+			if IsConditional(grade) {
+				cand.category = CategoryImprovement
+				cand.reason = "Şartlı geçilen ders, notu yükseltilebilir (" + grade + ")"
+			} else {
+				continue
+			}
+		case taken:
+			cand.category = CategoryFailed
+			cand.reason = "Kalınan ders (" + grade + "), öncelikle alınmalı"
+		case sem > result.TargetSemester:
+			continue
+		case !c.IsMandatory:
+			cand.category = CategoryElective
+			cand.reason = "Seçmeli ders"
+		case sem < result.TargetSemester:
+			cand.category = CategoryBehind
+			cand.reason = "Alt yarıyılda alınmamış zorunlu ders"
+		case gpa.Status == StatusFailed:
+			result.Skipped = append(result.Skipped, SkippedCourse{
+				Code: c.Code, Reason: "Başarısız öğrenci yeni zorunlu ders alamaz",
+			})
+			continue
+		default:
+			cand.category = CategoryMandatory
+			cand.reason = "Hedef yarıyılın zorunlu dersi"
+		}
+
+		candidates = append(candidates, cand)
+	}
+
+	// Alttan dersler en alt yarıyıldan başlanarak alınır.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if categoryRank[a.category] != categoryRank[b.category] {
+			return categoryRank[a.category] < categoryRank[b.category]
+		}
+		if a.semester != b.semester {
+			return a.semester < b.semester
+		}
+		if a.course.IsMandatory != b.course.IsMandatory {
+			return a.course.IsMandatory
+		}
+		return a.course.Code < b.course.Code
+	})
+
+	slotsByCode := make(map[string][]ScheduleSlot)
+	for _, s := range in.Schedule {
+		slotsByCode[s.Code] = append(slotsByCode[s.Code], s)
+	}
+	var selectedSlots []ScheduleSlot
+
+	for _, cand := range candidates {
+		code := cand.course.Code
+
+		if missing := missingPrerequisites(in.Prerequisites[code], passed); len(missing) > 0 {
+			result.Skipped = append(result.Skipped, SkippedCourse{
+				Code: code, Reason: "Ön koşul sağlanmadı: " + strings.Join(missing, ", "),
+			})
+			continue
+		}
+
+		pool, inPool := poolOf[code]
+		if cand.category == CategoryElective && inPool && poolRemaining[pool] <= 0 {
+			result.Skipped = append(result.Skipped, SkippedCourse{
+				Code: code, Reason: in.ElectiveGroups[pool].Name + " kotası dolu",
+			})
+			continue
+		}
+
+		if result.TotalECTS+cand.course.ECTS > result.MaxECTS {
+			result.Skipped = append(result.Skipped, SkippedCourse{
+				Code: code, Reason: "AKTS limiti aşılıyor",
+			})
+			continue
+		}
+
+		if in.AvoidConflicts {
+			if other, ok := conflictWith(slotsByCode[code], selectedSlots); ok {
+				result.Skipped = append(result.Skipped, SkippedCourse{
+					Code: code, Reason: other + " ile çakışıyor",
+				})
+				continue
+			}
+			selectedSlots = append(selectedSlots, slotsByCode[code]...)
+		}
+
+		if cand.category == CategoryElective && inPool {
+			poolRemaining[pool]--
+		}
+		result.TotalECTS += cand.course.ECTS
+		result.Recommended = append(result.Recommended, Recommendation{
+			Rank:     len(result.Recommended) + 1,
+			Course:   cand.course,
+			Category: cand.category,
+			Reason:   cand.reason,
+		})
+	}
+
+	return result
+}
+
+func missingPrerequisites(prerequisites []string, passed func(string) bool) []string {
+	var missing []string
+	for _, p := range prerequisites {
+		if !passed(p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+func conflictWith(slots, selected []ScheduleSlot) (string, bool) {
+	for _, s := range slots {
+		for _, other := range selected {
+			if s.Overlaps(other) {
+				return other.Code, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,168 @@
+package academic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+type RepeatPolicy string
+
+const (
+	// Tekrar alınan derste son not geçerlidir.
+	RepeatLatest RepeatPolicy = "latest"
+	// Tekrar alınan derste en yüksek not geçerlidir.
+	RepeatBest RepeatPolicy = "best"
+)
+
+// Eğitim-öğretim yönetmeliğinin not ve statü kuralları. Yönetmelik değiştiğinde yeni
+// kayıt eklenir, eski müfredat yılındaki öğrenciler eski kurallara göre hesaplanır.
+type Regulation struct {
+	Name          string             `json:"name"`
+	EffectiveYear int                `json:"effective_year"`
+	GradePoints   map[string]float64 `json:"grade_points"`
+	Repeat        RepeatPolicy       `json:"repeat_policy"`
+
+	// Bu AGNO'nun altında kalan öğrenci sınamalı, üst üste kalırsa başarısız sayılır.
+	ProbationGPA float64 `json:"probation_gpa"`
+	// DC/DD notlarının geçer sayılması için gereken AGNO
+	ConditionalPassGPA float64 `json:"conditional_pass_gpa"`
+	// Mezuniyet için gereken AGNO
+	GraduationGPA float64 `json:"graduation_gpa"`
+
+	BaseECTSLimit   float64 `json:"base_ects_limit"`
+	DebtECTSLimit   float64 `json:"debt_ects_limit"`
+	SeniorECTSLimit float64 `json:"senior_ects_limit"`
+	// Bu yarıyıldan itibaren öğrenci son sınıf limitini kullanır.
+	SeniorSemester int `json:"senior_semester"`
+}
+
+// Yerleşik yönetmelik. LoadRegulations ile yüklenen yönetmelikler buna eklenir; aynı yürürlük
+// yılındaki yönetmelik değiştirilir.
+var defaultRegulations = []Regulation{
+	{
+		Name:          "İÜC Önlisans ve Lisans Eğitim-Öğretim Yönetmeliği",
+		EffectiveYear: 2018,
+		GradePoints: map[string]float64{
+			"AA": 4.0, "BA": 3.5, "BB": 3.0, "CB": 2.5,
+			"CC": 2.0, "DC": 1.5, "DD": 1.0, "FD": 0.0, "FF": 0.0,
+			"NA": 0.0, "DZ": 0.0,
+		},
+		Repeat:             RepeatLatest,
+		ProbationGPA:       1.80,
+		ConditionalPassGPA: 2.00,
+		GraduationGPA:      2.00,
+		BaseECTSLimit:      30,
+		DebtECTSLimit:      45,
+		SeniorECTSLimit:    66,
+		SeniorSemester:     7,
+	},
+}
+
+var (
+	regulationsMu sync.RWMutex
+	regulations   = defaultRegulations
+)
+
+// JSON dizisi olarak verilen yönetmelikleri doğrular ve tanımlı yönetmeliklere ekler.
+// Her yönetmelik bütün kurallarıyla verilmelidir.
+func LoadRegulations(r io.Reader) error {
+	var loaded []Regulation
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&loaded); err != nil {
+		return fmt.Errorf("yönetmelikler okunamadı: %w", err)
+	}
+
+	regulationsMu.Lock()
+	defer regulationsMu.Unlock()
+
+	byYear := make(map[int]Regulation)
+	for _, reg := range regulations {
+		byYear[reg.EffectiveYear] = reg
+	}
+	for _, reg := range loaded {
+		if err := reg.Validate(); err != nil {
+			return err
+		}
+		byYear[reg.EffectiveYear] = reg
+	}
+
+	merged := make([]Regulation, 0, len(byYear))
+	for _, reg := range byYear {
+		merged = append(merged, reg)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].EffectiveYear < merged[j].EffectiveYear })
+	regulations = merged
+	return nil
+}
+
+// Yönetmeliğin eksiksiz ve tutarlı olduğunu denetler.
+func (r Regulation) Validate() error {
+	if r.EffectiveYear <= 0 {
+		return fmt.Errorf("%q yönetmeliğinin yürürlük yılı geçersiz", r.Name)
+	}
+	fail := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%d yönetmeliği: "+format, append([]interface{}{r.EffectiveYear}, args...)...)
+	}
+	if r.Name == "" {
+		return fail("ad boş")
+	}
+	if len(r.GradePoints) == 0 {
+		return fail("not katsayıları boş")
+	}
+	for grade, points := range r.GradePoints {
+		if !IsValidGrade(grade) || points < 0 || points > 4 {
+			return fail("%s notunun katsayısı geçersiz", grade)
+		}
+	}
+	if r.Repeat != RepeatLatest && r.Repeat != RepeatBest {
+		return fail("tekrar kuralı %q geçersiz", r.Repeat)
+	}
+	for _, gpa := range []float64{r.ProbationGPA, r.ConditionalPassGPA, r.GraduationGPA} {
+		if gpa < 0 || gpa > 4 {
+			return fail("AGNO eşiği %.2f geçersiz", gpa)
+		}
+	}
+	if r.BaseECTSLimit <= 0 || r.DebtECTSLimit < r.BaseECTSLimit || r.SeniorECTSLimit < r.BaseECTSLimit {
+		return fail("AKTS limitleri geçersiz")
+	}
+	if r.SeniorSemester <= 0 {
+		return fail("son sınıf yarıyılı geçersiz")
+	}
+	return nil
+}
+
+// Müfredat (giriş) yılında geçerli yönetmeliği döner. Yıl bilinmiyorsa en güncel yönetmelik kullanılır.
+func RegulationFor(year int) Regulation {
+	regulationsMu.RLock()
+	defer regulationsMu.RUnlock()
+
+	selected := regulations[0]
+	for _, r := range regulations {
+		if year <= 0 || r.EffectiveYear <= year {
+			selected = r
+		}
+	}
+	return selected
+}
+
+// Tanımlı yönetmelikleri yürürlük yılına göre sıralı döner.
+func Regulations() []Regulation {
+	regulationsMu.RLock()
+	defer regulationsMu.RUnlock()
+	return append([]Regulation(nil), regulations...)
+}
+
+// Not ortalamaya (AGNO/YANO) dahil ediliyor mu? G ve M dersi tamamlatır ama ortalamaya girmez.
+func (r Regulation) CountsInGPA(grade string) bool {
+	_, ok := r.GradePoints[grade]
+	return ok
+}
+
+// Ders, verilen AGNO ile geçilmiş sayılıyor mu?
+func (r Regulation) IsPassed(grade string, gpa float64) bool {
+	return IsPassing(grade) || (IsConditional(grade) && gpa >= r.ConditionalPassGPA)
+}
@@ -0,0 +1,125 @@
+package academic
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+// Testin yüklediği yönetmelikleri sonunda geri alır.
+func restoreRegulations(t *testing.T) {
+	saved := Regulations()
+	t.Cleanup(func() {
+		regulationsMu.Lock()
+		regulations = saved
+		regulationsMu.Unlock()
+	})
+}
+
+const bestRegulation = `[{
+	"name": "2024 Yönetmeliği",
+	"effective_year": 2024,
+	"grade_points": {"AA": 4, "BA": 3.5, "BB": 3, "CB": 2.5, "CC": 2, "DC": 1.5, "DD": 1, "FF": 0},
+	"repeat_policy": "best",
+	"probation_gpa": 2.0,
+	"conditional_pass_gpa": 2.0,
+	"graduation_gpa": 2.2,
+	"base_ects_limit": 30,
+	"debt_ects_limit": 40,
+	"senior_ects_limit": 60,
+	"senior_semester": 7
+}]`
+
+func TestLoadRegulations(t *testing.T) {
+	restoreRegulations(t)
+
+	if err := LoadRegulations(strings.NewReader(bestRegulation)); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(Regulations()); got != 2 {
+		t.Fatalf("regulations = %d, want 2", got)
+	}
+
+	tests := []struct {
+		year int
+		want int
+	}{
+		{0, 2024},
+		{2015, 2018},
+		{2018, 2018},
+		{2023, 2018},
+		{2024, 2024},
+		{2030, 2024},
+	}
+	for _, tt := range tests {
+		if got := RegulationFor(tt.year).EffectiveYear; got != tt.want {
+			t.Errorf("RegulationFor(%d) = %d, want %d", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestLoadRegulationsInvalid(t *testing.T) {
+	restoreRegulations(t)
+
+	tests := map[string]string{
+		"bozuk JSON":        `[{`,
+		"bilinmeyen alan":   `[{"name": "x", "effective_year": 2024, "ects": 30}]`,
+		"eksik kurallar":    `[{"name": "x", "effective_year": 2024}]`,
+		"geçersiz not":      strings.Replace(bestRegulation, `"FF": 0`, `"XX": 0`, 1),
+		"geçersiz tekrar":   strings.Replace(bestRegulation, `"best"`, `"first"`, 1),
+		"geçersiz limitler": strings.Replace(bestRegulation, `"debt_ects_limit": 40`, `"debt_ects_limit": 20`, 1),
+	}
+	for name, input := range tests {
+		if err := LoadRegulations(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if got := len(Regulations()); got != 1 {
+		t.Errorf("regulations = %d, want only the built-in one", got)
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	courses := map[string]models.Course{
+		"MAT101": {Code: "MAT101", Credit: 4, ECTS: 6, IsMandatory: true, Semester: "1. Yarıyıl"},
+		"FIZ101": {Code: "FIZ101", Credit: 3, ECTS: 5, IsMandatory: true, Semester: "1. Yarıyıl"},
+		"MAT102": {Code: "MAT102", Credit: 4, ECTS: 6, IsMandatory: true, Semester: "2. Yarıyıl"},
+	}
+	transcript := []TranscriptEntry{
+		{Code: "MAT101", Grade: "FF", Term: 1},
+		{Code: "FIZ101", Grade: "DD", Term: 1},
+		{Code: "MAT101", Grade: "BB", Term: 2},
+		{Code: "MAT102", Grade: "CC", Term: 2},
+	}
+
+	reg := RegulationFor(2020)
+	result := reg.Calculate(transcript, courses)
+
+	if len(result.Terms) != 2 {
+		t.Fatalf("terms = %+v", result.Terms)
+	}
+	// 1. dönem: (0*4 + 1*3) / 7
+	if first := result.Terms[0]; math.Abs(first.AGNO-3.0/7) > 1e-9 || first.Status != StatusProbation {
+		t.Errorf("first term = %+v", first)
+	}
+	// Son not geçerli: (3*4 + 1*3 + 2*4) / 11
+	if want := 23.0 / 11; math.Abs(result.AGNO-want) > 1e-9 {
+		t.Errorf("AGNO = %v, want %v", result.AGNO, want)
+	}
+	if result.Status != StatusSuccessful {
+		t.Errorf("status = %s", result.Status)
+	}
+	// AGNO 2'nin üstünde olduğu için DD geçer sayılır.
+	if result.EarnedECTS != 17 {
+		t.Errorf("earned ECTS = %v, want 17", result.EarnedECTS)
+	}
+	// Koşullu not borç sayılır.
+	if result.MaxECTS != reg.DebtECTSLimit {
+		t.Errorf("max ECTS = %v, want %v", result.MaxECTS, reg.DebtECTSLimit)
+	}
+	if result.Grades["MAT101"] != "BB" {
+		t.Errorf("MAT101 grade = %s", result.Grades["MAT101"])
+	}
+}
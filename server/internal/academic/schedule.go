@@ -0,0 +1,58 @@
+package academic
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var clockPattern = regexp.MustCompile(`(\d{1,2})[:.](\d{2})`)
+
+// Dersin haftalık programdaki bir saati
+type ScheduleSlot struct {
+	Code  string `json:"code"`
+	Day   string `json:"day"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+	Room  string `json:"room,omitempty"`
+	// Dersin birden fazla şubesi varsa şube adı (ör. "Grup 1"); boşsa bütün şubeler için ortaktır.
+	Section string `json:"section,omitempty"`
+}
+
+// "09:00" / "9.30" biçimindeki saati gün içindeki dakikaya çevirir. "25:99" gibi geçersiz
+// saatler reddedilir.
+func ParseClock(s string) (int, bool) {
+	m := clockPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, false
+	}
+	h, _ := strconv.Atoi(m[1])
+	minute, _ := strconv.Atoi(m[2])
+	if h > 23 || minute > 59 {
+		return 0, false
+	}
+	return h*60 + minute, true
+}
+
+func (s ScheduleSlot) minutes() (int, int, bool) {
+	start, ok1 := ParseClock(s.Start)
+	end, ok2 := ParseClock(s.End)
+	return start, end, ok1 && ok2 && end > start
+}
+
+// İki saat aynı gün içinde çakışıyor mu?
+func (s ScheduleSlot) Overlaps(other ScheduleSlot) bool {
+	if !strings.EqualFold(
+		strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(s.Day)),
+		strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(other.Day)),
+	) {
+		return false
+	}
+	s1, e1, ok1 := s.minutes()
+	s2, e2, ok2 := other.minutes()
+	if !ok1 || !ok2 {
+		return false
+	}
+	return s1 < e2 && s2 < e1
+}
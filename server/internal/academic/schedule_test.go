@@ -0,0 +1,27 @@
+package academic
+
+import "testing"
+
+func TestParseClock(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"09:00", 540, true},
+		{"9.30", 570, true},
+		{"00:00", 0, true},
+		{"23:59", 1439, true},
+		{"24:00", 0, false},
+		{"25:99", 0, false},
+		{"12:60", 0, false},
+		{"öğle", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseClock(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseClock(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package academic
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	classSemesterPattern = regexp.MustCompile(`(\d+)\s*\.\s*sınıf.*?(güz|bahar)`)
+	termSemesterPattern  = regexp.MustCompile(`(\d+)\s*\.\s*yarıyıl`)
+	digitSemesterPattern = regexp.MustCompile(`^(\d+)$`)
+)
+
+// EBS dönem başlığını yarıyıl numarasına çevirir ("2. Sınıf Bahar" -> 4, "3. Yarıyıl" -> 3).
+// Tanınmayan başlıklar için 0 döner.
+func ParseSemester(semester string) int {
+	lower := strings.TrimSpace(strings.ToLowerSpecial(unicode.TurkishCase, semester))
+	if lower == "" {
+		return 0
+	}
+
+	if m := classSemesterPattern.FindStringSubmatch(lower); m != nil {
+		class, _ := strconv.Atoi(m[1])
+		if m[2] == "güz" {
+			return class*2 - 1
+		}
+		return class * 2
+	}
+
+	if m := termSemesterPattern.FindStringSubmatch(lower); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+
+	if m := digitSemesterPattern.FindStringSubmatch(lower); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+
+	return 0
+}
@@ -0,0 +1,21 @@
+package academic
+
+import "testing"
+
+func TestParseSemester(t *testing.T) {
+	tests := map[string]int{
+		"1. Yarıyıl":      1,
+		"10. YARIYIL":     10,
+		"2. Sınıf Güz":    3,
+		"2. SINIF BAHAR":  4,
+		"1.Sınıf - Bahar": 2,
+		" 7 ":             7,
+		"":                0,
+		"Seçmeli Dersler": 0,
+	}
+	for input, want := range tests {
+		if got := ParseSemester(input); got != want {
+			t.Errorf("ParseSemester(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+const (
+	// Akademik hesap isteklerinin gövde sınırı; birkaç yüz transkript satırı için yeterli
+	maxAcademicBodySize = 64 << 10
+	// Transkript, simülasyon ve program listelerinin en fazla satır sayısı
+	maxAcademicEntries = 300
+)
+
+type auditRequest struct {
+	DepartmentGUID string                     `json:"department_guid"`
+	CurriculumYear int                        `json:"curriculum_year"`
+	Completed      []academic.TranscriptEntry `json:"completed"`
+}
+
+// Bölüm müfredatının derslerini, seçmeli havuzlarını ve bölüm dışından alınan dersleri yükler.
+func (h *Handler) loadCurriculum(db storage.DB, departmentID int, completed []academic.TranscriptEntry) ([]models.Course, []models.ElectiveGroup, map[string]models.Course, error) {
+	courses, err := storage.GetCoursesByDepartmentID(db, departmentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	groups, err := storage.GetElectiveGroupsByDepartmentID(db, departmentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	known := make(map[string]bool, len(courses))
+	for _, c := range courses {
+		known[c.Code] = true
+	}
+	var foreign []string
+	for _, c := range completed {
+		if !known[c.Code] {
+			foreign = append(foreign, c.Code)
+		}
+	}
+
+	external, err := storage.GetCoursesByCodes(db, foreign)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return courses, groups, external, nil
+}
+
+// Müfredat dışı kodla alınan dersler için onaylı eşdeğerliklerden müfredattaki karşılığını bulur.
+func (h *Handler) confirmedEquivalences(db storage.DB, completed []academic.TranscriptEntry, curriculum []models.Course) (map[string]string, error) {
+	inCurriculum := make(map[string]bool, len(curriculum))
+	for _, c := range curriculum {
+		inCurriculum[c.Code] = true
+	}
+
+	var codes []string
+	for _, e := range completed {
+		if !inCurriculum[e.Code] {
+			codes = append(codes, e.Code)
+		}
+	}
+	equivalents, err := storage.GetConfirmedEquivalentsByCodes(db, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, code := range codes {
+		for _, eq := range equivalents[code] {
+			if inCurriculum[eq.EquivalentCode] {
+				result[code] = eq.EquivalentCode
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+// Transkript satırlarının sayısını sınırlar ve harf notlarını doğrular. Boş not kabul edilir.
+// Hata yanıtı yazıldıysa false döner.
+func validTranscript(w http.ResponseWriter, r *http.Request, param string, entries []academic.TranscriptEntry) bool {
+	if len(entries) > maxAcademicEntries {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, param)
+		return false
+	}
+	for _, e := range entries {
+		grade := strings.ToUpper(strings.TrimSpace(e.Grade))
+		if grade != "" && !academic.IsValidGrade(grade) {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidGrade, e.Grade)
+			return false
+		}
+	}
+	return true
+}
+
+// Mezuniyet şartlarına göre öğrencinin ilerlemesini döner.
+func (h *Handler) PostAudit(w http.ResponseWriter, r *http.Request) {
+	var req auditRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAcademicBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	if req.DepartmentGUID == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "department_guid")
+		return
+	}
+	if !validTranscript(w, r, "completed", req.Completed) {
+		return
+	}
+
+	dept, ok := h.departmentByGUID(w, r, req.DepartmentGUID)
+	if !ok {
+		return
+	}
+
+	courses, groups, external, err := h.loadCurriculum(h.db(r), dept.ID, req.Completed)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	equivalences, err := h.confirmedEquivalences(h.db(r), req.Completed, courses)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	result := academic.Audit(academic.AuditInput{
+		Curriculum:     courses,
+		ElectiveGroups: groups,
+		CurriculumYear: req.CurriculumYear,
+		Completed:      req.Completed,
+		External:       external,
+		Equivalences:   equivalences,
+	})
+	respondJSON(w, result)
+}
+
+type gpaRequest struct {
+	DepartmentGUID string                     `json:"department_guid"`
+	CurriculumYear int                        `json:"curriculum_year"`
+	Transcript     []academic.TranscriptEntry `json:"transcript"`
+	WhatIf         []academic.TranscriptEntry `json:"what_if"`
+}
+
+type gpaResponse struct {
+	Current   academic.GPAResult  `json:"current"`
+	Simulated *academic.GPAResult `json:"simulated,omitempty"`
+}
+
+// Transkriptten YANO/AGNO, akademik durum ve AKTS limitini hesaplar. what_if verilirse
+// gerçek transkripti bozmadan simülasyon sonucunu da döner.
+func (h *Handler) PostGPA(w http.ResponseWriter, r *http.Request) {
+	var req gpaRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAcademicBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	if !validTranscript(w, r, "transcript", req.Transcript) || !validTranscript(w, r, "what_if", req.WhatIf) {
+		return
+	}
+
+	all := append(append([]academic.TranscriptEntry{}, req.Transcript...), req.WhatIf...)
+	courses := make(map[string]models.Course)
+
+	if req.DepartmentGUID != "" {
+		dept, ok := h.departmentByGUID(w, r, req.DepartmentGUID)
+		if !ok {
+			return
+		}
+
+		curriculum, _, external, err := h.loadCurriculum(h.db(r), dept.ID, all)
+		if err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+		for code, c := range external {
+			courses[code] = c
+		}
+		for _, c := range curriculum {
+			courses[c.Code] = c
+		}
+	} else {
+		codes := make([]string, 0, len(all))
+		for _, e := range all {
+			codes = append(codes, e.Code)
+		}
+		found, err := storage.GetCoursesByCodes(h.db(r), codes)
+		if err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+		courses = found
+	}
+
+	lang := requestLanguage(r)
+	reg := academic.RegulationFor(req.CurriculumYear)
+	resp := gpaResponse{Current: reg.Calculate(req.Transcript, courses)}
+	resp.Current.LimitReason = academic.LimitReason(resp.Current.Status, lang)
+	if len(req.WhatIf) > 0 {
+		simulated := reg.Calculate(academic.ApplyWhatIf(req.Transcript, req.WhatIf), courses)
+		simulated.LimitReason = academic.LimitReason(simulated.Status, lang)
+		resp.Simulated = &simulated
+	}
+	respondJSON(w, resp)
+}
+
+// Tanımlı yönetmelikler. Öğrenci, giriş (müfredat) yılında yürürlükte olan yönetmeliğe tabidir.
+func (h *Handler) GetRegulations(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, academic.Regulations())
+}
+
+type recommendRequest struct {
+	DepartmentGUID string                     `json:"department_guid"`
+	CurriculumYear int                        `json:"curriculum_year"`
+	Transcript     []academic.TranscriptEntry `json:"transcript"`
+	Term           string                     `json:"term"`
+	Schedule       []academic.ScheduleSlot    `json:"schedule"`
+	AvoidConflicts bool                       `json:"avoid_conflicts"`
+}
+
+// Hedef dönem için alınabilecek dersleri öncelik sırasıyla döner.
+func (h *Handler) PostRecommend(w http.ResponseWriter, r *http.Request) {
+	var req recommendRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAcademicBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	if req.DepartmentGUID == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "department_guid")
+		return
+	}
+	if !validTranscript(w, r, "transcript", req.Transcript) {
+		return
+	}
+	if len(req.Schedule) > maxAcademicEntries {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "schedule")
+		return
+	}
+	for _, s := range req.Schedule {
+		start, ok1 := academic.ParseClock(s.Start)
+		end, ok2 := academic.ParseClock(s.End)
+		if !ok1 || !ok2 || end <= start {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "schedule")
+			return
+		}
+	}
+	term, ok := academic.ParseTerm(req.Term)
+	if !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "term")
+		return
+	}
+
+	dept, ok := h.departmentByGUID(w, r, req.DepartmentGUID)
+	if !ok {
+		return
+	}
+
+	curriculum, groups, external, err := h.loadCurriculum(h.db(r), dept.ID, req.Transcript)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	courses := make(map[string]models.Course, len(curriculum)+len(external))
+	codes := make([]string, 0, len(curriculum))
+	for code, c := range external {
+		courses[code] = c
+	}
+	for _, c := range curriculum {
+		courses[c.Code] = c
+		codes = append(codes, c.Code)
+	}
+
+	prerequisites, err := storage.GetPrerequisites(h.db(r), codes)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	reg := academic.RegulationFor(req.CurriculumYear)
+	result := reg.Recommend(academic.PlanInput{
+		Curriculum:     curriculum,
+		ElectiveGroups: groups,
+		Prerequisites:  prerequisites,
+		Transcript:     req.Transcript,
+		Courses:        courses,
+		Term:           term,
+		Schedule:       req.Schedule,
+		AvoidConflicts: req.AvoidConflicts,
+	})
+	respondJSON(w, result)
+}
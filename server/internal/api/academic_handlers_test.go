@@ -0,0 +1,128 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// n satırlık transkript JSON dizisi
+func transcriptJSON(n int, grade string) string {
+	entries := make([]string, n)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`{"code":"DRS%03d","grade":%q}`, i, grade)
+	}
+	return "[" + strings.Join(entries, ",") + "]"
+}
+
+func TestPostAudit(t *testing.T) {
+	db := testDB(t)
+	if err := storage.SetEquivalenceStatus(db, "MAT101", "MATH101", models.EquivalenceConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	router := SetupRoutes(NewHandler(db), Config{})
+
+	rec := serve(t, router, "POST", "/api/v1/audit", `{"department_guid":"d1","completed":[{"code":"MATH101","grade":"BA"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var result academic.AuditResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Equivalents) != 1 || result.Equivalents[0].CountsAs != "MAT101" {
+		t.Errorf("equivalents = %+v, want MATH101 -> MAT101", result.Equivalents)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		code ErrorCode
+	}{
+		{"geçersiz not", `{"department_guid":"d1","completed":[{"code":"MAT101","grade":"ZZ"}]}`, ErrInvalidGrade},
+		{"çok satır", `{"department_guid":"d1","completed":` + transcriptJSON(maxAcademicEntries+1, "AA") + `}`, ErrInvalidParameter},
+		{"büyük gövde", `{"department_guid":"d1","completed":[{"code":"` + strings.Repeat("x", maxAcademicBodySize) + `"}]}`, ErrInvalidBody},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "POST", "/api/v1/audit", tt.body)
+		var env errorEnvelope
+		json.Unmarshal(rec.Body.Bytes(), &env)
+		if rec.Code != http.StatusBadRequest || env.Error.Code != tt.code {
+			t.Errorf("%s: status = %d, code = %s, want 400 %s", tt.name, rec.Code, env.Error.Code, tt.code)
+		}
+	}
+}
+
+func TestPostGPA(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	rec := serve(t, router, "POST", "/api/v1/gpa?lang=en", `{"transcript":[{"code":"MAT101","grade":"AA"}],"what_if":[{"code":"MAT102","grade":"FF"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var resp gpaResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Current.LimitReason != academic.LimitReason(resp.Current.Status, models.LangEN) || resp.Simulated == nil ||
+		resp.Simulated.LimitReason != academic.LimitReason(resp.Simulated.Status, models.LangEN) {
+		t.Errorf("limit reasons = %+v", resp)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		code ErrorCode
+	}{
+		{"geçersiz not", `{"what_if":[{"code":"MAT101","grade":"A+"}]}`, ErrInvalidGrade},
+		{"çok satır", `{"what_if":` + transcriptJSON(maxAcademicEntries+1, "AA") + `}`, ErrInvalidParameter},
+		{"büyük gövde", `{"transcript":[{"code":"` + strings.Repeat("x", maxAcademicBodySize) + `"}]}`, ErrInvalidBody},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "POST", "/api/v1/gpa", tt.body)
+		var env errorEnvelope
+		json.Unmarshal(rec.Body.Bytes(), &env)
+		if rec.Code != http.StatusBadRequest || env.Error.Code != tt.code {
+			t.Errorf("%s: status = %d, code = %s, want 400 %s", tt.name, rec.Code, env.Error.Code, tt.code)
+		}
+	}
+}
+
+func TestPostRecommendValidation(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	if rec := serve(t, router, "POST", "/api/v1/plan/recommend", `{"department_guid":"d1","term":"fall","schedule":[{"code":"MAT101","day":"Pazartesi","start":"09:00","end":"10:50"}]}`); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	schedule := make([]string, maxAcademicEntries+1)
+	for i := range schedule {
+		schedule[i] = `{"code":"MAT101","day":"Pazartesi","start":"09:00","end":"10:50"}`
+	}
+	tests := []struct {
+		name string
+		body string
+		code ErrorCode
+	}{
+		{"geçersiz saat", `{"department_guid":"d1","term":"fall","schedule":[{"code":"MAT101","day":"Pazartesi","start":"25:99","end":"26:00"}]}`, ErrInvalidParameter},
+		{"ters aralık", `{"department_guid":"d1","term":"fall","schedule":[{"code":"MAT101","day":"Pazartesi","start":"11:00","end":"10:00"}]}`, ErrInvalidParameter},
+		{"çok program satırı", `{"department_guid":"d1","term":"fall","schedule":[` + strings.Join(schedule, ",") + `]}`, ErrInvalidParameter},
+		{"çok transkript satırı", `{"department_guid":"d1","term":"fall","transcript":` + transcriptJSON(maxAcademicEntries+1, "AA") + `}`, ErrInvalidParameter},
+		{"geçersiz not", `{"department_guid":"d1","term":"fall","transcript":[{"code":"MAT101","grade":"X"}]}`, ErrInvalidGrade},
+		{"büyük gövde", `{"department_guid":"d1","term":"` + strings.Repeat("x", maxAcademicBodySize) + `"}`, ErrInvalidBody},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "POST", "/api/v1/plan/recommend", tt.body)
+		var env errorEnvelope
+		json.Unmarshal(rec.Body.Bytes(), &env)
+		if rec.Code != http.StatusBadRequest || env.Error.Code != tt.code {
+			t.Errorf("%s: status = %d, code = %s, want 400 %s", tt.name, rec.Code, env.Error.Code, tt.code)
+		}
+	}
+}
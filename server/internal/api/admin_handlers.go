@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+	"companion_server/internal/tasks"
+)
+
+// Son taramaları (varsayılan 20) yeniden eskiye döner.
+func (h *Handler) GetScrapeRuns(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 || parsed > maxPageSize {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "limit")
+			return
+		}
+		limit = parsed
+	}
+
+	runs, err := storage.GetScrapeRuns(h.db(r), limit)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	respondJSON(w, runs)
+}
+
+// Zamanlanmış taramayı beklemeden EBS taramasını başlatır. Tarama uzun sürdüğü için
+// arka planda çalışır; sonucu tarama geçmişinden izlenir.
+func (h *Handler) PostScrapeRun(w http.ResponseWriter, r *http.Request) {
+	if h.TriggerScrape == nil {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	if !h.TriggerScrape() {
+		respondError(w, r, http.StatusConflict, ErrScrapeRunning)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Eşdeğerlik çiftlerini duruma göre listeler (varsayılan: onay bekleyen adaylar).
+func (h *Handler) GetEquivalences(w http.ResponseWriter, r *http.Request) {
+	status := models.EquivalenceStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "":
+		status = models.EquivalenceCandidate
+	case models.EquivalenceCandidate, models.EquivalenceConfirmed, models.EquivalenceRejected:
+	default:
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "status")
+		return
+	}
+
+	limit := 100
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 || parsed > maxPageSize {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "limit")
+			return
+		}
+		limit = parsed
+	}
+
+	list, err := storage.GetEquivalencesByStatus(h.db(r), status, limit)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if list == nil {
+		list = []models.CourseEquivalence{}
+	}
+	respondJSON(w, list)
+}
+
+// Eşdeğerlik adaylarını yeniden hesaplar.
+func (h *Handler) PostComputeEquivalences(w http.ResponseWriter, r *http.Request) {
+	count, err := tasks.RunEquivalenceScan(r.Context(), h.DB)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	respondJSON(w, computeResult{Candidates: count})
+}
+
+type equivalenceReview struct {
+	Status models.EquivalenceStatus `json:"status"`
+}
+
+// Yönetici bir eşdeğerlik çiftini onaylar ya da reddeder.
+func (h *Handler) PutEquivalence(w http.ResponseWriter, r *http.Request) {
+	code, other := r.PathValue("code"), r.PathValue("other")
+	if code == other {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "other")
+		return
+	}
+
+	var review equivalenceReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	switch review.Status {
+	case models.EquivalenceConfirmed, models.EquivalenceRejected, models.EquivalenceCandidate:
+	default:
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "status")
+		return
+	}
+
+	if err := storage.SetEquivalenceStatus(h.db(r), code, other, review.Status); err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	h.dataChanged()
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestAdminTriggers(t *testing.T) {
+	db := testDB(t)
+	operator, _, err := storage.CreateAPIKey(db, "operatör", models.RoleOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, _, err := storage.CreateAPIKey(db, "okuyucu", models.RoleReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trigger := func() func() bool {
+		running := false
+		return func() bool {
+			if running {
+				return false
+			}
+			running = true
+			return true
+		}
+	}
+	for path, set := range map[string]func(h *Handler, f func() bool){
+		"/api/v1/admin/scrape-runs":       func(h *Handler, f func() bool) { h.TriggerScrape = f },
+		"/api/v1/admin/timetables/ingest": func(h *Handler, f func() bool) { h.TriggerTimetables = f },
+	} {
+		h := NewHandler(db)
+		router := SetupRoutes(h, Config{})
+		if rec := serve(t, router, "POST", path, "", "X-API-Key", operator); rec.Code != http.StatusNotFound {
+			t.Errorf("%s without task: status = %d, want 404", path, rec.Code)
+		}
+
+		set(h, trigger())
+		tests := []struct {
+			key    string
+			status int
+		}{
+			{"", http.StatusUnauthorized},
+			{reader, http.StatusForbidden},
+			{operator, http.StatusAccepted},
+			{operator, http.StatusConflict},
+		}
+		for i, tt := range tests {
+			rec := serve(t, router, "POST", path, "", "X-API-Key", tt.key)
+			if rec.Code != tt.status {
+				t.Errorf("%s request %d: status = %d, want %d", path, i, rec.Code, tt.status)
+			}
+		}
+	}
+}
+
+func TestGetEquivalencesParams(t *testing.T) {
+	db := testDB(t)
+	reader, _, err := storage.CreateAPIKey(db, "okuyucu", models.RoleReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := SetupRoutes(NewHandler(db), Config{})
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusOK},
+		{"?status=confirmed&limit=" + strconv.Itoa(maxPageSize), http.StatusOK},
+		{"?status=onaylı", http.StatusBadRequest},
+		{"?limit=0", http.StatusBadRequest},
+		{"?limit=" + strconv.Itoa(maxPageSize+1), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := serve(t, router, "GET", "/api/v1/admin/equivalences"+tt.query, "", "X-API-Key", reader); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.query, rec.Code, tt.want)
+		}
+	}
+}
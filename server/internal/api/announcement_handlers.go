@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"companion_server/internal/storage"
+)
+
+// Sunucuda toplanan CMS duyurularını döner, en yeni önce. site, category ve since ile
+// filtrelenir; since RFC3339 ya da YYYY-AA-GG biçimindedir.
+func (h *Handler) GetAnnouncements(w http.ResponseWriter, r *http.Request) {
+	opts, badParam := parseListOptions(r, storage.AnnouncementSortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+
+	query := r.URL.Query()
+	q := storage.AnnouncementQuery{ListOptions: opts, Site: query.Get("site")}
+	if v := query.Get("category"); v != "" {
+		category, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "category")
+			return
+		}
+		q.CategoryID = &category
+	}
+	if v := query.Get("since"); v != "" {
+		since, err := parseSince(v)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "since")
+			return
+		}
+		q.Since = &since
+	}
+
+	cursor, err := storage.QueryAnnouncements(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	streamList(w, r, cursor, opts)
+}
+
+func parseSince(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestMain(m *testing.M) {
+	accessLogger = slog.New(slog.NewJSONHandler(io.Discard, nil))
+	os.Exit(m.Run())
+}
+
+// Bir fakülte, bir bölüm ve birkaç dersle kurulmuş bellek içi veritabanı
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertFaculty(db, models.Faculty{ID: 1, GUID: "f1", Name: "Mühendislik", NameEn: "Engineering"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertDepartment(db, models.Department{ID: 10, FacultyID: 1, GUID: "d1", Name: "Bilgisayar", NameEn: "Computer"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []models.Course{
+		{Code: "MAT101", Name: "Matematik I", Credit: 4, ECTS: 6, IsMandatory: true, Semester: "1. Yarıyıl", Year: 2025},
+		{Code: "MAT102", Name: "Matematik II", Credit: 4, ECTS: 6, IsMandatory: true, Semester: "2. Yarıyıl", Year: 2025},
+		{Code: "SEC101", Name: "Felsefe", Credit: 2, ECTS: 3, Semester: "1. Yarıyıl", Year: 2025},
+	} {
+		if err := storage.InsertCourse(db, c, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.InsertCourseDetail(db, models.CourseDetail{BaseInfo: models.Course{Code: "MAT101"}, Aim: "Analiz"}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// İsteği varsayılan ayarlarla kurulan yönlendiriciden geçirir.
+func serve(t *testing.T, h http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDeprecatedSuccessorLink(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	tests := []struct {
+		path string
+		link string
+	}{
+		{"/api/courses?id=d1", `</api/v1/departments/d1/courses>; rel="successor-version"`},
+		{"/api/course-detail?code=MAT101", `</api/v1/courses/MAT101>; rel="successor-version"`},
+		{"/api/departments/d1/electives", `</api/v1/departments/d1/electives>; rel="successor-version"`},
+		{"/api/faculties", `</api/v1/faculties>; rel="successor-version"`},
+		// Parametre eksikse geçersiz adres yerine başlık hiç eklenmez.
+		{"/api/courses", ""},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "GET", tt.path, "")
+		if got := rec.Header().Get("Link"); got != tt.link {
+			t.Errorf("%s Link = %q, want %q", tt.path, got, tt.link)
+		}
+		if rec.Header().Get("Deprecation") != "true" {
+			t.Errorf("%s: missing Deprecation header", tt.path)
+		}
+	}
+}
+
+func TestGetCourseDetailErrors(t *testing.T) {
+	db := testDB(t)
+	router := SetupRoutes(NewHandler(db), Config{})
+
+	if rec := serve(t, router, "GET", "/api/v1/courses/MAT101", ""); rec.Code != http.StatusOK {
+		t.Errorf("existing course: status = %d", rec.Code)
+	}
+	// Detayı olmayan ders
+	if rec := serve(t, router, "GET", "/api/v1/courses/MAT102", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("missing detail: status = %d", rec.Code)
+	}
+
+	// Veritabanı hatası bulunamadı olarak gösterilmez.
+	if _, err := db.Exec("DROP TABLE course_details"); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/courses/SEC101", ""); rec.Code != http.StatusInternalServerError {
+		t.Errorf("db error: status = %d, want 500", rec.Code)
+	}
+}
+
+func TestCachedConditionalRequest(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	rec := serve(t, router, "GET", "/api/v1/departments/d1/courses", "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, etag = %q", rec.Code, etag)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/departments/d1/courses", "", "If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("matching etag: status = %d, want 304", rec.Code)
+	}
+
+	// Olmayan kaynak için istemcinin elindeki sürüm ne olursa olsun 404 döner.
+	missing := serve(t, router, "GET", "/api/v1/departments/yok/courses", "")
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("missing: status = %d", missing.Code)
+	}
+	// Hata yanıtları paylaşılan önbelleklerde tutulmamalı.
+	uncached := func(name string, rec *httptest.ResponseRecorder) {
+		for _, h := range []string{"ETag", "Cache-Control", "Last-Modified"} {
+			if v := rec.Header().Get(h); v != "" {
+				t.Errorf("%s: %s = %q, want none", name, h, v)
+			}
+		}
+	}
+	uncached("missing", missing)
+	for _, header := range [][]string{
+		{"If-None-Match", etag},
+		{"If-None-Match", "*"},
+		{"If-Modified-Since", time.Now().UTC().Format(http.TimeFormat)},
+	} {
+		rec := serve(t, router, "GET", "/api/v1/departments/yok/courses", "", header...)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", header[0], rec.Code)
+		}
+		uncached(header[0], rec)
+	}
+	uncached("bad parameter", serve(t, router, "GET", "/api/v1/departments/d1/courses?limit=-1", ""))
+}
+
+func TestGetCourses(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	tests := []struct {
+		path   string
+		status int
+		codes  string
+		total  string
+	}{
+		{"/api/v1/departments/d1/courses", http.StatusOK, "MAT101,MAT102,SEC101", "3"},
+		{"/api/v1/departments/d1/courses?is_mandatory=true&sort=-semester&limit=1", http.StatusOK, "MAT102", "2"},
+		{"/api/v1/departments/d1/courses?semester=1.%20Yar%C4%B1y%C4%B1l&sort=-ects", http.StatusOK, "MAT101,SEC101", "2"},
+		{"/api/v1/departments/d1/courses?sort=teacher", http.StatusBadRequest, "", ""},
+		{"/api/v1/departments/d1/courses?limit=-1", http.StatusBadRequest, "", ""},
+		{"/api/v1/departments/yok/courses", http.StatusNotFound, "", ""},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "GET", tt.path, "")
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		var body struct {
+			Data []models.Course `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		codes := make([]string, len(body.Data))
+		for i, c := range body.Data {
+			codes[i] = c.Code
+		}
+		if got := strings.Join(codes, ","); got != tt.codes {
+			t.Errorf("%s: codes = %s, want %s", tt.path, got, tt.codes)
+		}
+		if got := rec.Header().Get("X-Total-Count"); got != tt.total {
+			t.Errorf("%s: X-Total-Count = %s, want %s", tt.path, got, tt.total)
+		}
+	}
+}
+
+func TestCacheScopes(t *testing.T) {
+	db := testDB(t)
+	h := NewHandler(db)
+	router := SetupRoutes(h, Config{})
+
+	etags := func() (string, string) {
+		courses := serve(t, router, "GET", "/api/v1/departments/d1/courses", "")
+		announcements := serve(t, router, "GET", "/api/v1/announcements", "")
+		if courses.Code != http.StatusOK || announcements.Code != http.StatusOK {
+			t.Fatalf("status = %d, %d", courses.Code, announcements.Code)
+		}
+		return courses.Header().Get("ETag"), announcements.Header().Get("ETag")
+	}
+	coursesBefore, announcementsBefore := etags()
+
+	// Yeni duyurular katalog ETag'ini değiştirmez.
+	if _, err := storage.BumpVersion(db, storage.AnnouncementVersion); err != nil {
+		t.Fatal(err)
+	}
+	h.Cache.InvalidateScope(storage.AnnouncementVersion)
+	coursesAfter, announcementsAfter := etags()
+	if coursesAfter != coursesBefore {
+		t.Errorf("courses etag changed: %s -> %s", coursesBefore, coursesAfter)
+	}
+	if announcementsAfter == announcementsBefore {
+		t.Errorf("announcements etag unchanged: %s", announcementsAfter)
+	}
+
+	if _, err := storage.BumpDataVersion(db); err != nil {
+		t.Fatal(err)
+	}
+	h.Cache.Invalidate()
+	if coursesLast, announcementsLast := etags(); coursesLast == coursesAfter || announcementsLast != announcementsAfter {
+		t.Errorf("after catalog bump: courses %s -> %s, announcements %s -> %s", coursesAfter, coursesLast, announcementsAfter, announcementsLast)
+	}
+}
+
+func TestGetCourseEquivalents(t *testing.T) {
+	db := testDB(t)
+	if err := storage.SetEquivalenceStatus(db, "MAT101", "MATH101", models.EquivalenceConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	router := SetupRoutes(NewHandler(db), Config{})
+
+	tests := []struct {
+		code   string
+		status int
+		body   string
+	}{
+		{"MAT101", http.StatusOK, "MATH101"},
+		// Eşdeğeri olmayan ders boş liste, bilinmeyen kod 404 alır.
+		{"MAT102", http.StatusOK, "[]"},
+		{"YOK101", http.StatusNotFound, string(ErrCourseNotFound)},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "GET", "/api/v1/courses/"+tt.code+"/equivalents", "")
+		if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+			t.Errorf("%s: status = %d, body = %s", tt.code, rec.Code, rec.Body)
+		}
+	}
+}
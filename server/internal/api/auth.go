@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// Doğrulanan anahtarlar kısa süre bellekte tutulur. CLI ile iptal edilen anahtar en geç
+// bu süre sonunda reddedilir. Geçersiz anahtarlar tutulmaz; uydurulan anahtarlar geçerlileri
+// önbellekten atamasın.
+const (
+	apiKeyCacheTTL  = time.Minute
+	apiKeyCacheSize = 10000
+)
+
+const apiKeyContextKey contextKey = "api_key"
+
+type cachedAPIKey struct {
+	key     *models.APIKey
+	expires time.Time
+}
+
+type apiKeyCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedAPIKey
+}
+
+func newAPIKeyCache() *apiKeyCache {
+	return &apiKeyCache{entries: make(map[string]cachedAPIKey)}
+}
+
+// Anahtar X-API-Key ya da "Authorization: Bearer" başlığıyla gönderilir.
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return ""
+}
+
+// Anahtarı doğrular; geçersiz ya da iptal edilmiş anahtar için nil döner.
+func (h *Handler) authenticate(r *http.Request, key string) (*models.APIKey, error) {
+	hash := storage.HashAPIKey(key)
+	now := time.Now()
+
+	h.keys.mu.Lock()
+	cached, ok := h.keys.entries[hash]
+	h.keys.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.key, nil
+	}
+
+	k, err := storage.AuthenticateAPIKey(h.db(r), key)
+	if err == sql.ErrNoRows {
+		k, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if k != nil {
+		// Son kullanım zamanı önbellek süresi hassasiyetinde tutulur.
+		storage.TouchAPIKey(h.db(r), k.ID)
+		h.keys.put(hash, cachedAPIKey{key: k, expires: now.Add(apiKeyCacheTTL)}, now)
+	}
+	return k, nil
+}
+
+func (c *apiKeyCache) put(hash string, entry cachedAPIKey, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= apiKeyCacheSize {
+		for h, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, h)
+			}
+		}
+		if len(c.entries) >= apiKeyCacheSize {
+			c.entries = make(map[string]cachedAPIKey)
+		}
+	}
+	c.entries[hash] = entry
+}
+
+// Anahtar yakın zamanda doğrulandıysa true; veritabanına gitmez.
+func (h *Handler) knownAPIKey(key string) bool {
+	h.keys.mu.Lock()
+	defer h.keys.mu.Unlock()
+	cached, ok := h.keys.entries[storage.HashAPIKey(key)]
+	return ok && cached.key != nil && time.Now().Before(cached.expires)
+}
+
+// Hız sınırlayıcı için: anahtar geçerliyse istemci anahtarıyla tanınır.
+func (h *Handler) validAPIKey(r *http.Request, key string) bool {
+	k, err := h.authenticate(r, key)
+	return err == nil && k != nil
+}
+
+// Rotaya en az role rolüne sahip bir API anahtarıyla erişilebilir.
+func (h *Handler) requireRole(role models.Role, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := apiKeyFromRequest(r)
+		if key == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			respondError(w, r, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+
+		k, err := h.authenticate(r, key)
+		if err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+		if k == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin", error="invalid_token"`)
+			respondError(w, r, http.StatusUnauthorized, ErrUnauthorized)
+			return
+		}
+		if !k.Role.Allows(role) {
+			respondError(w, r, http.StatusForbidden, ErrForbidden, role)
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey, k)))
+	}
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestRequireRole(t *testing.T) {
+	db := testDB(t)
+	keys := map[models.Role]string{}
+	for _, role := range []models.Role{models.RoleReader, models.RoleOperator, models.RoleAdmin} {
+		key, _, err := storage.CreateAPIKey(db, string(role), role)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[role] = key
+	}
+
+	h := NewHandler(db)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	// Her rol kendi seviyesindeki ve altındaki rotalara erişir.
+	tests := []struct {
+		key      models.Role
+		required models.Role
+		want     int
+	}{
+		{models.RoleReader, models.RoleReader, http.StatusNoContent},
+		{models.RoleReader, models.RoleOperator, http.StatusForbidden},
+		{models.RoleReader, models.RoleAdmin, http.StatusForbidden},
+		{models.RoleOperator, models.RoleReader, http.StatusNoContent},
+		{models.RoleOperator, models.RoleOperator, http.StatusNoContent},
+		{models.RoleOperator, models.RoleAdmin, http.StatusForbidden},
+		{models.RoleAdmin, models.RoleReader, http.StatusNoContent},
+		{models.RoleAdmin, models.RoleOperator, http.StatusNoContent},
+		{models.RoleAdmin, models.RoleAdmin, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		rec := serve(t, h.requireRole(tt.required, ok), "GET", "/", "", "X-API-Key", keys[tt.key])
+		if rec.Code != tt.want {
+			t.Errorf("%s key on %s route: status = %d, want %d", tt.key, tt.required, rec.Code, tt.want)
+		}
+	}
+
+	handler := h.requireRole(models.RoleReader, ok)
+	if rec := serve(t, handler, "GET", "/", "", "Authorization", "Bearer "+keys[models.RoleReader]); rec.Code != http.StatusNoContent {
+		t.Errorf("bearer: status = %d", rec.Code)
+	}
+	if rec := serve(t, handler, "GET", "/", ""); rec.Code != http.StatusUnauthorized || rec.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("no key: status = %d", rec.Code)
+	}
+	if rec := serve(t, handler, "GET", "/", "", "X-API-Key", "icm_uydurma"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("unknown key: status = %d", rec.Code)
+	}
+}
+
+func TestAPIKeyRevocationAfterCacheExpiry(t *testing.T) {
+	db := testDB(t)
+	key, k, err := storage.CreateAPIKey(db, "panel", models.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHandler(db)
+	handler := h.requireRole(models.RoleAdmin, func(w http.ResponseWriter, r *http.Request) {})
+
+	if rec := serve(t, handler, "GET", "/", "", "X-API-Key", key); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if !h.knownAPIKey(key) {
+		t.Error("key not cached after authentication")
+	}
+	if err := storage.RevokeAPIKey(db, k.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// İptal edilen anahtar önbellek süresi dolana kadar geçerli kalır.
+	if rec := serve(t, handler, "GET", "/", "", "X-API-Key", key); rec.Code != http.StatusOK {
+		t.Errorf("cached: status = %d, want 200", rec.Code)
+	}
+
+	hash := storage.HashAPIKey(key)
+	h.keys.mu.Lock()
+	entry := h.keys.entries[hash]
+	entry.expires = time.Now().Add(-time.Second)
+	h.keys.entries[hash] = entry
+	h.keys.mu.Unlock()
+
+	if h.knownAPIKey(key) {
+		t.Error("expired entry still known")
+	}
+	if rec := serve(t, handler, "GET", "/", "", "X-API-Key", key); rec.Code != http.StatusUnauthorized {
+		t.Errorf("after expiry: status = %d, want 401", rec.Code)
+	}
+}
+
+func TestAPIKeyCachePut(t *testing.T) {
+	c := newAPIKeyCache()
+	now := time.Now()
+	for i := 0; i < apiKeyCacheSize; i++ {
+		expires := now.Add(time.Minute)
+		if i%2 == 0 {
+			expires = now.Add(-time.Second)
+		}
+		c.entries[storage.HashAPIKey(string(rune(i)))] = cachedAPIKey{expires: expires}
+	}
+
+	// Dolu önbellekte önce süresi dolanlar atılır.
+	c.put("yeni", cachedAPIKey{expires: now.Add(time.Minute)}, now)
+	if len(c.entries) != apiKeyCacheSize/2+1 {
+		t.Errorf("entries = %d, want %d", len(c.entries), apiKeyCacheSize/2+1)
+	}
+
+	// Süresi dolan kalmadıysa önbellek boşaltılır.
+	for i := 0; len(c.entries) < apiKeyCacheSize; i++ {
+		c.entries["dolu"+string(rune(i))] = cachedAPIKey{expires: now.Add(time.Minute)}
+	}
+	c.put("son", cachedAPIKey{expires: now.Add(time.Minute)}, now)
+	if _, ok := c.entries["son"]; !ok || len(c.entries) != 1 {
+		t.Errorf("entries = %d, want only the new key", len(c.entries))
+	}
+}
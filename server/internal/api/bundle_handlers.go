@@ -0,0 +1,94 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"companion_server/internal/storage"
+)
+
+// Bölümün fakülte, ders, ders detayı ve seçmeli havuz verisini tek pakette döner.
+// Paket içeriğinin özeti version alanında ve ETag başlığında bulunur; istemci
+// If-None-Match ya da ?version= ile elindeki özeti gönderirse 304 döner.
+//
+// Özet ve (sınırdan küçükse) gövde, katalog sürümü değişene kadar bölüm başına
+// önbellekte tutulur; güncel istemciye veritabanına inmeden 304 dönülür.
+func (h *Handler) GetBundle(w http.ResponseWriter, r *http.Request) {
+	guid := r.URL.Query().Get("department")
+	if guid == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "department")
+		return
+	}
+
+	version := h.Cache.Version(storage.CatalogVersion)
+	key := "bundle " + guid
+	cached, ok := h.Cache.get(storage.CatalogVersion, key)
+	if ok && writeBundle(w, r, cached.header.Get("ETag"), cached.body) {
+		return
+	}
+
+	dept, found := h.departmentByGUID(w, r, guid)
+	if !found {
+		return
+	}
+	bundle, err := storage.GetDepartmentBundle(h.db(r), *dept)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	// Özet, version alanı boşken hesaplanır; sürüm değişmediyse önbellekteki özet geçerlidir.
+	if ok {
+		bundle.Version = bundleVersion(cached.header.Get("ETag"))
+	} else {
+		content, err := json.Marshal(bundle)
+		if err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+		sum := sha256.Sum256(content)
+		bundle.Version = hex.EncodeToString(sum[:16])
+	}
+	body, err := json.Marshal(bundle)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	body = append(body, '\n')
+
+	etag := `"` + bundle.Version + `"`
+	resp := cachedResponse{status: http.StatusOK, header: make(http.Header)}
+	resp.header.Set("ETag", etag)
+	if len(body) <= maxCachedBodySize {
+		resp.body = body
+	}
+	h.Cache.put(storage.CatalogVersion, key, version.Version, resp)
+	writeBundle(w, r, etag, body)
+}
+
+// İstemcinin kopyası güncelse 304, değilse gövdeyi yazar. Gövde yoksa (önbellekte
+// sadece özet varsa) hiçbir şey yazmadan false döner.
+func writeBundle(w http.ResponseWriter, r *http.Request, etag string, body []byte) bool {
+	notModified := etagMatches(r.Header.Get("If-None-Match"), etag) || r.URL.Query().Get("version") == bundleVersion(etag)
+	if !notModified && body == nil {
+		return false
+	}
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	// Sıkıştırma, Accept-Encoding'e göre withCompression tarafından yapılır.
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+	return true
+}
+
+func bundleVersion(etag string) string {
+	return strings.Trim(etag, `"`)
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestGetBundle(t *testing.T) {
+	db := testDB(t)
+	h := NewHandler(db)
+	router := SetupRoutes(h, Config{RateLimits: map[string]RateLimit{"GET /api/v1/bundle": defaultRateLimit}})
+
+	rec := serve(t, router, "GET", "/api/v1/bundle?department=d1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var bundle models.Bundle
+	if err := json.Unmarshal(rec.Body.Bytes(), &bundle); err != nil {
+		t.Fatal(err)
+	}
+	etag := rec.Header().Get("ETag")
+	if bundle.Version == "" || etag != `"`+bundle.Version+`"` || len(bundle.Courses) != 3 || len(bundle.Details) != 1 {
+		t.Fatalf("etag = %s, bundle = %+v", etag, bundle)
+	}
+	first := rec.Body.String()
+
+	// Özet ve gövde önbellekten gelir; güncel istemciye veritabanına inmeden 304 dönülür.
+	if _, err := db.Exec("DROP TABLE course_details"); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/bundle?department=d1", "", "If-None-Match", etag); rec.Code != http.StatusNotModified {
+		t.Errorf("If-None-Match: status = %d, want 304", rec.Code)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/bundle?department=d1&version="+bundle.Version, ""); rec.Code != http.StatusNotModified {
+		t.Errorf("version: status = %d, want 304", rec.Code)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/bundle?department=d1", ""); rec.Code != http.StatusOK || rec.Body.String() != first {
+		t.Errorf("cached body: status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(t, router, "GET", "/api/v1/bundle?department=yok", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown department: status = %d, want 404", rec.Code)
+	}
+	if rec := serve(t, router, "GET", "/api/v1/bundle", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("missing department: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestGetBundleVersionChange(t *testing.T) {
+	db := testDB(t)
+	h := NewHandler(db)
+	router := SetupRoutes(h, Config{})
+
+	rec := serve(t, router, "GET", "/api/v1/bundle?department=d1", "")
+	etag := rec.Header().Get("ETag")
+
+	// Büyük paketlerde önbellekte sadece özet tutulur; gövde yeniden üretilir, özet aynı kalır.
+	version := h.Cache.Version(storage.CatalogVersion)
+	digest := cachedResponse{status: http.StatusOK, header: make(http.Header)}
+	digest.header.Set("ETag", etag)
+	h.Cache.put(storage.CatalogVersion, "bundle d1", version.Version, digest)
+	if again := serve(t, router, "GET", "/api/v1/bundle?department=d1", ""); again.Code != http.StatusOK ||
+		again.Header().Get("ETag") != etag || again.Body.String() != rec.Body.String() {
+		t.Errorf("digest only: status = %d, etag = %s", again.Code, again.Header().Get("ETag"))
+	}
+
+	if err := storage.InsertCourse(db, models.Course{Code: "FIZ101", Name: "Fizik"}, 10); err != nil {
+		t.Fatal(err)
+	}
+	h.dataChanged()
+	rec = serve(t, router, "GET", "/api/v1/bundle?department=d1", "", "If-None-Match", etag)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Errorf("after change: status = %d, etag = %s", rec.Code, rec.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,299 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"companion_server/internal/storage"
+)
+
+const (
+	maxCachedResponses = 1000
+	// Bundan büyük yanıtlar (ör. tüm dersler) önbelleğe alınmaz, akış olarak yazılmaya devam eder.
+	maxCachedBodySize = 1 << 20
+	// Başka bir süreç (CLI taraması vb.) veriyi değiştirmiş olabilir, sürüm bu aralıkla yeniden okunur.
+	versionRefreshInterval = 30 * time.Second
+	cacheControl           = "public, max-age=300"
+)
+
+type cachedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Veri sürümüne bağlı, süreç içi yanıt önbelleği. Kayıtlar sürüm kapsamına göre ayrı tutulur;
+// bir kapsamın sürümü değiştiğinde sadece onun kayıtları silinir.
+type ResponseCache struct {
+	db *sql.DB
+
+	mu     sync.Mutex
+	scopes map[storage.VersionScope]*cacheScope
+}
+
+type cacheScope struct {
+	version   storage.DataVersion
+	checkedAt time.Time
+	entries   map[string]cachedResponse
+}
+
+func NewResponseCache(db *sql.DB) *ResponseCache {
+	return &ResponseCache{db: db, scopes: make(map[storage.VersionScope]*cacheScope)}
+}
+
+func (c *ResponseCache) scope(scope storage.VersionScope) *cacheScope {
+	s, ok := c.scopes[scope]
+	if !ok {
+		s = &cacheScope{entries: make(map[string]cachedResponse)}
+		c.scopes[scope] = s
+	}
+	return s
+}
+
+// Kapsamın güncel veri sürümünü döner. Sürüm değişmişse kapsamın önbelleği temizlenir.
+func (c *ResponseCache) Version(scope storage.VersionScope) storage.DataVersion {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.scope(scope)
+	if time.Since(s.checkedAt) < versionRefreshInterval {
+		return s.version
+	}
+
+	v, err := storage.GetVersion(c.db, scope)
+	if err != nil {
+		log.Printf("Veri sürümü okunamadı: %v", err)
+		return s.version
+	}
+	s.checkedAt = time.Now()
+	if v.Version != s.version.Version {
+		s.version = v
+		s.entries = make(map[string]cachedResponse)
+	}
+	return s.version
+}
+
+// Tarama ya da yönetici değişikliği sonrası önbelleği boşaltır ve sürümleri yeniden okutur.
+func (c *ResponseCache) Invalidate() {
+	c.mu.Lock()
+	c.scopes = make(map[storage.VersionScope]*cacheScope)
+	c.mu.Unlock()
+}
+
+// Invalidate'in tek kapsamlı karşılığı; ör. yeni duyurular katalog yanıtlarını silmez.
+func (c *ResponseCache) InvalidateScope(scope storage.VersionScope) {
+	c.mu.Lock()
+	delete(c.scopes, scope)
+	c.mu.Unlock()
+}
+
+func (c *ResponseCache) get(scope storage.VersionScope, key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp, ok := c.scope(scope).entries[key]
+	return resp, ok
+}
+
+func (c *ResponseCache) put(scope storage.VersionScope, key string, version int64, resp cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.scope(scope)
+	// Yanıt hazırlanırken sürüm değiştiyse eski veriyi saklama.
+	if version != s.version.Version {
+		return
+	}
+	if len(s.entries) >= maxCachedResponses {
+		for k := range s.entries {
+			delete(s.entries, k)
+			break
+		}
+	}
+	s.entries[key] = resp
+}
+
+// Yanıtı hem istemciye yazar hem de önbellek için kopyalar. Önbellek başlıkları sadece
+// başarılı yanıta eklenir.
+type teeWriter struct {
+	http.ResponseWriter
+	validators func(http.Header)
+	status     int
+	body       bytes.Buffer
+	overflow   bool
+}
+
+func (t *teeWriter) WriteHeader(status int) {
+	if t.status != 0 {
+		return
+	}
+	t.status = status
+	if status == http.StatusOK {
+		t.validators(t.Header())
+	} else {
+		clearValidators(t.Header())
+	}
+	t.ResponseWriter.WriteHeader(status)
+}
+
+func (t *teeWriter) Write(b []byte) (int, error) {
+	if t.status == 0 {
+		t.WriteHeader(http.StatusOK)
+	}
+	if !t.overflow {
+		if t.body.Len()+len(b) > maxCachedBodySize {
+			t.overflow = true
+			t.body = bytes.Buffer{}
+		} else {
+			t.body.Write(b)
+		}
+	}
+	return t.ResponseWriter.Write(b)
+}
+
+func etagFor(version int64, key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return fmt.Sprintf(`"v%d-%08x"`, version, h.Sum32())
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// GET yanıtlarına ETag/Last-Modified/Cache-Control ekler, koşullu isteklere 304 döner ve
+// başarılı yanıtları kapsamın veri sürümü değişene kadar bellekte tutar. Kapsam boşsa katalog
+// sürümü kullanılır.
+func (h *Handler) cached(scope storage.VersionScope, next http.HandlerFunc) http.HandlerFunc {
+	if scope == "" {
+		scope = storage.CatalogVersion
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		version := h.Cache.Version(scope)
+		key := r.Method + " " + r.URL.RequestURI() + " " + requestLanguage(r)
+		// Mutlak bağlantılar istekten türetiliyorsa sahte Host başlığı başka istemcilerin
+		// yanıtına karışmamalı.
+		if h.PublicURL == "" {
+			key += " " + requestOrigin(r)
+		}
+		etag := etagFor(version.Version, key)
+		validators := func(header http.Header) {
+			header.Set("ETag", etag)
+			header.Set("Cache-Control", cacheControl)
+			if !version.UpdatedAt.IsZero() {
+				header.Set("Last-Modified", version.UpdatedAt.UTC().Format(http.TimeFormat))
+			}
+		}
+		w.Header().Add("Vary", "Accept-Language")
+
+		// Önbellekte sadece başarılı yanıtlar bulunur.
+		if resp, ok := h.Cache.get(scope, key); ok {
+			validators(w.Header())
+			if notModified(r, etag, version) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			for k, v := range resp.header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(resp.status)
+			w.Write(resp.body)
+			return
+		}
+
+		// Kaynak yoksa koşullu istek de 404 almalı; 304 ancak yanıt başarılıysa döner.
+		if notModified(r, etag, version) {
+			buf := &bufferedWriter{header: make(http.Header)}
+			next(buf, r)
+			if buf.status == 0 {
+				buf.status = http.StatusOK
+			}
+			if buf.status == http.StatusOK {
+				if buf.body.Len() <= maxCachedBodySize {
+					h.Cache.put(scope, key, version.Version, cacheable(buf.header, buf.status, buf.body.Bytes()))
+				}
+				validators(w.Header())
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			for k, v := range buf.header {
+				w.Header()[k] = v
+			}
+			clearValidators(w.Header())
+			w.WriteHeader(buf.status)
+			w.Write(buf.body.Bytes())
+			return
+		}
+
+		tee := &teeWriter{ResponseWriter: w, validators: validators}
+		next(tee, r)
+
+		if tee.status == http.StatusOK && !tee.overflow {
+			h.Cache.put(scope, key, version.Version, cacheable(w.Header(), tee.status, tee.body.Bytes()))
+		}
+	}
+}
+
+// If-None-Match ya da If-Modified-Since istemcideki kopyanın güncel olduğunu söylüyorsa true döner.
+func notModified(r *http.Request, etag string, version storage.DataVersion) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagMatches(inm, etag)
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && !version.UpdatedAt.IsZero() {
+		t, err := http.ParseTime(ims)
+		return err == nil && !version.UpdatedAt.Truncate(time.Second).After(t)
+	}
+	return false
+}
+
+// Hata yanıtları ne önbelleğe alınmalı ne de doğrulayıcı taşımalı.
+func clearValidators(h http.Header) {
+	h.Del("ETag")
+	h.Del("Last-Modified")
+	h.Del("Cache-Control")
+}
+
+// Yanıtın önbellekte tutulan başlıklarını ve gövdesinin kopyasını alır.
+func cacheable(h http.Header, status int, body []byte) cachedResponse {
+	header := make(http.Header)
+	for _, k := range []string{"Content-Type", "Content-Language", "X-Total-Count"} {
+		if v := h.Values(k); len(v) > 0 {
+			header[k] = v
+		}
+	}
+	return cachedResponse{status: status, header: header, body: append([]byte(nil), body...)}
+}
+
+// Koşullu isteklerde yanıtı istemciye yazmadan önce durumunu görmek için kullanılır.
+type bufferedWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedWriter) Header() http.Header { return b.header }
+
+func (b *bufferedWriter) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// İstekteki takvim filtrelerini okur; hatalı parametrenin adını döner.
+func calendarQuery(r *http.Request) (storage.CalendarQuery, string) {
+	query := r.URL.Query()
+	q := storage.CalendarQuery{
+		AcademicYear: query.Get("year"),
+		Term:         query.Get("term"),
+		From:         query.Get("from"),
+		To:           query.Get("to"),
+	}
+	switch q.Term {
+	case "", models.TermFall, models.TermSpring, models.TermSummer:
+	default:
+		return q, "term"
+	}
+	if v := query.Get("category"); v != "" {
+		q.Categories = strings.Split(v, ",")
+		for _, c := range q.Categories {
+			if !slices.Contains(models.CalendarCategories, c) {
+				return q, "category"
+			}
+		}
+	}
+	if q.From != "" {
+		if _, err := time.Parse("2006-01-02", q.From); err != nil {
+			return q, "from"
+		}
+	}
+	if q.To != "" {
+		if _, err := time.Parse("2006-01-02", q.To); err != nil {
+			return q, "to"
+		}
+	}
+	return q, ""
+}
+
+// Akademik takvim: kayıt haftaları, ekle-sil, sınav dönemleri ve tatiller.
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	opts, badParam := parseListOptions(r, storage.CalendarSortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+	q, badParam := calendarQuery(r)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+	q.ListOptions = opts
+
+	cursor, err := storage.QueryCalendarEvents(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	streamList(w, r, cursor, opts)
+}
+
+// Google/Apple Takvim'e abone olunabilen akademik takvim. Filtreler /api/v1/calendar ile aynıdır.
+func (h *Handler) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	q, badParam := calendarQuery(r)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+
+	db := h.db(r)
+	events, _, err := storage.ListCalendarEvents(db, q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	// DTSTAMP veri sürümünden alınır; takvim değişmedikçe akış aynı kalır.
+	version, err := storage.GetDataVersion(db)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	cal := icsCalendar{
+		Name:        "İÜC Akademik Takvim",
+		Description: "İstanbul Üniversitesi-Cerrahpaşa akademik takvimi",
+	}
+	for _, e := range events {
+		start, _ := time.Parse("2006-01-02", e.StartDate)
+		end, _ := time.Parse("2006-01-02", e.EndDate)
+		cal.Events = append(cal.Events, icsEvent{
+			UID:        e.UID,
+			Summary:    e.Title,
+			Categories: []string{e.Category},
+			Start:      start,
+			End:        end,
+			AllDay:     true,
+			Stamp:      version.UpdatedAt,
+		})
+	}
+	writeICS(w, cal)
+}
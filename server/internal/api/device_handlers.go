@@ -0,0 +1,155 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+const (
+	maxDeviceBodySize = 16 << 10
+	// Konu türü başına en fazla abonelik
+	maxSubscriptions = 50
+)
+
+type deviceRequest struct {
+	Token    string          `json:"token" doc:"FCM/APNs push jetonu"`
+	Platform models.Platform `json:"platform"`
+	// Bildirim dili; verilmezse isteğin dili kullanılır.
+	Language      string               `json:"language,omitempty"`
+	Subscriptions models.Subscriptions `json:"subscriptions"`
+}
+
+// İstek gövdesini okur ve doğrular; hata yanıtını kendisi yazar.
+func (h *Handler) readDeviceRequest(w http.ResponseWriter, r *http.Request) (*models.Device, bool) {
+	var req deviceRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDeviceBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return nil, false
+	}
+	if req.Token == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "token")
+		return nil, false
+	}
+	if !req.Platform.Valid() {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "platform")
+		return nil, false
+	}
+	switch req.Language {
+	case "":
+		req.Language = requestLanguage(r)
+	case models.LangTR, models.LangEN:
+	default:
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "language")
+		return nil, false
+	}
+
+	subs := req.Subscriptions
+	if len(subs.Departments) > maxSubscriptions || len(subs.Faculties) > maxSubscriptions || len(subs.Sites) > maxSubscriptions {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "subscriptions")
+		return nil, false
+	}
+	db := h.db(r)
+	for _, guid := range subs.Departments {
+		if _, err := storage.GetDepartmentByGUID(db, guid); err == sql.ErrNoRows {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "subscriptions.departments")
+			return nil, false
+		} else if err != nil {
+			respondInternalError(w, r, err)
+			return nil, false
+		}
+	}
+	if len(subs.Faculties) > 0 {
+		ids := uniqueInts(subs.Faculties)
+		found, _, err := storage.ListFaculties(db, storage.FacultyQuery{IDs: ids})
+		if err != nil {
+			respondInternalError(w, r, err)
+			return nil, false
+		}
+		if len(found) != len(ids) {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "subscriptions.faculties")
+			return nil, false
+		}
+	}
+	for _, site := range subs.Sites {
+		if site == "" || len(site) > 100 {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "subscriptions.sites")
+			return nil, false
+		}
+	}
+
+	return &models.Device{Token: req.Token, Platform: req.Platform, Language: req.Language, Subscriptions: subs}, true
+}
+
+// Cihazı bildirimler için kaydeder. Aynı jetonla tekrar kayıt mevcut cihazı günceller
+// ve aynı id'yi döner.
+func (h *Handler) PostDevice(w http.ResponseWriter, r *http.Request) {
+	device, ok := h.readDeviceRequest(w, r)
+	if !ok {
+		return
+	}
+	if err := storage.SaveDevice(h.db(r), device); err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	h.respondDevice(w, r, device.ID, http.StatusCreated)
+}
+
+// Cihazın jetonunu, dilini ve aboneliklerini verilenlerle değiştirir.
+func (h *Handler) PutDevice(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := storage.GetDevice(h.db(r), id); err == sql.ErrNoRows {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	} else if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	device, ok := h.readDeviceRequest(w, r)
+	if !ok {
+		return
+	}
+	device.ID = id
+	if err := storage.SaveDevice(h.db(r), device); err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	h.respondDevice(w, r, id, http.StatusOK)
+}
+
+func (h *Handler) GetDevice(w http.ResponseWriter, r *http.Request) {
+	h.respondDevice(w, r, r.PathValue("id"), http.StatusOK)
+}
+
+// Cihazın kaydını ve bütün aboneliklerini siler.
+func (h *Handler) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	deleted, err := storage.DeleteDevice(h.db(r), r.PathValue("id"))
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if !deleted {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondDevice(w http.ResponseWriter, r *http.Request, id string, status int) {
+	device, err := storage.GetDevice(h.db(r), id)
+	if err == sql.ErrNoRows {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(device)
+}
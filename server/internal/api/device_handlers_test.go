@@ -0,0 +1,57 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestReadDeviceRequest(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+	manyDepartments := `["d1"` + strings.Repeat(`,"d1"`, maxSubscriptions) + `]`
+
+	tests := []struct {
+		name string
+		body string
+		want int
+		code ErrorCode
+	}{
+		{"geçerli", `{"token":"t1","platform":"android","subscriptions":{"departments":["d1"],"faculties":[1,1],"sites":["bm"]}}`, http.StatusCreated, ""},
+		{"bozuk gövde", `{"token":`, http.StatusBadRequest, ErrInvalidBody},
+		{"çok büyük gövde", `{"token":"` + strings.Repeat("x", maxDeviceBodySize) + `"}`, http.StatusBadRequest, ErrInvalidBody},
+		{"jeton yok", `{"platform":"ios"}`, http.StatusBadRequest, ErrMissingParameter},
+		{"bilinmeyen platform", `{"token":"t2","platform":"symbian"}`, http.StatusBadRequest, ErrInvalidParameter},
+		{"bilinmeyen dil", `{"token":"t2","platform":"ios","language":"de"}`, http.StatusBadRequest, ErrInvalidParameter},
+		{"bilinmeyen bölüm", `{"token":"t2","platform":"ios","subscriptions":{"departments":["yok"]}}`, http.StatusBadRequest, ErrInvalidParameter},
+		{"bilinmeyen fakülte", `{"token":"t2","platform":"ios","subscriptions":{"faculties":[1,99]}}`, http.StatusBadRequest, ErrInvalidParameter},
+		{"boş site", `{"token":"t2","platform":"web","subscriptions":{"sites":[""]}}`, http.StatusBadRequest, ErrInvalidParameter},
+		{"çok fazla abonelik", `{"token":"t2","platform":"web","subscriptions":{"departments":` + manyDepartments + `}}`, http.StatusBadRequest, ErrInvalidParameter},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "POST", "/api/v1/devices", tt.body)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d (%s)", tt.name, rec.Code, tt.want, rec.Body)
+			continue
+		}
+		if tt.code != "" {
+			var env errorEnvelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil || env.Error.Code != tt.code {
+				t.Errorf("%s: error = %s, want %s", tt.name, rec.Body, tt.code)
+			}
+		}
+	}
+
+	// Dil verilmezse isteğin dili kullanılır.
+	rec := serve(t, router, "POST", "/api/v1/devices", `{"token":"t3","platform":"ios"}`, "Accept-Language", "en-US")
+	var device models.Device
+	if err := json.Unmarshal(rec.Body.Bytes(), &device); err != nil || device.Language != models.LangEN {
+		t.Errorf("language = %q, want en (%s)", device.Language, rec.Body)
+	}
+
+	if rec := serve(t, router, "PUT", "/api/v1/devices/yok", `{"token":"t4","platform":"ios"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT unknown device: status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Makine tarafından okunabilir hata kodu
+type ErrorCode string
+
+const (
+	ErrInvalidBody        ErrorCode = "invalid_body"
+	ErrMissingParameter   ErrorCode = "missing_parameter"
+	ErrInvalidParameter   ErrorCode = "invalid_parameter"
+	ErrInvalidGrade       ErrorCode = "invalid_grade"
+	ErrDepartmentNotFound ErrorCode = "department_not_found"
+	ErrCourseNotFound     ErrorCode = "course_not_found"
+	ErrNotFound           ErrorCode = "not_found"
+	ErrMethodNotAllowed   ErrorCode = "method_not_allowed"
+	ErrInternal           ErrorCode = "internal_error"
+	ErrTimeout            ErrorCode = "timeout"
+	ErrRateLimited        ErrorCode = "rate_limited"
+	ErrUnauthorized       ErrorCode = "unauthorized"
+	ErrForbidden          ErrorCode = "forbidden"
+	ErrScrapeRunning      ErrorCode = "scrape_running"
+	ErrIngestRunning      ErrorCode = "ingest_running"
+	ErrSectionRequired    ErrorCode = "section_required"
+)
+
+// Hata kodlarının dillere göre mesajları. %s yer tutucuları respondError'a verilen argümanlarla doldurulur.
+var errorMessages = map[ErrorCode]map[string]string{
+	ErrInvalidBody: {
+		"tr": "Geçersiz istek gövdesi",
+		"en": "Invalid request body",
+	},
+	ErrMissingParameter: {
+		"tr": "%s parametresi zorunludur",
+		"en": "%s parameter is required",
+	},
+	ErrInvalidParameter: {
+		"tr": "Geçersiz %s parametresi",
+		"en": "Invalid %s parameter",
+	},
+	ErrInvalidGrade: {
+		"tr": "Geçersiz harf notu: %s",
+		"en": "Invalid letter grade: %s",
+	},
+	ErrDepartmentNotFound: {
+		"tr": "Bölüm bulunamadı",
+		"en": "Department not found",
+	},
+	ErrCourseNotFound: {
+		"tr": "Ders ya da ders detayı bulunamadı",
+		"en": "Course or course detail not found",
+	},
+	ErrNotFound: {
+		"tr": "İstenen kaynak bulunamadı",
+		"en": "The requested resource was not found",
+	},
+	ErrMethodNotAllowed: {
+		"tr": "Bu HTTP metodu desteklenmiyor",
+		"en": "HTTP method not allowed",
+	},
+	ErrInternal: {
+		"tr": "Sunucuda bir sıkıntı yaşandı",
+		"en": "Internal server error",
+	},
+	ErrTimeout: {
+		"tr": "İstek zaman aşımına uğradı",
+		"en": "Request timed out",
+	},
+	ErrRateLimited: {
+		"tr": "Çok fazla istek gönderildi, lütfen daha sonra tekrar deneyin",
+		"en": "Too many requests, please try again later",
+	},
+	ErrUnauthorized: {
+		"tr": "Geçerli bir API anahtarı gerekli",
+		"en": "A valid API key is required",
+	},
+	ErrForbidden: {
+		"tr": "Bu işlem için %s rolü gerekli",
+		"en": "This operation requires the %s role",
+	},
+	ErrScrapeRunning: {
+		"tr": "Bir tarama zaten sürüyor",
+		"en": "A scrape is already running",
+	},
+	ErrIngestRunning: {
+		"tr": "Bir ders programı alımı zaten sürüyor",
+		"en": "A timetable ingest is already running",
+	},
+	ErrSectionRequired: {
+		"tr": "%s dersinin birden fazla şubesi var, şube seçilmelidir",
+		"en": "%s has more than one section; a section must be chosen",
+	},
+}
+
+type apiError struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+}
+
+type errorEnvelope struct {
+	Error apiError `json:"error"`
+}
+
+// İstek dilini belirler: önce ?lang=, sonra Accept-Language. Varsayılan Türkçe.
+func requestLanguage(r *http.Request) string {
+	if lang := strings.ToLower(r.URL.Query().Get("lang")); lang == "en" || lang == "tr" {
+		return lang
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if strings.HasPrefix(tag, "tr") {
+			return "tr"
+		}
+		if strings.HasPrefix(tag, "en") {
+			return "en"
+		}
+	}
+	return "tr"
+}
+
+func errorMessage(code ErrorCode, lang string, args ...interface{}) string {
+	messages, ok := errorMessages[code]
+	if !ok {
+		messages = errorMessages[ErrInternal]
+	}
+	msg, ok := messages[lang]
+	if !ok {
+		msg = messages["tr"]
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Tüm hatalar aynı JSON zarfıyla döner: {"error": {"code": "...", "message": "..."}}
+func respondError(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, args ...interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorEnvelope{
+		Error: apiError{Code: code, Message: errorMessage(code, requestLanguage(r), args...)},
+	})
+}
+
+// İç hatayı loglar, istemciye ayrıntı sızdırmadan 500 döner. Rota süresi dolduysa 503 döner.
+func respondInternalError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("[%s] %s %s: %v", requestID(r), r.Method, logPath(r), err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		respondError(w, r, http.StatusServiceUnavailable, ErrTimeout)
+		return
+	}
+	respondError(w, r, http.StatusInternalServerError, ErrInternal)
+}
+
+// ServeMux'un kendi ürettiği düz metin 404/405 yanıtlarını yakalamak için
+type statusCapture struct {
+	header http.Header
+	status int
+}
+
+func (c *statusCapture) Header() http.Header         { return c.header }
+func (c *statusCapture) Write(b []byte) (int, error) { return len(b), nil }
+func (c *statusCapture) WriteHeader(status int)      { c.status = status }
+
+// Eşleşen rota yoksa mux'un yanıtını JSON hata zarfına çevirir.
+func withJSONFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		capture := &statusCapture{header: make(http.Header)}
+		mux.ServeHTTP(capture, r)
+
+		switch capture.status {
+		case http.StatusMethodNotAllowed:
+			w.Header().Set("Allow", capture.header.Get("Allow"))
+			respondError(w, r, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+		case http.StatusNotFound, 0:
+			respondError(w, r, http.StatusNotFound, ErrNotFound)
+		default:
+			// Yönlendirme vb. durumlar mux'a bırakılır.
+			mux.ServeHTTP(w, r)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// Sitenin duyuru akışı: /feeds/announcements/{site}.atom, .rss ya da .json.
+// category ile tek kategoriye daraltılabilir.
+func (h *Handler) GetAnnouncementFeed(w http.ResponseWriter, r *http.Request) {
+	site, format, ok := splitFeedName(r.PathValue("file"))
+	if !ok {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	q := storage.AnnouncementQuery{ListOptions: storage.ListOptions{Limit: feedSize}, Site: site}
+	link := "/api/v1/announcements?site=" + url.QueryEscape(site)
+	if v := r.URL.Query().Get("category"); v != "" {
+		category, err := strconv.Atoi(v)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "category")
+			return
+		}
+		q.CategoryID = &category
+		link += "&category=" + v
+	}
+
+	announcements, _, err := storage.ListAnnouncements(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	f := feed{
+		Title:       "İÜC duyuruları: " + site,
+		Description: "İstanbul Üniversitesi-Cerrahpaşa " + site + " sitesinin duyuruları",
+		SelfURL:     h.absoluteURL(r, r.URL.RequestURI()),
+		Link:        h.absoluteURL(r, link),
+	}
+	for _, a := range announcements {
+		f.Items = append(f.Items, feedItem{
+			ID:      "urn:iuc-companion:announcement:" + a.Site + ":" + a.ExternalID,
+			Title:   a.Title,
+			Link:    a.URL,
+			Updated: a.PublishedAt,
+		})
+	}
+	writeFeed(w, f, format)
+}
+
+// Bölümün müfredat değişiklikleri: /feeds/departments/{guid}/changes.rss, .atom ya da .json.
+func (h *Handler) GetDepartmentChangesFeed(w http.ResponseWriter, r *http.Request) {
+	name, format, ok := splitFeedName(r.PathValue("file"))
+	if !ok || name != "changes" {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	dept, ok := h.departmentByGUID(w, r, r.PathValue("guid"))
+	if !ok {
+		return
+	}
+	changes, err := storage.GetDepartmentChanges(h.db(r), dept.ID, feedSize)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	lang := requestLanguage(r)
+	localized := dept.Localize(lang)
+	f := feed{
+		SelfURL: h.absoluteURL(r, r.URL.RequestURI()),
+		Link:    h.absoluteURL(r, "/api/v1/departments/"+url.PathEscape(dept.GUID)+"/courses"),
+	}
+	if lang == models.LangEN {
+		f.Title = localized.Name + " curriculum changes"
+		f.Description = "Courses added, removed or updated in the " + localized.Name + " curriculum"
+	} else {
+		f.Title = localized.Name + " müfredat değişiklikleri"
+		f.Description = localized.Name + " müfredatına eklenen, kaldırılan ve güncellenen dersler"
+	}
+
+	for _, c := range changes {
+		f.Items = append(f.Items, feedItem{
+			ID:      "urn:iuc-companion:change:" + strconv.FormatInt(c.ID, 10),
+			Title:   c.Describe(lang),
+			Link:    h.absoluteURL(r, "/api/v1/courses/"+url.PathEscape(c.CourseCode)),
+			Updated: c.ChangedAt,
+		})
+	}
+	writeFeed(w, f, format)
+}
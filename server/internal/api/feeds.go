@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Akışlara yazılan en fazla kayıt
+const feedSize = 50
+
+// Atom, akış düzeyinde yazar ister.
+const feedAuthor = "IUC Companion"
+
+// Biçimden bağımsız akış; Atom, RSS 2.0 ve JSON Feed olarak yazılabilir.
+type feed struct {
+	Title       string
+	Description string
+	// Akışın kendi adresi ve içeriğin insan/istemci tarafından okunabilir karşılığı
+	SelfURL string
+	Link    string
+	Updated time.Time
+	Items   []feedItem
+}
+
+type feedItem struct {
+	ID      string
+	Title   string
+	Link    string
+	Summary string
+	Updated time.Time
+}
+
+type feedFormat string
+
+const (
+	feedAtom feedFormat = "atom"
+	feedRSS  feedFormat = "rss"
+	feedJSON feedFormat = "json"
+)
+
+// "abc.atom" -> ("abc", atom). Uzantı tanınmazsa ok false döner.
+func splitFeedName(file string) (name string, format feedFormat, ok bool) {
+	dot := strings.LastIndexByte(file, '.')
+	if dot <= 0 {
+		return "", "", false
+	}
+	format = feedFormat(file[dot+1:])
+	switch format {
+	case feedAtom, feedRSS, feedJSON:
+		return file[:dot], format, true
+	}
+	return "", "", false
+}
+
+// Yolun mutlak adresi; akışlar okuyuculara mutlak bağlantı vermek zorundadır. Adres
+// PublicURL'den kurulur, verilmemişse isteğin Host başlığından türetilir.
+func (h *Handler) absoluteURL(r *http.Request, path string) string {
+	if h.PublicURL != "" {
+		return strings.TrimSuffix(h.PublicURL, "/") + path
+	}
+	return requestOrigin(r) + path
+}
+
+func requestOrigin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func writeFeed(w http.ResponseWriter, f feed, format feedFormat) {
+	if f.Updated.IsZero() {
+		for _, item := range f.Items {
+			if item.Updated.After(f.Updated) {
+				f.Updated = item.Updated
+			}
+		}
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+
+	switch format {
+	case feedAtom:
+		w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+		writeXML(w, atomFeedOf(f))
+	case feedRSS:
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		writeXML(w, rssFeedOf(f))
+	default:
+		w.Header().Set("Content-Type", "application/feed+json")
+		json.NewEncoder(w).Encode(jsonFeedOf(f))
+	}
+}
+
+func writeXML(w io.Writer, v interface{}) {
+	io.WriteString(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	enc.Encode(v)
+	io.WriteString(w, "\n")
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Link    atomLink `xml:"link"`
+	Summary string   `xml:"summary,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   string      `xml:"author>name"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+func atomFeedOf(f feed) atomFeed {
+	out := atomFeed{
+		ID:       f.SelfURL,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Author:   feedAuthor,
+		Links:    []atomLink{{Rel: "self", Href: f.SelfURL}, {Rel: "alternate", Href: f.Link}},
+	}
+	for _, item := range f.Items {
+		out.Entries = append(out.Entries, atomEntry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: item.Updated.UTC().Format(time.RFC3339),
+			Link:    atomLink{Href: item.Link},
+			Summary: item.Summary,
+		})
+	}
+	return out
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		LastBuildDate string    `xml:"lastBuildDate"`
+		Items         []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+func rssFeedOf(f feed) rssFeed {
+	out := rssFeed{Version: "2.0"}
+	out.Channel.Title = f.Title
+	out.Channel.Link = f.Link
+	out.Channel.Description = f.Description
+	out.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	for _, item := range f.Items {
+		out.Channel.Items = append(out.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			GUID:        rssGUID{Value: item.ID},
+			PubDate:     item.Updated.UTC().Format(time.RFC1123Z),
+		})
+	}
+	return out
+}
+
+// JSON Feed 1.1 (https://jsonfeed.org/version/1.1)
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url,omitempty"`
+	Title         string `json:"title"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+func jsonFeedOf(f feed) jsonFeed {
+	out := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		Description: f.Description,
+		HomePageURL: f.Link,
+		FeedURL:     f.SelfURL,
+		Items:       []jsonFeedItem{},
+	}
+	for _, item := range f.Items {
+		text := item.Summary
+		if text == "" {
+			text = item.Title
+		}
+		out.Items = append(out.Items, jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			ContentText:   text,
+			DatePublished: item.Updated.UTC().Format(time.RFC3339),
+		})
+	}
+	return out
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFeedLinksIgnoreForgedHost(t *testing.T) {
+	for _, publicURL := range []string{"https://api.example", ""} {
+		h := NewHandler(testDB(t))
+		h.PublicURL = publicURL
+		router := SetupRoutes(h, Config{})
+
+		forged := httptest.NewRequest("GET", "/feeds/announcements/fen.json", nil)
+		forged.Host = "evil.example"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, forged)
+
+		if publicURL != "" && strings.Contains(rec.Body.String(), "evil.example") {
+			t.Errorf("public URL set: feed uses forged host: %s", rec.Body.String())
+		}
+
+		// Sonraki istemci sahte adresli önbellek kaydını almamalı.
+		rec = serve(t, router, "GET", "/feeds/announcements/fen.json", "")
+		if strings.Contains(rec.Body.String(), "evil.example") {
+			t.Errorf("public URL %q: cached feed uses forged host: %s", publicURL, rec.Body.String())
+		}
+		if publicURL != "" && !strings.Contains(rec.Body.String(), publicURL+"/api/v1/announcements?site=fen") {
+			t.Errorf("feed link missing public URL: %s", rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,456 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"companion_server/internal/graphql"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+const (
+	graphqlMaxDepth      = 8
+	graphqlMaxComplexity = 10000
+	maxGraphQLBodySize   = 64 << 10
+)
+
+// Liste alanlarının karmaşıklık hesabındaki tahmini eleman sayıları
+const (
+	facultyListSize    = 20
+	departmentListSize = 300
+	facultyDeptSize    = 20
+	courseListSize     = 60
+)
+
+// Üst nesneler listeden gelince değer, tekil sorgudan gelince işaretçi olur.
+func deref[T any](v interface{}) T {
+	switch x := v.(type) {
+	case T:
+		return x
+	case *T:
+		return *x
+	}
+	var zero T
+	return zero
+}
+
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	result := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func argString(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return s
+}
+
+func argInt(args map[string]interface{}, name string) int {
+	n, _ := args[name].(int)
+	return n
+}
+
+func argIntPtr(args map[string]interface{}, name string) *int {
+	if n, ok := args[name].(int); ok {
+		return &n
+	}
+	return nil
+}
+
+func argBoolPtr(args map[string]interface{}, name string) *bool {
+	if b, ok := args[name].(bool); ok {
+		return &b
+	}
+	return nil
+}
+
+func pageArgs() map[string]graphql.Arg {
+	return map[string]graphql.Arg{
+		"limit":  {Type: "Int"},
+		"offset": {Type: "Int"},
+	}
+}
+
+// İç içe listelerin üst nesne başına sayfalaması; after önceki sayfanın son öğesinin anahtarıdır.
+func cursorArgs(key string) map[string]graphql.Arg {
+	return map[string]graphql.Arg{
+		"first": {Type: "Int", Description: "Üst nesne başına en fazla öğe sayısı"},
+		"after": {Type: "String", Description: "Bu " + key + " değerinden sonraki öğeler"},
+	}
+}
+
+// first ve after argümanlarının üst nesne başına sayfası; sayfalama SQL'de yapılır.
+func parentPage(args map[string]interface{}) *storage.ParentPage {
+	return &storage.ParentPage{First: argInt(args, "first"), After: argString(args, "after")}
+}
+
+func validateFirst(args map[string]interface{}) error {
+	if first := argInt(args, "first"); first < 0 || first > maxPageSize {
+		return graphql.Errorf("first 0 ile %d arasında olmalı", maxPageSize)
+	}
+	return nil
+}
+
+func courseFilterArgs() map[string]graphql.Arg {
+	return map[string]graphql.Arg{
+		"semester":     {Type: "String"},
+		"is_mandatory": {Type: "Boolean"},
+		"is_removed":   {Type: "Boolean"},
+		"year":         {Type: "Int"},
+	}
+}
+
+func listOptions(args map[string]interface{}) (storage.ListOptions, error) {
+	opts := storage.ListOptions{Limit: argInt(args, "limit"), Offset: argInt(args, "offset")}
+	if opts.Limit < 0 || opts.Limit > maxPageSize {
+		return opts, graphql.Errorf("limit 0 ile %d arasında olmalı", maxPageSize)
+	}
+	if opts.Offset < 0 {
+		return opts, graphql.Errorf("offset negatif olamaz")
+	}
+	return opts, nil
+}
+
+func courseQuery(args map[string]interface{}) storage.CourseQuery {
+	return storage.CourseQuery{
+		Semester:    argString(args, "semester"),
+		IsMandatory: argBoolPtr(args, "is_mandatory"),
+		IsRemoved:   argBoolPtr(args, "is_removed"),
+		Year:        argIntPtr(args, "year"),
+	}
+}
+
+// Kök alanlar üst nesne kullanmaz; tek değeri döner.
+func rootResolver(resolve func(ctx context.Context, args map[string]interface{}) (interface{}, error)) graphql.Resolver {
+	return func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+		v, err := resolve(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{v}, nil
+	}
+}
+
+// Fakülte, bölüm, ders ve ders detaylarının GraphQL şeması. İç içe alanlar aynı seviyedeki
+// tüm üst nesneler için tek sorguyla yüklenir.
+func (h *Handler) newGraphQLSchema() *graphql.Schema {
+	faculty := &graphql.Object{Name: "Faculty", Description: "Fakülte", Fields: graphql.ScalarFields(models.Faculty{})}
+	department := &graphql.Object{Name: "Department", Description: "Bölüm", Fields: graphql.ScalarFields(models.Department{})}
+	course := &graphql.Object{Name: "Course", Description: "Ders", Fields: graphql.ScalarFields(models.Course{})}
+	detail := &graphql.Object{Name: "CourseDetail", Description: "Ders izlencesi", Fields: graphql.ScalarFields(models.CourseDetail{})}
+
+	faculty.Fields["departments"] = &graphql.Field{
+		Type: "[Department!]!", Object: department, List: true, Size: facultyDeptSize,
+		Args: mergeArgs(cursorArgs("guid"), map[string]graphql.Arg{"q": {Type: "String"}}),
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			if err := validateFirst(args); err != nil {
+				return nil, err
+			}
+			ids := make([]int, len(parents))
+			for i, p := range parents {
+				ids[i] = deref[models.Faculty](p).ID
+			}
+			depts, _, err := storage.ListDepartments(storage.WithContext(ctx, h.DB), storage.DepartmentQuery{
+				FacultyIDs: uniqueInts(ids),
+				Search:     argString(args, "q"),
+				PerFaculty: parentPage(args),
+			})
+			if err != nil {
+				return nil, err
+			}
+			byFaculty := make(map[int][]models.Department)
+			for _, d := range depts {
+				byFaculty[d.FacultyID] = append(byFaculty[d.FacultyID], d)
+			}
+			out := make([]interface{}, len(parents))
+			for i, id := range ids {
+				out[i] = append([]models.Department{}, byFaculty[id]...)
+			}
+			return out, nil
+		},
+	}
+
+	department.Fields["faculty"] = &graphql.Field{
+		Type: "Faculty", Object: faculty,
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			ids := make([]int, len(parents))
+			for i, p := range parents {
+				ids[i] = deref[models.Department](p).FacultyID
+			}
+			faculties, _, err := storage.ListFaculties(storage.WithContext(ctx, h.DB), storage.FacultyQuery{IDs: uniqueInts(ids)})
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]*models.Faculty, len(faculties))
+			for i := range faculties {
+				byID[faculties[i].ID] = &faculties[i]
+			}
+			out := make([]interface{}, len(parents))
+			for i, id := range ids {
+				if f, ok := byID[id]; ok {
+					out[i] = f
+				}
+			}
+			return out, nil
+		},
+	}
+
+	department.Fields["courses"] = &graphql.Field{
+		Type: "[Course!]!", Object: course, List: true, Size: courseListSize,
+		Args: mergeArgs(cursorArgs("code"), courseFilterArgs()),
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			if err := validateFirst(args); err != nil {
+				return nil, err
+			}
+			ids := make([]int, len(parents))
+			for i, p := range parents {
+				ids[i] = deref[models.Department](p).ID
+			}
+			q := courseQuery(args)
+			q.DepartmentIDs = uniqueInts(ids)
+			q.PerDepartment = parentPage(args)
+			courses, _, err := storage.ListCourses(storage.WithContext(ctx, h.DB), q)
+			if err != nil {
+				return nil, err
+			}
+			byDepartment := make(map[int][]models.Course)
+			for _, c := range courses {
+				byDepartment[c.DepartmentID] = append(byDepartment[c.DepartmentID], c)
+			}
+			out := make([]interface{}, len(parents))
+			for i, id := range ids {
+				out[i] = append([]models.Course{}, byDepartment[id]...)
+			}
+			return out, nil
+		},
+	}
+
+	course.Fields["department"] = &graphql.Field{
+		Type: "Department", Object: department,
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			ids := make([]int, len(parents))
+			for i, p := range parents {
+				ids[i] = deref[models.Course](p).DepartmentID
+			}
+			depts, _, err := storage.ListDepartments(storage.WithContext(ctx, h.DB), storage.DepartmentQuery{IDs: uniqueInts(ids)})
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[int]*models.Department, len(depts))
+			for i := range depts {
+				byID[depts[i].ID] = &depts[i]
+			}
+			out := make([]interface{}, len(parents))
+			for i, id := range ids {
+				if d, ok := byID[id]; ok {
+					out[i] = d
+				}
+			}
+			return out, nil
+		},
+	}
+
+	course.Fields["detail"] = &graphql.Field{
+		Type: "CourseDetail", Object: detail, Description: "İzlencesi olmayan derslerde null",
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			codes := make([]string, len(parents))
+			seen := make(map[string]bool, len(parents))
+			var unique []string
+			for i, p := range parents {
+				codes[i] = deref[models.Course](p).Code
+				if !seen[codes[i]] {
+					seen[codes[i]] = true
+					unique = append(unique, codes[i])
+				}
+			}
+			details, err := storage.GetCourseDetailsByCodes(storage.WithContext(ctx, h.DB), unique)
+			if err != nil {
+				return nil, err
+			}
+			byCode := make(map[string]models.CourseDetail, len(details))
+			for _, d := range details {
+				byCode[d.BaseInfo.Code] = d
+			}
+			out := make([]interface{}, len(parents))
+			for i, p := range parents {
+				if d, ok := byCode[codes[i]]; ok {
+					d.BaseInfo = deref[models.Course](p)
+					out[i] = &d
+				}
+			}
+			return out, nil
+		},
+	}
+
+	detail.Fields["base_info"] = &graphql.Field{Type: "Course!", Object: course}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"faculties": {
+			Type: "[Faculty!]!", Object: faculty, List: true, Size: facultyListSize,
+			Args: mergeArgs(pageArgs(), map[string]graphql.Arg{"q": {Type: "String", Description: "Ada göre arama"}}),
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				opts, err := listOptions(args)
+				if err != nil {
+					return nil, err
+				}
+				faculties, _, err := storage.ListFaculties(storage.WithContext(ctx, h.DB), storage.FacultyQuery{
+					ListOptions: opts, Search: argString(args, "q"),
+				})
+				return faculties, err
+			}),
+		},
+		"faculty": {
+			Type: "Faculty", Object: faculty,
+			Args: map[string]graphql.Arg{"id": {Type: "Int!"}},
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				f, err := storage.GetFacultyByID(storage.WithContext(ctx, h.DB), argInt(args, "id"))
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				return f, err
+			}),
+		},
+		"departments": {
+			Type: "[Department!]!", Object: department, List: true, Size: departmentListSize,
+			Args: mergeArgs(pageArgs(), map[string]graphql.Arg{"q": {Type: "String"}, "faculty_id": {Type: "Int"}}),
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				opts, err := listOptions(args)
+				if err != nil {
+					return nil, err
+				}
+				depts, _, err := storage.ListDepartments(storage.WithContext(ctx, h.DB), storage.DepartmentQuery{
+					ListOptions: opts, Search: argString(args, "q"), FacultyID: argIntPtr(args, "faculty_id"),
+				})
+				return depts, err
+			}),
+		},
+		"department": {
+			Type: "Department", Object: department,
+			Args: map[string]graphql.Arg{"guid": {Type: "String!"}},
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				d, err := storage.GetDepartmentByGUID(storage.WithContext(ctx, h.DB), argString(args, "guid"))
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				return d, err
+			}),
+		},
+		"courses": {
+			Type: "[Course!]!", Object: course, List: true, Size: courseListSize,
+			Args: mergeArgs(pageArgs(), courseFilterArgs(), map[string]graphql.Arg{"department_guid": {Type: "String!"}}),
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				opts, err := listOptions(args)
+				if err != nil {
+					return nil, err
+				}
+				db := storage.WithContext(ctx, h.DB)
+				dept, err := storage.GetDepartmentByGUID(db, argString(args, "department_guid"))
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, graphql.Errorf("bölüm bulunamadı")
+				}
+				if err != nil {
+					return nil, err
+				}
+				q := courseQuery(args)
+				q.ListOptions = opts
+				q.DepartmentID = dept.ID
+				courses, _, err := storage.ListCourses(db, q)
+				return courses, err
+			}),
+		},
+		"course": {
+			Type: "CourseDetail", Object: detail, Description: "Ders izlencesi, ders koduyla",
+			Args: map[string]graphql.Arg{"code": {Type: "String!"}},
+			Resolve: rootResolver(func(ctx context.Context, args map[string]interface{}) (interface{}, error) {
+				d, err := storage.GetCourseDetail(storage.WithContext(ctx, h.DB), argString(args, "code"))
+				if errors.Is(err, sql.ErrNoRows) {
+					return nil, nil
+				}
+				return d, err
+			}),
+		},
+	}}
+
+	return &graphql.Schema{Query: query, MaxDepth: graphqlMaxDepth, MaxComplexity: graphqlMaxComplexity}
+}
+
+func mergeArgs(sets ...map[string]graphql.Arg) map[string]graphql.Arg {
+	merged := map[string]graphql.Arg{}
+	for _, set := range sets {
+		for k, v := range set {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// GraphQL sorgusunu çalıştırır. Ayrıştırma/doğrulama hataları 400, çözücü hataları
+// kısmi veriyle birlikte 200 döner. İç hatalar loglanır, istemciye genel mesaj verilir.
+func (h *Handler) serveGraphQL(w http.ResponseWriter, r *http.Request, req graphql.Request) {
+	if req.Query == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "query")
+		return
+	}
+
+	resp, ok := h.gql.Execute(r.Context(), req)
+	for _, e := range resp.Errors {
+		if e.Err == nil {
+			continue
+		}
+		log.Printf("[%s] %s %s: %v", requestID(r), r.Method, logPath(r), e.Err)
+		code := ErrInternal
+		if errors.Is(e.Err, context.DeadlineExceeded) {
+			code = ErrTimeout
+		}
+		e.Message = errorMessage(code, requestLanguage(r))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) PostGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req graphql.Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxGraphQLBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	h.serveGraphQL(w, r, req)
+}
+
+// GET ile sorgu: ?query=...&variables={...}&operationName=...
+func (h *Handler) GetGraphQL(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := graphql.Request{Query: q.Get("query"), OperationName: q.Get("operationName")}
+	if len(req.Query) > maxGraphQLBodySize {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "query")
+		return
+	}
+	if v := q.Get("variables"); v != "" {
+		if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "variables")
+			return
+		}
+	}
+	h.serveGraphQL(w, r, req)
+}
+
+// Şemayı SDL olarak döner.
+func (h *Handler) GetGraphQLSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(h.gql.SDL()))
+}
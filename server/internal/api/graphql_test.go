@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+type graphqlResult struct {
+	Data struct {
+		Department struct {
+			Courses []struct {
+				Code string `json:"code"`
+			} `json:"courses"`
+		} `json:"department"`
+		Course *struct {
+			Aim string `json:"aim"`
+		} `json:"course"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func graphqlQuery(t *testing.T, h http.Handler, query string) graphqlResult {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": query})
+	rec := serve(t, h, "POST", "/graphql", string(body), "Content-Type", "application/json")
+	var result graphqlResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("%s: %v", rec.Body.String(), err)
+	}
+	return result
+}
+
+func TestGraphQLNestedPagination(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	tests := []struct {
+		args string
+		want string
+	}{
+		{``, "MAT101,MAT102,SEC101"},
+		{`(first: 2)`, "MAT101,MAT102"},
+		{`(first: 2, after: "MAT102")`, "SEC101"},
+		{`(after: "SEC101")`, ""},
+		{`(after: "YOK")`, ""},
+	}
+	for _, tt := range tests {
+		result := graphqlQuery(t, router, `{ department(guid: "d1") { courses`+tt.args+` { code } } }`)
+		if len(result.Errors) > 0 {
+			t.Fatalf("%s: %v", tt.args, result.Errors)
+		}
+		var got string
+		for i, c := range result.Data.Department.Courses {
+			if i > 0 {
+				got += ","
+			}
+			got += c.Code
+		}
+		if got != tt.want {
+			t.Errorf("courses%s = %s, want %s", tt.args, got, tt.want)
+		}
+	}
+
+	result := graphqlQuery(t, router, `{ department(guid: "d1") { courses(first: `+strconv.Itoa(maxPageSize+1)+`) { code } } }`)
+	if len(result.Errors) == 0 {
+		t.Error("first above the page size: expected error")
+	}
+}
+
+func TestGraphQLCourseErrors(t *testing.T) {
+	db := testDB(t)
+	router := SetupRoutes(NewHandler(db), Config{})
+
+	if result := graphqlQuery(t, router, `{ course(code: "MAT101") { aim } }`); result.Data.Course == nil || result.Data.Course.Aim != "Analiz" {
+		t.Errorf("existing course = %+v, errors = %v", result.Data.Course, result.Errors)
+	}
+	if result := graphqlQuery(t, router, `{ course(code: "YOK") { aim } }`); result.Data.Course != nil || len(result.Errors) != 0 {
+		t.Errorf("missing course = %+v, errors = %v", result.Data.Course, result.Errors)
+	}
+
+	// Veritabanı hatası null değil, hata olarak döner.
+	if _, err := db.Exec("DROP TABLE course_details"); err != nil {
+		t.Fatal(err)
+	}
+	if result := graphqlQuery(t, router, `{ course(code: "MAT101") { aim } }`); len(result.Errors) != 1 {
+		t.Errorf("db error: errors = %v", result.Errors)
+	}
+}
@@ -3,100 +3,289 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
 
+	"companion_server/internal/graphql"
+	"companion_server/internal/models"
 	"companion_server/internal/storage"
 )
 
 type Handler struct {
-	DB *sql.DB
+	DB    *sql.DB
+	Cache *ResponseCache
+	// Akış bağlantılarının kök adresi (ör. https://api.example.com). Boşsa istekten türetilir.
+	PublicURL string
+	// EBS taramasını arka planda başlatır; tarama zaten sürüyorsa false döner.
+	TriggerScrape func() bool
+	// Ders programı alımını arka planda başlatır; alım zaten sürüyorsa false döner.
+	TriggerTimetables func() bool
+
+	keys *apiKeyCache
+	gql  *graphql.Schema
 }
 
 func NewHandler(db *sql.DB) *Handler {
-	return &Handler{DB: db}
+	h := &Handler{DB: db, Cache: NewResponseCache(db), keys: newAPIKeyCache()}
+	h.gql = h.newGraphQLSchema()
+	return h
+}
+
+// İsteğin bağlamına bağlı bağlantı; rota zaman aşımı veritabanı sorgularına da uygulanır.
+func (h *Handler) db(r *http.Request) storage.DB {
+	return storage.WithContext(r.Context(), h.DB)
+}
+
+// Veri değiştiren yönetici işlemlerinden sonra çağrılır.
+func (h *Handler) dataChanged() {
+	if _, err := storage.BumpDataVersion(h.DB); err != nil {
+		log.Printf("Veri sürümü artırılamadı: %v", err)
+	}
+	h.Cache.Invalidate()
 }
 
 func respondJSON(w http.ResponseWriter, data interface{}) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
 
-// Tüm fakülteleri döner
+// v1 katalog yanıtlarının dili. Eski rotalar iki dili de ham döndürmeye devam eder;
+// bu durumda ok false olur.
+func contentLanguage(w http.ResponseWriter, r *http.Request) (lang string, ok bool) {
+	if isLegacy(r) {
+		return "", false
+	}
+	lang = requestLanguage(r)
+	w.Header().Set("Content-Language", lang)
+	return lang, true
+}
+
+// Fakülteleri döner. q ile ada göre filtrelenir, limit/offset/sort destekler.
 func (h *Handler) GetFaculties(w http.ResponseWriter, r *http.Request) {
-	faculties, err := storage.GetAllFaculties(h.DB)
+	opts, badParam := parseListOptions(r, storage.FacultySortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+
+	cursor, err := storage.QueryFaculties(h.db(r), storage.FacultyQuery{
+		ListOptions: opts,
+		Search:      r.URL.Query().Get("q"),
+	})
 	if err != nil {
-		http.Error(w, "Failed to fetch faculties", http.StatusInternalServerError)
+		respondInternalError(w, r, err)
 		return
 	}
-	respondJSON(w, faculties)
+	if lang, ok := contentLanguage(w, r); ok {
+		streamView(w, r, cursor, opts, func(f models.Faculty) models.LocalizedFaculty { return f.Localize(lang) })
+		return
+	}
+	streamList(w, r, cursor, opts)
 }
 
-// Bütün bölümleri veya fakülte belirtilmişse fakülte bölümlerini döner
+// Bütün bölümleri veya fakülte belirtilmişse fakülte bölümlerini döner.
+// Fakülte, v1'de yol parametresi (/faculties/{id}/departments), eski rotada faculty_id ile verilir.
 func (h *Handler) GetDepartments(w http.ResponseWriter, r *http.Request) {
-	facultyIDStr := r.URL.Query().Get("faculty_id")
+	opts, badParam := parseListOptions(r, storage.DepartmentSortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+
+	q := storage.DepartmentQuery{ListOptions: opts, Search: r.URL.Query().Get("q")}
 
+	facultyIDStr := r.PathValue("id")
+	if facultyIDStr == "" {
+		facultyIDStr = r.URL.Query().Get("faculty_id")
+	}
 	if facultyIDStr != "" {
 		facultyID, err := strconv.Atoi(facultyIDStr)
 		if err != nil {
-			http.Error(w, "Geçersiz faculty_id parametresi", http.StatusBadRequest)
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "faculty_id")
 			return
 		}
+		q.FacultyID = &facultyID
+	}
 
-		departments, err := storage.GetDepartmentsByFacultyID(h.DB, facultyID)
-		if err != nil {
-			http.Error(w, "Bölümler alınırken bir sıkıntı yaşandı", http.StatusInternalServerError)
-			return
-		}
-		respondJSON(w, departments)
-	} else {
-		departments, err := storage.GetAllDepartments(h.DB)
-		if err != nil {
-			http.Error(w, "Bölümler alınırken bir sıkıntı yaşandı", http.StatusInternalServerError)
-			return
-		}
-		respondJSON(w, departments)
+	cursor, err := storage.QueryDepartments(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if lang, ok := contentLanguage(w, r); ok {
+		streamView(w, r, cursor, opts, func(d models.Department) models.LocalizedDepartment { return d.Localize(lang) })
+		return
 	}
+	streamList(w, r, cursor, opts)
 }
 
-// Dersleri döner, bölüm guid ile çalışır.
-func (h *Handler) GetCourses(w http.ResponseWriter, r *http.Request) {
+// Bölümü guid ile bulur, bulunamazsa 404 yazar.
+func (h *Handler) departmentByGUID(w http.ResponseWriter, r *http.Request, guid string) (*models.Department, bool) {
+	dept, err := storage.GetDepartmentByGUID(h.db(r), guid)
+	if err == sql.ErrNoRows {
+		respondError(w, r, http.StatusNotFound, ErrDepartmentNotFound)
+		return nil, false
+	}
+	if err != nil {
+		respondInternalError(w, r, err)
+		return nil, false
+	}
+	return dept, true
+}
 
-	deptGUID := r.URL.Query().Get("id")
+// Tek bir bölümü döner, bölüm guid ile çalışır.
+func (h *Handler) GetDepartment(w http.ResponseWriter, r *http.Request) {
+	dept, ok := h.departmentByGUID(w, r, r.PathValue("guid"))
+	if !ok {
+		return
+	}
+	if lang, ok := contentLanguage(w, r); ok {
+		respondJSON(w, dept.Localize(lang))
+		return
+	}
+	respondJSON(w, dept)
+}
 
+// Dersleri döner, bölüm guid ile çalışır. semester, is_mandatory, is_removed, year,
+// ects_min, ects_max ve language ile filtrelenir.
+func (h *Handler) GetCourses(w http.ResponseWriter, r *http.Request) {
+	deptGUID := r.PathValue("guid")
 	if deptGUID == "" {
-		http.Error(w, "id (department guid) parametresi zorunludur", http.StatusBadRequest)
+		deptGUID = r.URL.Query().Get("id")
+	}
+
+	if deptGUID == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "id")
 		return
 	}
 
-	dept, err := storage.GetDepartmentByGUID(h.DB, deptGUID)
-	if err != nil {
-		http.Error(w, "Bölüm bulunamadı", http.StatusNotFound)
+	opts, badParam := parseListOptions(r, storage.CourseSortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
 		return
 	}
 
-	data, err := storage.GetCoursesByDepartmentID(h.DB, dept.ID)
+	q := storage.CourseQuery{
+		ListOptions: opts,
+		Semester:    r.URL.Query().Get("semester"),
+		Language:    r.URL.Query().Get("language"),
+	}
+	var ok bool
+	if q.IsMandatory, ok = queryBool(r, "is_mandatory"); !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "is_mandatory")
+		return
+	}
+	if q.IsRemoved, ok = queryBool(r, "is_removed"); !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "is_removed")
+		return
+	}
+	if q.Year, ok = queryInt(r, "year"); !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "year")
+		return
+	}
+	if q.MinECTS, ok = queryFloat(r, "ects_min"); !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "ects_min")
+		return
+	}
+	if q.MaxECTS, ok = queryFloat(r, "ects_max"); !ok {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "ects_max")
+		return
+	}
+
+	dept, ok := h.departmentByGUID(w, r, deptGUID)
+	if !ok {
+		return
+	}
+	q.DepartmentID = dept.ID
+
+	cursor, err := storage.QueryCourses(h.db(r), q)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		respondInternalError(w, r, err)
 		return
 	}
-	respondJSON(w, data)
+	if lang, ok := contentLanguage(w, r); ok {
+		streamView(w, r, cursor, opts, func(c models.Course) models.Course { return c.Localize(lang) })
+		return
+	}
+	streamList(w, r, cursor, opts)
 }
 
 // Ders detaylarını döner, ders kodu (BIMU..) ile çalışır
 func (h *Handler) GetCourseDetail(w http.ResponseWriter, r *http.Request) {
-	code := r.URL.Query().Get("code")
+	code := r.PathValue("code")
+	if code == "" {
+		code = r.URL.Query().Get("code")
+	}
 
 	if code == "" {
-		http.Error(w, "code parametresi zorunludur", http.StatusBadRequest)
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "code")
 		return
 	}
 
-	data, err := storage.GetCourseDetail(h.DB, code)
+	data, err := storage.GetCourseDetail(h.db(r), code)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(w, r, http.StatusNotFound, ErrCourseNotFound)
+		return
+	}
 	if err != nil {
-		http.Error(w, "Ders detayı bulunamadı", http.StatusNotFound)
+		respondInternalError(w, r, err)
+		return
+	}
+	if lang, ok := contentLanguage(w, r); ok {
+		respondJSON(w, data.Localize(lang))
 		return
 	}
 	respondJSON(w, data)
 }
+
+// Bölümün seçmeli ders havuzlarını döner, bölüm guid ile çalışır.
+func (h *Handler) GetElectiveGroups(w http.ResponseWriter, r *http.Request) {
+	dept, ok := h.departmentByGUID(w, r, r.PathValue("guid"))
+	if !ok {
+		return
+	}
+
+	groups, err := storage.GetElectiveGroupsByDepartmentID(h.db(r), dept.ID)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if groups == nil {
+		groups = []models.ElectiveGroup{}
+	}
+	if lang, ok := contentLanguage(w, r); ok {
+		for i := range groups {
+			for j, c := range groups[i].Courses {
+				groups[i].Courses[j] = c.Localize(lang)
+			}
+		}
+	}
+	respondJSON(w, groups)
+}
+
+// Dersin onaylanmış eşdeğerlerini (başka bölümlerdeki karşılıklarını) döner.
+func (h *Handler) GetCourseEquivalents(w http.ResponseWriter, r *http.Request) {
+	code := r.PathValue("code")
+
+	equivalents, err := storage.GetConfirmedEquivalents(h.db(r), code)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	// Eşdeğeri olmayan ders ile bilinmeyen kod ayrılır.
+	if len(equivalents) == 0 {
+		courses, err := storage.GetCoursesByCodes(h.db(r), []string{code})
+		if err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+		if _, ok := courses[code]; !ok {
+			respondError(w, r, http.StatusNotFound, ErrCourseNotFound)
+			return
+		}
+		equivalents = []models.CourseEquivalence{}
+	}
+	respondJSON(w, equivalents)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"companion_server/internal/models"
+)
+
+const icsProductID = "-//IUC Companion//Takvim//TR"
+
+// Takvim uygulamalarının akışı yeniden çekme aralığı
+const icsRefreshInterval = "PT12H"
+
+// Saatli etkinlikler bu bölgede (models.Istanbul) yazılır.
+const icsTimeZone = "Europe/Istanbul"
+
+// RFC 5545 takvimi; abone olunabilir .ics akışları için.
+type icsCalendar struct {
+	Name        string
+	Description string
+	Events      []icsEvent
+}
+
+type icsEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Categories  []string
+	// Tüm gün etkinliklerinde End dahildir; yazılırken bir gün eklenir.
+	Start, End time.Time
+	AllDay     bool
+	Stamp      time.Time
+	Location   string
+	// Tekrar kuralı (ör. "FREQ=WEEKLY;UNTIL=20260116T205959Z")
+	RRule string
+	// Tekrardan çıkarılan başlangıç zamanları
+	ExDates []time.Time
+}
+
+func writeICS(w http.ResponseWriter, cal icsCalendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) { writeICSLine(bw, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", icsProductID)
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", icsEscape(cal.Name))
+	if cal.Description != "" {
+		line("X-WR-CALDESC", icsEscape(cal.Description))
+	}
+	line("REFRESH-INTERVAL;VALUE=DURATION", icsRefreshInterval)
+	line("X-PUBLISHED-TTL", icsRefreshInterval)
+
+	for _, e := range cal.Events {
+		if !e.AllDay {
+			line("BEGIN", "VTIMEZONE")
+			line("TZID", icsTimeZone)
+			line("BEGIN", "STANDARD")
+			line("DTSTART", "19700101T000000")
+			line("TZOFFSETFROM", "+0300")
+			line("TZOFFSETTO", "+0300")
+			line("TZNAME", "+03")
+			line("END", "STANDARD")
+			line("END", "VTIMEZONE")
+			break
+		}
+	}
+
+	local := func(t time.Time) string { return t.In(models.Istanbul).Format("20060102T150405") }
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", e.Stamp.UTC().Format("20060102T150405Z"))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE", e.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", e.End.AddDate(0, 0, 1).Format("20060102"))
+			line("TRANSP", "TRANSPARENT")
+		} else {
+			line("DTSTART;TZID="+icsTimeZone, local(e.Start))
+			line("DTEND;TZID="+icsTimeZone, local(e.End))
+		}
+		if e.RRule != "" {
+			line("RRULE", e.RRule)
+		}
+		if len(e.ExDates) > 0 {
+			dates := make([]string, len(e.ExDates))
+			for i, d := range e.ExDates {
+				dates[i] = local(d)
+			}
+			line("EXDATE;TZID="+icsTimeZone, strings.Join(dates, ","))
+		}
+		line("SUMMARY", icsEscape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", icsEscape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", icsEscape(e.Location))
+		}
+		if len(e.Categories) > 0 {
+			escaped := make([]string, len(e.Categories))
+			for i, c := range e.Categories {
+				escaped[i] = icsEscape(c)
+			}
+			line("CATEGORIES", strings.Join(escaped, ","))
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	bw.Flush()
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// Satırları 75 baytta katlar; çok baytlı karakterler bölünmez.
+func writeICSLine(w *bufio.Writer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// Devam satırındaki boşluk da sınıra dahildir.
+		limit = 74
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
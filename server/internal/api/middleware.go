@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+type Middleware func(http.Handler) http.Handler
+
+// Ara katmanları sırayla uygular; ilk verilen en dışta çalışır.
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+const requestIDKey contextKey = "request_id"
+
+func requestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey).(string)
+	return id
+}
+
+// İstemcinin gönderdiği X-Request-ID kullanılır, yoksa ya da geçersizse yenisi üretilir.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" || len(id) > 64 || strings.ContainsAny(id, " \t\r\n") {
+			b := make([]byte, 8)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		w.Header().Set("X-Request-ID", id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	})
+}
+
+// Yanıt durum kodunu ve yazılan bayt sayısını tutar.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+var accessLogger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+// Yolunda gizli anahtar taşıyan rotaların önekleri (kişisel takvim akışları). Adresi
+// bilen herkes akışı okuyabildiği için günlüklere anahtar yerine yer tutucu yazılır.
+var secretPathPrefixes = []string{"/feeds/timetable/", "/api/v1/timetable/feeds/"}
+
+// Günlüklere yazılacak yol
+func logPath(r *http.Request) string {
+	for _, prefix := range secretPathPrefixes {
+		if len(r.URL.Path) > len(prefix) && strings.HasPrefix(r.URL.Path, prefix) {
+			return prefix + "{token}"
+		}
+	}
+	return r.URL.Path
+}
+
+// Her isteği JSON satırı olarak loglar: metot, yol, durum, süre ve yazılan bayt.
+func withAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		accessLogger.Info("request",
+			"request_id", requestID(r),
+			"method", r.Method,
+			"path", logPath(r),
+			"query", r.URL.RawQuery,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"bytes", rec.bytes,
+			"remote", r.RemoteAddr,
+		)
+	})
+}
+
+// Handler'da oluşan panic'i loglar ve henüz yanıt yazılmadıysa JSON 500 döner.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+
+			log.Printf("[%s] %s %s panic: %v\n%s", requestID(r), r.Method, logPath(r), p, debug.Stack())
+			if rec, ok := w.(*responseRecorder); ok && rec.status != 0 {
+				return
+			}
+			respondError(w, r, http.StatusInternalServerError, ErrInternal)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// İzin verilen kökenler için CORS başlıklarını ekler ve ön uçuş (preflight) isteklerini yanıtlar.
+// "*" tüm kökenlere izin verir.
+func withCORS(allowedOrigins []string) Middleware {
+	allowAll := false
+	allowed := make(map[string]bool, len(allowedOrigins))
+	for _, o := range allowedOrigins {
+		o = strings.TrimSpace(o)
+		if o == "*" {
+			allowAll = true
+		}
+		allowed[o] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			h := w.Header()
+			switch {
+			case allowAll:
+				h.Set("Access-Control-Allow-Origin", "*")
+			case origin != "" && allowed[origin]:
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Add("Vary", "Origin")
+			default:
+				origin = ""
+			}
+			if allowAll || origin != "" {
+				h.Set("Access-Control-Expose-Headers", "ETag, Link, Deprecation, Retry-After, X-Total-Count, X-Request-ID")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				if allowAll || origin != "" {
+					h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+					h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-Request-ID, If-None-Match")
+					h.Set("Access-Control-Max-Age", "600")
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// İsteğin bağlamına süre sınırı koyar. Handler'lar sorguları bu bağlamla çalıştırdığı için
+// süre dolduğunda veritabanı sorgusu da iptal edilir.
+func withTimeout(timeout time.Duration, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next(w, r.WithContext(ctx))
+	}
+}
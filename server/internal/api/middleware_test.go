@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	handler := withCORS([]string{"https://app.example", " https://admin.example"})(next)
+
+	tests := []struct {
+		origin    string
+		preflight bool
+		allow     string
+		status    int
+	}{
+		{"https://app.example", false, "https://app.example", http.StatusOK},
+		{"https://admin.example", true, "https://admin.example", http.StatusNoContent},
+		{"https://evil.example", false, "", http.StatusOK},
+		{"https://evil.example", true, "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		method := "GET"
+		if tt.preflight {
+			method = http.MethodOptions
+		}
+		r := httptest.NewRequest(method, "/", nil)
+		r.Header.Set("Origin", tt.origin)
+		if tt.preflight {
+			r.Header.Set("Access-Control-Request-Method", "POST")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.allow {
+			t.Errorf("%s %s: allow origin = %q, want %q", method, tt.origin, got, tt.allow)
+		}
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: status = %d, want %d", method, tt.origin, rec.Code, tt.status)
+		}
+		if allowed := rec.Header().Get("Access-Control-Allow-Methods") != ""; allowed != (tt.preflight && tt.allow != "") {
+			t.Errorf("%s %s: allow methods = %v", method, tt.origin, allowed)
+		}
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	handler := withRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestID(r)
+	}))
+
+	for _, tt := range []struct {
+		header string
+		keep   bool
+	}{
+		{"abc-123", true},
+		{"", false},
+		{"iki kelime", false},
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Request-ID", tt.header)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		got := rec.Header().Get("X-Request-ID")
+		if got == "" || got != seen {
+			t.Errorf("%q: header = %q, context = %q", tt.header, got, seen)
+		}
+		if (got == tt.header) != tt.keep {
+			t.Errorf("%q: request id = %q", tt.header, got)
+		}
+	}
+}
+
+func TestWithRecovery(t *testing.T) {
+	handler := withRecovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := map[string]string{
+		"":                       "",
+		"gzip":                   "gzip",
+		"gzip, br":               "br",
+		"br;q=0.5, gzip":         "gzip",
+		"deflate, identity":      "",
+		"GZIP;q=0.8, br;q=0":     "gzip",
+		"br;q=0.9, gzip;q=bozuk": "gzip",
+	}
+	for header, want := range tests {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestAccessLogRedactsTokens(t *testing.T) {
+	var buf bytes.Buffer
+	defer func(l *slog.Logger) { accessLogger = l }(accessLogger)
+	accessLogger = slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := withAccessLog(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/feeds/timetable/itf_gizli.ics", `"path":"/feeds/timetable/{token}"`},
+		{"/api/v1/timetable/feeds/itf_gizli", `"path":"/api/v1/timetable/feeds/{token}"`},
+		{"/api/v1/timetable/feeds", `"path":"/api/v1/timetable/feeds"`},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", tt.path, nil))
+		if line := buf.String(); strings.Contains(line, "itf_gizli") || !strings.Contains(line, tt.want) {
+			t.Errorf("%s: log = %s", tt.path, line)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"companion_server/internal/models"
+)
+
+const openAPIVersion = "3.0.3"
+
+// Belgenin kendisi kodla birlikte değiştiği için sürüm olarak API sürümü kullanılır.
+const apiVersion = "1.0.0"
+
+type object = map[string]interface{}
+
+// Go tiplerinden JSON şeması üretir. Adlandırılmış struct'lar components/schemas altına
+// bir kez yazılır ve $ref ile kullanılır.
+type schemaBuilder struct {
+	schemas map[string]interface{}
+	names   map[reflect.Type]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{schemas: object{}, names: map[reflect.Type]string{}}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (b *schemaBuilder) schema(t reflect.Type) object {
+	switch {
+	case t == nil:
+		return object{}
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := b.schema(t.Elem())
+		if _, isRef := s["$ref"]; isRef {
+			return object{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return object{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.String:
+		return object{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return object{"type": "string", "format": "byte"}
+		}
+		// nil dilimler null yazılır.
+		return object{"type": "array", "items": b.schema(t.Elem()), "nullable": true}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return object{"$ref": "#/components/schemas/" + b.register(t)}
+	default:
+		return object{}
+	}
+}
+
+// Struct'ı bileşen olarak kaydeder. Farklı paketlerde aynı adlı tipler paket adıyla ayrılır.
+func (b *schemaBuilder) register(t reflect.Type) string {
+	if name, ok := b.names[t]; ok {
+		return name
+	}
+
+	name := exportedName(t.Name())
+	if _, taken := b.schemas[name]; taken {
+		pkg := t.PkgPath()
+		name = exportedName(pkg[strings.LastIndex(pkg, "/")+1:]) + name
+	}
+	b.names[t] = name
+	// Kendine referans veren tipler için yer tutucu
+	b.schemas[name] = object{}
+	b.schemas[name] = b.structSchema(t)
+	return name
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) object {
+	properties := object{}
+	b.addFields(t, properties)
+	return object{"type": "object", "properties": properties}
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties object) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		// Etiketsiz gömülü struct'ların alanları, encoding/json'daki gibi üst nesneye taşınır.
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(ft, properties)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		s := b.schema(field.Type)
+		if doc := field.Tag.Get("doc"); doc != "" {
+			if _, isRef := s["$ref"]; isRef {
+				s = object{"allOf": []interface{}{s}}
+			}
+			s["description"] = doc
+		}
+		properties[name] = s
+	}
+}
+
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// "GET /api/v1/departments/{guid}/courses" -> "getDepartmentsByGuidCourses".
+// Eski rotalar legacy önekiyle ayrılır.
+func operationID(method, path string, legacy bool) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		switch {
+		case seg == "" || seg == "api" || seg == "v1":
+			continue
+		case strings.HasPrefix(seg, "{"):
+			sb.WriteString("By" + exportedName(strings.Trim(seg, "{}")))
+		default:
+			for _, part := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '.' || r == '_' }) {
+				sb.WriteString(exportedName(part))
+			}
+		}
+	}
+	if legacy {
+		return "legacy" + exportedName(sb.String())
+	}
+	return sb.String()
+}
+
+func pathParams(path string) []string {
+	var params []string
+	for _, seg := range strings.Split(path, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params = append(params, strings.Trim(seg, "{}"))
+		}
+	}
+	return params
+}
+
+func errorResponse(name string) object {
+	return object{"$ref": "#/components/responses/" + name}
+}
+
+func (b *schemaBuilder) operation(rt route, method, path string) object {
+	doc := rt.Doc
+	op := object{
+		"operationId": operationID(method, path, rt.Successor != ""),
+		"summary":     doc.Summary,
+	}
+	if doc.Tag != "" {
+		op["tags"] = []string{doc.Tag}
+	}
+	if rt.Successor != "" {
+		op["deprecated"] = true
+		op["description"] = "Yerine " + rt.Successor + " kullanılmalı."
+	}
+
+	params := []interface{}{}
+	for _, name := range pathParams(path) {
+		params = append(params, object{"name": name, "in": "path", "required": true, "schema": object{"type": "string"}})
+	}
+	for _, p := range doc.Query {
+		s := object{"type": p.Type}
+		if len(p.Enum) > 0 {
+			s["enum"] = p.Enum
+		}
+		param := object{"name": p.Name, "in": "query", "schema": s}
+		if p.Description != "" {
+			param["description"] = p.Description
+		}
+		if p.Required {
+			param["required"] = true
+		}
+		params = append(params, param)
+	}
+	if rt.Cached {
+		params = append(params, langParamObject())
+	}
+	if len(params) > 0 {
+		op["parameters"] = params
+	}
+
+	if doc.Request != nil {
+		op["requestBody"] = object{
+			"required": true,
+			"content":  object{"application/json": object{"schema": b.schema(reflect.TypeOf(doc.Request))}},
+		}
+	}
+
+	responses := object{}
+	status := doc.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := object{"description": http.StatusText(status)}
+	if status != http.StatusNoContent {
+		contentType := doc.ContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success["content"] = object{contentType: object{"schema": b.responseSchema(rt)}}
+	}
+	responses[strconv.Itoa(status)] = success
+
+	if rt.Cached {
+		responses["304"] = object{"description": "ETag ya da Last-Modified değişmedi"}
+	}
+	if doc.Request != nil || len(doc.Query) > 0 || len(pathParams(path)) > 0 {
+		responses["400"] = errorResponse("BadRequest")
+	}
+	if len(pathParams(path)) > 0 {
+		responses["404"] = errorResponse("NotFound")
+	}
+	if rt.Role != "" {
+		responses["401"] = errorResponse("Unauthorized")
+		responses["403"] = errorResponse("Forbidden")
+		op["security"] = []interface{}{object{"apiKey": []string{}}, object{"bearer": []string{}}}
+		op["x-required-role"] = rt.Role
+	}
+	responses["429"] = errorResponse("RateLimited")
+	responses["500"] = errorResponse("InternalError")
+	responses["503"] = errorResponse("Timeout")
+	op["responses"] = responses
+	return op
+}
+
+func langParamObject() object {
+	return object{
+		"name": langParam.Name, "in": "query", "description": langParam.Description,
+		"schema": object{"type": langParam.Type, "enum": langParam.Enum},
+	}
+}
+
+// Liste rotaları v1'de {data, meta} zarfıyla, eski rotalarda düz dizi olarak döner.
+func (b *schemaBuilder) responseSchema(rt route) object {
+	doc := rt.Doc
+	if doc.Response == nil {
+		if doc.ContentType != "" {
+			return object{"type": "string"}
+		}
+		return object{"type": "object"}
+	}
+	s := b.schema(reflect.TypeOf(doc.Response))
+	if !doc.List {
+		return s
+	}
+	items := object{"type": "array", "items": s}
+	if rt.Successor != "" {
+		return items
+	}
+	return object{
+		"type": "object",
+		"properties": object{
+			"data": items,
+			"meta": b.schema(reflect.TypeOf(listMeta{})),
+		},
+	}
+}
+
+// Rota tablosundan OpenAPI 3 belgesini üretir. Çıktı deterministiktir (map anahtarları
+// sıralı yazılır), bu sayede kayıtlı belge ile karşılaştırılabilir.
+func OpenAPISpec() ([]byte, error) {
+	b := newSchemaBuilder()
+	paths := object{}
+
+	for _, rt := range (&Handler{}).routes() {
+		method, path, _ := strings.Cut(rt.Pattern, " ")
+		item, ok := paths[path].(object)
+		if !ok {
+			item = object{}
+			paths[path] = item
+		}
+		item[strings.ToLower(method)] = b.operation(rt, method, path)
+	}
+
+	errorContent := object{"application/json": object{"schema": b.schema(reflect.TypeOf(errorEnvelope{}))}}
+	errorResponses := object{}
+	for name, code := range map[string]ErrorCode{
+		"BadRequest":    ErrInvalidParameter,
+		"NotFound":      ErrNotFound,
+		"Unauthorized":  ErrUnauthorized,
+		"Forbidden":     ErrForbidden,
+		"RateLimited":   ErrRateLimited,
+		"InternalError": ErrInternal,
+		"Timeout":       ErrTimeout,
+	} {
+		errorResponses[name] = object{"description": strings.ReplaceAll(errorMessages[code]["tr"], "%s", "{…}"), "content": errorContent}
+	}
+
+	roles := []string{string(models.RoleReader), string(models.RoleOperator), string(models.RoleAdmin)}
+	spec := object{
+		"openapi": openAPIVersion,
+		"info": object{
+			"title":       "İÜC Companion API",
+			"version":     apiVersion,
+			"description": "Fakülte, bölüm, ders ve akademik hesaplama servisleri. Yönetim rotaları X-API-Key başlığı ya da Bearer token ile çağrılır; roller: " + strings.Join(roles, " < ") + ".",
+		},
+		"paths": paths,
+		"components": object{
+			"schemas":   b.schemas,
+			"responses": errorResponses,
+			"securitySchemes": object{
+				"apiKey": object{"type": "apiKey", "in": "header", "name": "X-API-Key"},
+				"bearer": object{"type": "http", "scheme": "bearer"},
+			},
+		},
+	}
+	return json.MarshalIndent(spec, "", "  ")
+}
+
+// OpenAPI belgesini döner.
+func (h *Handler) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	spec, err := OpenAPISpec()
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(spec)
+}
+
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>İÜC Companion API</title>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/api/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// OpenAPI belgesini Redoc ile gösteren sayfa.
+func (h *Handler) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
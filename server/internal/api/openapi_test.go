@@ -0,0 +1,254 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// Kayıtlı openapi.json rota tablosuyla aynı olmalı; değilse go run ./cmd/openapi -o openapi.json
+func TestOpenAPISpecUpToDate(t *testing.T) {
+	spec, err := OpenAPISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile("../../openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(saved, append(spec, '\n')) {
+		t.Error("openapi.json güncel değil, go run ./cmd/openapi -o openapi.json ile yeniden üretin")
+	}
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	spec, err := OpenAPISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Paths) == 0 {
+		t.Fatal("no paths")
+	}
+	for _, p := range []string{"/api/v1/faculties", "/api/v1/courses/{code}", "/api/v1/regulations"} {
+		if _, ok := doc.Paths[p]["get"]; !ok {
+			t.Errorf("missing GET %s", p)
+		}
+	}
+}
+
+// Belgelenen her JSON GET rotası test veritabanına karşı çağrılır ve yanıt gövdesi rotanın
+// şemasına göre denetlenir: şemada olmayan alanlar ve tip uyuşmazlıkları hata sayılır.
+func TestDocumentedResponsesMatchSchema(t *testing.T) {
+	db := timetableDB(t)
+	admin, _, err := storage.CreateAPIKey(db, "yönetici", models.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	device := &models.Device{Token: "jeton", Platform: models.PlatformAndroid, Language: "tr",
+		Subscriptions: models.Subscriptions{Departments: []string{"d1"}}}
+	if err := storage.SaveDevice(db, device); err != nil {
+		t.Fatal(err)
+	}
+	webhook, err := storage.CreateWebhook(db, "https://example.com/hook", []string{"*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.EnqueueEvent(db, "course.updated", map[string]string{"code": "MAT101"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.UpsertEquivalenceCandidates(db, []models.CourseEquivalence{{CourseCode: "MAT101", EquivalentCode: "MAT102", Score: 0.9}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.SetEquivalenceStatus(db, "MAT101", "MAT102", models.EquivalenceConfirmed); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.SaveAnnouncement(db, &models.Announcement{Site: "iuc", Categories: []int{1}, ExternalID: "1",
+		Title: "Duyuru", URL: "https://iuc.edu.tr/1", PublishedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.ReplaceCalendarEvents(db, []models.CalendarEvent{{UID: "u1", AcademicYear: "2025-2026", Term: "fall",
+		Category: "classes", Title: "Derslerin başlaması", StartDate: "2025-09-22", EndDate: "2025-09-22"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.ReplaceElectiveGroups(db, 10, []models.ElectiveGroup{{Name: "Seçmeli I", Semester: "1. Yarıyıl", RequiredCount: 1, CourseCodes: []string{"SEC101"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.AddTimetableSource(db, 10, "https://example.com/program"); err != nil {
+		t.Fatal(err)
+	}
+	run, err := storage.StartScrapeRun(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.FinishScrapeRun(db, run, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Yol parametreli ve zorunlu sorgu parametreli rotaların çağrılacağı adresler
+	urls := map[string]string{
+		"GET /api/v1/faculties/{id}/departments":     "/api/v1/faculties/1/departments",
+		"GET /api/v1/departments/{guid}":             "/api/v1/departments/d1",
+		"GET /api/v1/departments/{guid}/courses":     "/api/v1/departments/d1/courses",
+		"GET /api/v1/departments/{guid}/electives":   "/api/v1/departments/d1/electives",
+		"GET /api/v1/departments/{guid}/timetable":   "/api/v1/departments/d1/timetable",
+		"GET /api/v1/courses/{code}":                 "/api/v1/courses/MAT101",
+		"GET /api/v1/courses/{code}/equivalents":     "/api/v1/courses/MAT101/equivalents",
+		"GET /api/v1/bundle":                         "/api/v1/bundle?department=d1",
+		"GET /api/v1/devices/{id}":                   "/api/v1/devices/" + device.ID,
+		"GET /api/v1/admin/equivalences":             "/api/v1/admin/equivalences?status=confirmed",
+		"GET /api/v1/admin/webhooks/{id}/deliveries": "/api/v1/admin/webhooks/" + strconv.FormatInt(webhook.ID, 10) + "/deliveries",
+		"GET /graphql":                               "/graphql?query=" + url.QueryEscape("{ faculties { id text departments { guid } } }"),
+		"GET /api/courses":                           "/api/courses?id=d1",
+		"GET /api/course-detail":                     "/api/course-detail?code=MAT101",
+		"GET /api/departments/{guid}/electives":      "/api/departments/d1/electives",
+		"GET /api/courses/{code}/equivalents":        "/api/courses/MAT101/equivalents",
+	}
+
+	var spec struct {
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	raw, err := OpenAPISpec()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	router := SetupRoutes(NewHandler(db), Config{})
+	for _, rt := range (&Handler{}).routes() {
+		method, path, _ := strings.Cut(rt.Pattern, " ")
+		if method != "GET" || rt.Doc.ContentType != "" {
+			continue
+		}
+		target, ok := urls[rt.Pattern]
+		if !ok {
+			if strings.Contains(rt.Pattern, "{") {
+				t.Errorf("%s: test adresi tanımlı değil", rt.Pattern)
+				continue
+			}
+			target = path
+		}
+
+		rec := serve(t, router, method, target, "", "X-API-Key", admin)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, body = %s", target, rec.Code, rec.Body)
+			continue
+		}
+		var body interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Errorf("%s: %v", target, err)
+			continue
+		}
+		content := spec.Paths[path]["get"]["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"]
+		schema := content.(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+		for _, problem := range matchSchema(spec.Components.Schemas, schema.(map[string]interface{}), body, "$") {
+			t.Errorf("%s: %s", target, problem)
+		}
+	}
+}
+
+// Değeri OpenAPI şemasıyla karşılaştırır ve uyuşmazlıkları yollarıyla döner.
+func matchSchema(components map[string]interface{}, schema map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		schema = components[strings.TrimPrefix(ref, "#/components/schemas/")].(map[string]interface{})
+	}
+	if all, ok := schema["allOf"].([]interface{}); ok {
+		if value == nil && schema["nullable"] == true {
+			return nil
+		}
+		var problems []string
+		for _, s := range all {
+			problems = append(problems, matchSchema(components, s.(map[string]interface{}), value, path)...)
+		}
+		return problems
+	}
+	if value == nil {
+		if schema["nullable"] == true || schema["type"] == nil {
+			return nil
+		}
+		return []string{path + ": null, şemada nullable değil"}
+	}
+
+	mismatch := func() []string {
+		return []string{fmt.Sprintf("%s: %T değer, şemada %v", path, value, schema["type"])}
+	}
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+		properties, hasProps := schema["properties"].(map[string]interface{})
+		additional, hasAdditional := schema["additionalProperties"].(map[string]interface{})
+		var problems []string
+		for _, key := range sortedKeys(obj) {
+			switch {
+			case hasProps && properties[key] != nil:
+				problems = append(problems, matchSchema(components, properties[key].(map[string]interface{}), obj[key], path+"."+key)...)
+			case hasAdditional:
+				problems = append(problems, matchSchema(components, additional, obj[key], path+"."+key)...)
+			case hasProps:
+				problems = append(problems, path+"."+key+": şemada yok")
+			}
+		}
+		return problems
+	case "array":
+		list, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		var problems []string
+		for i, item := range list {
+			problems = append(problems, matchSchema(components, schema["items"].(map[string]interface{}), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return problems
+	case "string":
+		if _, ok := value.(string); !ok {
+			return mismatch()
+		}
+	case "integer":
+		if n, ok := value.(float64); !ok || n != math.Trunc(n) {
+			return mismatch()
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return mismatch()
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return mismatch()
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"companion_server/internal/storage"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+type contextKey string
+
+// Kullanımdan kaldırılan rotalarda liste yanıtı eskisi gibi düz dizi olarak döner.
+const legacyResponseKey contextKey = "legacy_response"
+
+func withLegacyResponse(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), legacyResponseKey, true))
+}
+
+func isLegacy(r *http.Request) bool {
+	legacy, _ := r.Context().Value(legacyResponseKey).(bool)
+	return legacy
+}
+
+type listMeta struct {
+	Total      int  `json:"total"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	NextOffset *int `json:"next_offset"`
+}
+
+func newListMeta(total int, opts storage.ListOptions) listMeta {
+	meta := listMeta{Total: total, Limit: opts.Limit, Offset: opts.Offset}
+	if opts.Limit > 0 && opts.Offset+opts.Limit < total {
+		next := opts.Offset + opts.Limit
+		meta.NextOffset = &next
+	}
+	return meta
+}
+
+// Liste yanıtı: v1'de {data, meta}, eski rotalarda düz dizi. Toplam her iki durumda da
+// X-Total-Count başlığında bulunur. Kayıtlar veritabanından okundukça yazılır, liste
+// belleğe alınmaz. Yazım başladıktan sonra oluşan hata sadece loglanır.
+func streamList[T any](w http.ResponseWriter, r *http.Request, cursor *storage.Cursor[T], opts storage.ListOptions) {
+	streamView(w, r, cursor, opts, func(item T) T { return item })
+}
+
+// streamList gibi; kayıtlar yazılmadan önce view ile dönüştürülür (ör. dile göre).
+func streamView[T, V any](w http.ResponseWriter, r *http.Request, cursor *storage.Cursor[T], opts storage.ListOptions, view func(T) V) {
+	defer cursor.Close()
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(cursor.Total))
+	w.Header().Set("Content-Type", "application/json")
+
+	legacy := isLegacy(r)
+	if legacy {
+		io.WriteString(w, "[")
+	} else {
+		io.WriteString(w, `{"data":[`)
+	}
+
+	if err := writeItems(w, cursor, view); err != nil {
+		log.Printf("[%s] %s %s: %v", requestID(r), r.Method, logPath(r), err)
+		return
+	}
+
+	if legacy {
+		io.WriteString(w, "]\n")
+		return
+	}
+	meta, _ := json.Marshal(newListMeta(cursor.Total, opts))
+	io.WriteString(w, `],"meta":`+string(meta)+"}\n")
+}
+
+// Cursor'daki kayıtları view ile dönüştürüp virgülle ayrılmış JSON olarak yazar; dizinin
+// köşeli parantezleri çağırana kalır.
+func writeItems[T, V any](w io.Writer, cursor *storage.Cursor[T], view func(T) V) error {
+	for n := 0; cursor.Next(); n++ {
+		item, err := cursor.Item()
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(view(item))
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			io.WriteString(w, ",")
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// limit, offset ve sort (ör. "sort=-ects,name") parametrelerini okur. Hatalı parametrenin adını döner.
+func parseListOptions(r *http.Request, sortFields map[string]string) (storage.ListOptions, string) {
+	q := r.URL.Query()
+	opts := storage.ListOptions{}
+	if !isLegacy(r) {
+		opts.Limit = defaultPageSize
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 || limit > maxPageSize {
+			return opts, "limit"
+		}
+		opts.Limit = limit
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return opts, "offset"
+		}
+		opts.Offset = offset
+	}
+
+	if v := q.Get("sort"); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			field = strings.TrimPrefix(field, "-")
+			if _, ok := sortFields[field]; !ok {
+				return opts, "sort"
+			}
+			opts.Sort = append(opts.Sort, storage.SortField{Field: field, Desc: desc})
+		}
+	}
+
+	return opts, ""
+}
+
+func queryBool(r *http.Request, name string) (*bool, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, false
+	}
+	return &b, true
+}
+
+func queryInt(r *http.Request, name string) (*int, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, true
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, false
+	}
+	return &i, true
+}
+
+func queryFloat(r *http.Request, name string) (*float64, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return nil, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &f, true
+}
@@ -0,0 +1,211 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token bucket ayarı: saniyede Rate jeton dolar, kova en fazla Burst jeton tutar.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Kovaların tutulduğu yer. Bellek içi uygulama tek sunucu içindir; birden fazla sunucu
+// için ortak bir depo (ör. Redis) aynı arayüzle takılabilir.
+type RateLimitStore interface {
+	// key için bir jeton harcar. Jeton yoksa ne kadar sonra tekrar denenebileceğini döner.
+	Take(key string, limit RateLimit, now time.Time) (allowed bool, retryAfter time.Duration)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type memoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+func NewMemoryRateLimitStore() RateLimitStore {
+	return &memoryRateLimitStore{buckets: make(map[string]*bucket)}
+}
+
+const sweepEvery = 10000
+
+func (s *memoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+}
+
+// Uzun süredir kullanılmayan kovalar dolmuş sayılır, silinmeleri davranışı değiştirmez.
+func (s *memoryRateLimitStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.last) > time.Hour {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+type rateLimiter struct {
+	store        RateLimitStore
+	defaultLimit RateLimit
+	routeLimits  map[string]RateLimit
+	allowedNets  []*net.IPNet
+	allowedKeys  map[string]bool
+	trustProxy   bool
+	proxyHops    int
+	knownKey     func(key string) bool
+	validKey     func(r *http.Request, key string) bool
+}
+
+func newRateLimiter(cfg Config) *rateLimiter {
+	rl := &rateLimiter{
+		store:        cfg.RateLimitStore,
+		defaultLimit: cfg.DefaultRateLimit,
+		routeLimits:  make(map[string]RateLimit),
+		allowedKeys:  make(map[string]bool),
+		trustProxy:   cfg.TrustProxyHeaders,
+		proxyHops:    max(1, cfg.TrustedProxyHops),
+		knownKey:     cfg.KnownAPIKey,
+		validKey:     cfg.ValidAPIKey,
+	}
+	if rl.store == nil {
+		rl.store = NewMemoryRateLimitStore()
+	}
+	if rl.defaultLimit.Rate <= 0 {
+		rl.defaultLimit = defaultRateLimit
+	}
+	for pattern, limit := range defaultRouteLimits {
+		rl.routeLimits[pattern] = limit
+	}
+	for pattern, limit := range cfg.RateLimits {
+		rl.routeLimits[pattern] = limit
+	}
+
+	// İzin listesindeki öğe IP ya da CIDR değilse API anahtarı kabul edilir.
+	for _, entry := range cfg.RateLimitAllowlist {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			rl.allowedNets = append(rl.allowedNets, ipNet)
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			rl.allowedNets = append(rl.allowedNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+		} else {
+			rl.allowedKeys[entry] = true
+		}
+	}
+	return rl
+}
+
+// İstemci adresi. Vekil sunucu arkasında çalışılıyorsa X-Forwarded-For'da sağdan proxyHops'uncu
+// adres, yani ilk güvenilir vekilin gördüğü adres alınır.
+func (rl *rateLimiter) clientIP(r *http.Request) string {
+	if rl.trustProxy {
+		var hops []string
+		for _, v := range r.Header.Values("X-Forwarded-For") {
+			hops = append(hops, strings.Split(v, ",")...)
+		}
+		if len(hops) > 0 {
+			ip := strings.TrimSpace(hops[max(0, len(hops)-rl.proxyHops)])
+			if net.ParseIP(ip) != nil {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (rl *rateLimiter) allowlisted(ip, key string) bool {
+	if key != "" && rl.allowedKeys[key] {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	for _, n := range rl.allowedNets {
+		if parsed != nil && n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Rotaya istemci başına limit uygular. Daha önce doğrulanmış API anahtarı kendi kovasından,
+// diğer istekler IP adresinin kovasından harcar. Tanınmayan anahtar ancak IP jetonu
+// harcandıktan sonra doğrulanır; her istekte yeni anahtar uyduran istemci IP'sine göre
+// sınırlanır ve veritabanına istek başına gidemez.
+func (rl *rateLimiter) limit(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	limit, ok := rl.routeLimits[pattern]
+	if !ok {
+		limit = rl.defaultLimit
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ip := rl.clientIP(r)
+		key := apiKeyFromRequest(r)
+		if rl.allowlisted(ip, key) {
+			next(w, r)
+			return
+		}
+
+		client := "ip:" + ip
+		known := key != "" && rl.knownKey != nil && rl.knownKey(key)
+		if known {
+			client = "key:" + key
+		}
+
+		allowed, retryAfter := rl.store.Take(pattern+"|"+client, limit, time.Now())
+		if !allowed {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+			respondError(w, r, http.StatusTooManyRequests, ErrRateLimited)
+			return
+		}
+		if key != "" && !known && rl.validKey != nil {
+			// Geçerliyse sonraki istekler anahtarın kovasından harcar.
+			rl.validKey(r, key)
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       Config
+		forwarded []string
+		want      string
+	}{
+		{"vekil yok", Config{}, []string{"1.1.1.1"}, "192.0.2.1"},
+		{"tek vekil", Config{TrustProxyHeaders: true}, []string{"6.6.6.6, 1.1.1.1"}, "1.1.1.1"},
+		{"iki vekil", Config{TrustProxyHeaders: true, TrustedProxyHops: 2}, []string{"6.6.6.6, 1.1.1.1, 10.0.0.2"}, "1.1.1.1"},
+		{"ayrı başlıklar", Config{TrustProxyHeaders: true}, []string{"6.6.6.6", "1.1.1.1"}, "1.1.1.1"},
+		{"az adres", Config{TrustProxyHeaders: true, TrustedProxyHops: 3}, []string{"1.1.1.1"}, "1.1.1.1"},
+		{"geçersiz adres", Config{TrustProxyHeaders: true}, []string{"1.1.1.1, bozuk"}, "192.0.2.1"},
+		{"başlık yok", Config{TrustProxyHeaders: true}, nil, "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "192.0.2.1:1234"
+		for _, v := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if got := newRateLimiter(tt.cfg).clientIP(r); got != tt.want {
+			t.Errorf("%s: clientIP = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimitForgedForwardedFor(t *testing.T) {
+	rl := newRateLimiter(Config{
+		TrustProxyHeaders:  true,
+		DefaultRateLimit:   RateLimit{Rate: 0.001, Burst: 1},
+		RateLimitAllowlist: []string{"10.0.0.0/8"},
+	})
+	handler := rl.limit("GET /", func(w http.ResponseWriter, r *http.Request) {})
+
+	// İstemcinin soluna eklediği izinli adres ne limiti ne de izin listesini etkiler.
+	for i, want := range []int{http.StatusOK, http.StatusTooManyRequests} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Forwarded-For", "10.0.0.1, 203.0.113.7")
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		if rec.Code != want {
+			t.Errorf("request %d: status = %d, want %d", i, rec.Code, want)
+		}
+	}
+}
+
+func TestRateLimitAPIKeys(t *testing.T) {
+	validated := map[string]bool{}
+	lookups := 0
+	rl := newRateLimiter(Config{
+		DefaultRateLimit: RateLimit{Rate: 0.001, Burst: 1},
+		KnownAPIKey:      func(key string) bool { return validated[key] },
+		ValidAPIKey: func(r *http.Request, key string) bool {
+			lookups++
+			if key == "gecerli" {
+				validated[key] = true
+			}
+			return validated[key]
+		},
+	})
+	handler := rl.limit("GET /", func(w http.ResponseWriter, r *http.Request) {})
+	send := func(header, value string) int {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(header, value)
+		rec := httptest.NewRecorder()
+		handler(rec, r)
+		return rec.Code
+	}
+
+	// İlk istek IP kovasından harcar ve anahtarı doğrular; sonraki istekler anahtarın kovasından.
+	if code := send("Authorization", "Bearer gecerli"); code != http.StatusOK {
+		t.Fatalf("first request: status = %d", code)
+	}
+	if code := send("X-API-Key", "gecerli"); code != http.StatusOK {
+		t.Errorf("validated key: status = %d, want 200", code)
+	}
+	// Uydurulan anahtarlar IP kovasında sayılır ve limit aşılınca doğrulanmaz.
+	for i, want := range []int{http.StatusTooManyRequests, http.StatusTooManyRequests} {
+		if code := send("X-API-Key", "uydurma"+strconv.Itoa(i)); code != want {
+			t.Errorf("random key %d: status = %d, want %d", i, code, want)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("lookups = %d, want 1", lookups)
+	}
+}
+
+func TestAPIKeyCacheSkipsInvalidKeys(t *testing.T) {
+	db := testDB(t)
+	h := NewHandler(db)
+	key, _, err := storage.CreateAPIKey(db, "okuyucu", models.RoleReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+
+	if k, err := h.authenticate(r, "gecersiz"); err != nil || k != nil {
+		t.Fatalf("invalid key: %v, %v", k, err)
+	}
+	if k, err := h.authenticate(r, key); err != nil || k == nil {
+		t.Fatalf("valid key: %v, %v", k, err)
+	}
+	if len(h.keys.entries) != 1 || h.knownAPIKey("gecersiz") || !h.knownAPIKey(key) {
+		t.Errorf("cache = %v entries, want only the valid key", len(h.keys.entries))
+	}
+}
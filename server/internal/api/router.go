@@ -1,14 +1,124 @@
 package api
 
-import "net/http"
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+)
 
-func SetupRoutes(h *Handler) *http.ServeMux {
-	mux := http.NewServeMux()
+var successorParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// Eski rotalarda yol parametresi yerine kullanılan sorgu parametreleri
+var legacyQueryParams = map[string]string{"guid": "id"}
+
+// Eski (sürümsüz) rotalar v1 karşılıklarına yönlendirilmeden çalışmaya devam eder,
+// yanıtlarına kullanımdan kaldırıldıklarını belirten başlıklar eklenir.
+func deprecated(successor string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+		if link, ok := successorPath(successor, r); ok {
+			w.Header().Set("Link", "<"+link+`>; rel="successor-version"`)
+		}
+		next(w, withLegacyResponse(r))
+	}
+}
 
-	mux.HandleFunc("/api/faculties", h.GetFaculties)
-	mux.HandleFunc("/api/departments", h.GetDepartments)
-	mux.HandleFunc("/api/courses", h.GetCourses)
-	mux.HandleFunc("/api/course-detail", h.GetCourseDetail)
+// Yeni rotanın kalıbını isteğin yol ya da sorgu parametreleriyle doldurur. Eksik parametre
+// varsa false döner.
+func successorPath(pattern string, r *http.Request) (string, bool) {
+	ok := true
+	path := successorParam.ReplaceAllStringFunc(pattern, func(m string) string {
+		name := m[1 : len(m)-1]
+		value := r.PathValue(name)
+		if value == "" {
+			if alias, found := legacyQueryParams[name]; found {
+				value = r.URL.Query().Get(alias)
+			} else {
+				value = r.URL.Query().Get(name)
+			}
+		}
+		if value == "" {
+			ok = false
+		}
+		return url.PathEscape(value)
+	})
+	return path, ok
+}
+
+// Sunucu ayarları
+type Config struct {
+	// CORS için izin verilen kökenler. "*" tüm kökenlere izin verir, boşsa CORS başlığı eklenmez.
+	AllowedOrigins []string
+
+	// Rotası RateLimits'te olmayan isteklerin limiti. Boşsa defaultRateLimit kullanılır.
+	DefaultRateLimit RateLimit
+	// Rota kalıbına (ör. "GET /api/v1/bundle") göre limitler, defaultRouteLimits'i ezer.
+	RateLimits map[string]RateLimit
+	// Limit uygulanmayan IP, CIDR ya da API anahtarları (ör. kendi mobil arka ucumuz)
+	RateLimitAllowlist []string
+	// Boşsa bellek içi depo kullanılır.
+	RateLimitStore RateLimitStore
+	// Sunucu vekil arkasındaysa istemci adresi X-Forwarded-For'dan okunur.
+	TrustProxyHeaders bool
+	// Önümüzdeki güvenilir vekil sayısı; X-Forwarded-For'un sağından bu kadar adres sayılır.
+	// 0 ise 1 kabul edilir. Soldaki adresler istemci tarafından uydurulabilir.
+	TrustedProxyHops int
+	// Verilirse geçerli API anahtarları IP yerine anahtar başına sınırlandırılır. KnownAPIKey
+	// sadece daha önce doğrulanmış anahtarları tanır ve veritabanına gitmez; ValidAPIKey
+	// anahtarı doğrular ve sadece IP jetonu harcandıktan sonra çağrılır.
+	KnownAPIKey func(key string) bool
+	ValidAPIKey func(r *http.Request, key string) bool
+}
+
+var defaultRateLimit = RateLimit{Rate: 10, Burst: 40}
+
+// Pahalı rotaların varsayılan limitleri
+var defaultRouteLimits = map[string]RateLimit{
+	"GET /api/v1/bundle":                      {Rate: 0.2, Burst: 5},
+	"GET /api/v1/sync":                        {Rate: 0.5, Burst: 5},
+	"POST /api/v1/admin/equivalences/compute": {Rate: 0.01, Burst: 1},
+	// Akışlar kimlik doğrulamasız oluşturulur; istemci başına saatte 60 akış.
+	"POST /api/v1/timetable/feeds": {Rate: 1.0 / 60, Burst: 10},
+}
+
+// Rota zaman aşımları. Süre dolduğunda istek bağlamı iptal edilir, çalışan sorgu da durur.
+const (
+	readTimeout = 10 * time.Second
+	// Listeler akış halinde yazıldığı için tam liste istekleri daha uzun sürebilir.
+	listTimeout    = 30 * time.Second
+	computeTimeout = 5 * time.Minute
+)
+
+func SetupRoutes(h *Handler, cfg Config) http.Handler {
+	if cfg.KnownAPIKey == nil {
+		cfg.KnownAPIKey = h.knownAPIKey
+	}
+	if cfg.ValidAPIKey == nil {
+		cfg.ValidAPIKey = h.validAPIKey
+	}
+
+	mux := http.NewServeMux()
+	limiter := newRateLimiter(cfg)
+	for _, rt := range h.routes() {
+		handler := rt.Handler
+		if rt.Cached {
+			handler = h.cached(rt.CacheScope, handler)
+		}
+		if rt.Successor != "" {
+			handler = deprecated(rt.Successor, handler)
+		}
+		if rt.Role != "" {
+			handler = h.requireRole(rt.Role, handler)
+		}
+		mux.HandleFunc(rt.Pattern, withTimeout(rt.Timeout, limiter.limit(rt.Pattern, handler)))
+	}
 
-	return mux
+	return Chain(withJSONFallback(mux),
+		withRequestID,
+		withAccessLog,
+		withRecovery,
+		withCORS(cfg.AllowedOrigins),
+		withCompression,
+	)
 }
@@ -0,0 +1,271 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/graphql"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// Sorgu parametresi belgesi
+type paramDoc struct {
+	Name        string
+	Type        string // string, integer, number, boolean
+	Description string
+	Required    bool
+	Enum        []string
+}
+
+// Rotanın OpenAPI belgesi. Request/Response örnek değerlerinin tipinden şema üretilir.
+type routeDoc struct {
+	Summary  string
+	Tag      string
+	Query    []paramDoc
+	Request  interface{}
+	Response interface{}
+	// Response bir liste elemanıdır; v1'de {data, meta} zarfı, eski rotada düz dizi döner.
+	List bool
+	// Başarılı yanıt kodu, varsayılan 200
+	Status int
+	// Varsayılan application/json
+	ContentType string
+}
+
+// Sunucunun tüm rotaları. Hem mux kaydı hem de OpenAPI belgesi bu tablodan üretilir.
+type route struct {
+	Pattern string
+	Handler http.HandlerFunc
+	Timeout time.Duration
+	// Doluysa rota bu role sahip API anahtarı ister.
+	Role models.Role
+	// Veri sürümüne bağlı ETag ve yanıt önbelleği
+	Cached bool
+	// Önbelleğin bağlı olduğu sürüm; boşsa katalog sürümü
+	CacheScope storage.VersionScope
+	// Doluysa rota kullanımdan kaldırılmıştır, değer yerine geçen v1 rotasıdır.
+	Successor string
+	Doc       routeDoc
+}
+
+type computeResult struct {
+	Candidates int `json:"candidates"`
+}
+
+var langParam = paramDoc{Name: "lang", Type: "string", Description: "Yanıt dili, Accept-Language yerine", Enum: []string{"tr", "en"}}
+
+func sortFieldNames(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func listParams(sortFields map[string]string) []paramDoc {
+	return []paramDoc{
+		{Name: "limit", Type: "integer", Description: "Sayfa boyutu (v1'de varsayılan 50, en fazla 500)"},
+		{Name: "offset", Type: "integer", Description: "Atlanacak kayıt sayısı"},
+		{Name: "sort", Type: "string", Description: "Virgülle ayrılmış alanlar, azalan sıra için başına - (ör. -ects,name). Alanlar: " + sortFieldNames(sortFields)},
+	}
+}
+
+var courseFilterParams = []paramDoc{
+	{Name: "semester", Type: "string", Description: "Yarıyıl (ör. \"1. Yarıyıl\")"},
+	{Name: "is_mandatory", Type: "boolean"},
+	{Name: "is_removed", Type: "boolean", Description: "Müfredattan kaldırılmış dersler"},
+	{Name: "year", Type: "integer", Description: "Müfredat yılı"},
+	{Name: "ects_min", Type: "number"},
+	{Name: "ects_max", Type: "number"},
+	{Name: "language", Type: "string", Description: "İzlencedeki ders dili"},
+}
+
+var calendarParams = []paramDoc{
+	{Name: "year", Type: "string", Description: "Akademik yıl (ör. 2025-2026)"},
+	{Name: "term", Type: "string", Enum: []string{models.TermFall, models.TermSpring, models.TermSummer}},
+	{Name: "category", Type: "string", Description: "Virgülle ayrılmış kategoriler: " + strings.Join(models.CalendarCategories, ", ")},
+	{Name: "from", Type: "string", Description: "Bu tarihten (YYYY-AA-GG) sonra biten etkinlikler"},
+	{Name: "to", Type: "string", Description: "Bu tarihten (YYYY-AA-GG) önce başlayan etkinlikler"},
+}
+
+var timetableParams = []paramDoc{
+	{Name: "semester", Type: "string", Description: "Yarıyıl (ör. \"1. Yarıyıl\")"},
+	{Name: "day", Type: "string", Enum: []string{"Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma"}},
+	{Name: "course_code", Type: "string"},
+}
+
+func (h *Handler) routes() []route {
+	facultiesDoc := routeDoc{
+		Summary: "Fakülteleri listeler", Tag: "Katalog", List: true, Response: models.LocalizedFaculty{},
+		Query: append([]paramDoc{{Name: "q", Type: "string", Description: "Ada göre arama"}}, listParams(storage.FacultySortFields)...),
+	}
+	departmentsDoc := routeDoc{
+		Summary: "Bölümleri listeler", Tag: "Katalog", List: true, Response: models.LocalizedDepartment{},
+		Query: append([]paramDoc{{Name: "q", Type: "string", Description: "Ada göre arama"}}, listParams(storage.DepartmentSortFields)...),
+	}
+	coursesDoc := routeDoc{
+		Summary: "Bölümün derslerini listeler", Tag: "Katalog", List: true, Response: models.Course{},
+		Query: append(append([]paramDoc{}, courseFilterParams...), listParams(storage.CourseSortFields)...),
+	}
+	courseDetailDoc := routeDoc{Summary: "Ders izlencesini döner", Tag: "Katalog", Response: models.CourseDetail{}}
+	electivesDoc := routeDoc{Summary: "Bölümün seçmeli ders havuzları", Tag: "Katalog", Response: []models.ElectiveGroup{}}
+	equivalentsDoc := routeDoc{Summary: "Dersin onaylı eşdeğerleri", Tag: "Katalog", Response: []models.CourseEquivalence{}}
+	auditDoc := routeDoc{Summary: "Mezuniyet şartlarına göre ilerleme", Tag: "Akademik", Request: auditRequest{}, Response: academic.AuditResult{}}
+	gpaDoc := routeDoc{Summary: "YANO/AGNO ve akademik durum hesabı, what-if simülasyonu", Tag: "Akademik", Request: gpaRequest{}, Response: gpaResponse{}}
+	recommendDoc := routeDoc{Summary: "Hedef dönem için ders önerisi", Tag: "Akademik", Request: recommendRequest{}, Response: academic.PlanResult{}}
+
+	// Eski rotalar fakülte ve bölüm adlarını iki dilde ham döner.
+	legacyFacultiesDoc := facultiesDoc
+	legacyFacultiesDoc.Response = models.Faculty{}
+	legacyCoursesDoc := coursesDoc
+	legacyCoursesDoc.Query = append([]paramDoc{{Name: "id", Type: "string", Description: "Bölüm guid", Required: true}}, coursesDoc.Query...)
+	legacyDepartmentsDoc := departmentsDoc
+	legacyDepartmentsDoc.Query = append([]paramDoc{{Name: "faculty_id", Type: "integer"}}, departmentsDoc.Query...)
+	legacyDepartmentsDoc.Response = models.Department{}
+	legacyDetailDoc := courseDetailDoc
+	legacyDetailDoc.Query = []paramDoc{{Name: "code", Type: "string", Description: "Ders kodu", Required: true}}
+
+	return []route{
+		{Pattern: "GET /api/v1/faculties", Handler: h.GetFaculties, Timeout: listTimeout, Cached: true, Doc: facultiesDoc},
+		{Pattern: "GET /api/v1/faculties/{id}/departments", Handler: h.GetDepartments, Timeout: listTimeout, Cached: true, Doc: departmentsDoc},
+		{Pattern: "GET /api/v1/departments", Handler: h.GetDepartments, Timeout: listTimeout, Cached: true, Doc: departmentsDoc},
+		{Pattern: "GET /api/v1/departments/{guid}", Handler: h.GetDepartment, Timeout: readTimeout, Cached: true,
+			Doc: routeDoc{Summary: "Tek bir bölümü döner", Tag: "Katalog", Response: models.LocalizedDepartment{}}},
+		{Pattern: "GET /api/v1/departments/{guid}/courses", Handler: h.GetCourses, Timeout: listTimeout, Cached: true, Doc: coursesDoc},
+		{Pattern: "GET /api/v1/departments/{guid}/electives", Handler: h.GetElectiveGroups, Timeout: readTimeout, Cached: true, Doc: electivesDoc},
+		{Pattern: "GET /api/v1/departments/{guid}/timetable", Handler: h.GetTimetable, Timeout: listTimeout, Cached: true,
+			Doc: routeDoc{Summary: "Bölümün haftalık ders programı", Tag: "Katalog", List: true, Response: models.TimetableSlot{},
+				Query: append(append([]paramDoc{}, timetableParams...), listParams(storage.TimetableSortFields)...)}},
+		{Pattern: "GET /api/v1/courses/{code}", Handler: h.GetCourseDetail, Timeout: readTimeout, Cached: true, Doc: courseDetailDoc},
+		{Pattern: "GET /api/v1/courses/{code}/equivalents", Handler: h.GetCourseEquivalents, Timeout: readTimeout, Cached: true, Doc: equivalentsDoc},
+		{Pattern: "GET /api/v1/bundle", Handler: h.GetBundle, Timeout: listTimeout,
+			Doc: routeDoc{Summary: "Bölümün çevrimdışı veri paketi", Tag: "Çevrimdışı", Response: models.Bundle{}, Query: []paramDoc{
+				{Name: "department", Type: "string", Description: "Bölüm guid", Required: true},
+				{Name: "version", Type: "string", Description: "İstemcideki paket özeti; değişmediyse 304 döner"},
+			}}},
+		{Pattern: "GET /api/v1/sync", Handler: h.GetSync, Timeout: listTimeout,
+			Doc: routeDoc{Summary: "Jetondan bu yana değişen kayıtlar", Tag: "Çevrimdışı", Response: models.SyncChanges{}, Query: []paramDoc{
+				{Name: "since", Type: "integer", Description: "Önceki eşitlemeden dönen jeton; verilmezse tüm veri döner"},
+			}}},
+		{Pattern: "GET /api/v1/announcements", Handler: h.GetAnnouncements, Timeout: listTimeout, Cached: true, CacheScope: storage.AnnouncementVersion,
+			Doc: routeDoc{Summary: "Üniversite CMS duyuruları, en yeni önce", Tag: "Duyurular", List: true, Response: models.Announcement{},
+				Query: append([]paramDoc{
+					{Name: "site", Type: "string", Description: "CMS site anahtarı (siteKey)"},
+					{Name: "category", Type: "integer", Description: "CMS kategori id'si"},
+					{Name: "since", Type: "string", Description: "Sunucunun bu zamandan sonra ilk kez gördüğü duyurular (RFC3339 ya da YYYY-AA-GG)"},
+				}, listParams(storage.AnnouncementSortFields)...)}},
+
+		{Pattern: "GET /api/v1/calendar", Handler: h.GetCalendar, Timeout: listTimeout, Cached: true,
+			Doc: routeDoc{Summary: "Akademik takvim: kayıt, ekle-sil, sınav dönemleri ve tatiller", Tag: "Takvim", List: true, Response: models.CalendarEvent{},
+				Query: append(append([]paramDoc{}, calendarParams...), listParams(storage.CalendarSortFields)...)}},
+		{Pattern: "GET /feeds/calendar.ics", Handler: h.GetCalendarFeed, Timeout: readTimeout, Cached: true,
+			Doc: routeDoc{Summary: "Takvim uygulamalarına abone olunabilen akademik takvim (iCalendar)", Tag: "Takvim",
+				ContentType: "text/calendar", Query: calendarParams}},
+		{Pattern: "GET /feeds/timetable/{file}", Handler: h.GetTimetableFeed, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Kişisel ders programı takvimi; dönem boyunca haftalık tekrarlanır, tatiller çıkarılır ({token}.ics)", Tag: "Takvim",
+				ContentType: "text/calendar"}},
+
+		{Pattern: "POST /api/v1/devices", Handler: h.PostDevice, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Cihazı bildirimler için kaydeder; aynı jetonla tekrar kayıt günceller", Tag: "Bildirimler",
+				Request: deviceRequest{}, Response: models.Device{}, Status: http.StatusCreated}},
+		{Pattern: "GET /api/v1/devices/{id}", Handler: h.GetDevice, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Cihazın kaydı ve abonelikleri", Tag: "Bildirimler", Response: models.Device{}}},
+		{Pattern: "PUT /api/v1/devices/{id}", Handler: h.PutDevice, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Cihazın jetonunu, dilini ve aboneliklerini değiştirir", Tag: "Bildirimler",
+				Request: deviceRequest{}, Response: models.Device{}}},
+		{Pattern: "DELETE /api/v1/devices/{id}", Handler: h.DeleteDevice, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Cihazın kaydını siler", Tag: "Bildirimler", Status: http.StatusNoContent}},
+
+		{Pattern: "GET /feeds/announcements/{file}", Handler: h.GetAnnouncementFeed, Timeout: readTimeout, Cached: true, CacheScope: storage.AnnouncementVersion,
+			Doc: routeDoc{Summary: "Sitenin duyuru akışı: {site}.atom, {site}.rss ya da {site}.json (JSON Feed)", Tag: "Akışlar",
+				ContentType: "application/atom+xml", Query: []paramDoc{
+					{Name: "category", Type: "integer", Description: "CMS kategori id'si"},
+				}}},
+		{Pattern: "GET /feeds/departments/{guid}/{file}", Handler: h.GetDepartmentChangesFeed, Timeout: readTimeout, Cached: true,
+			Doc: routeDoc{Summary: "Bölümün müfredat değişiklikleri: changes.rss, changes.atom ya da changes.json (JSON Feed)", Tag: "Akışlar",
+				ContentType: "application/rss+xml"}},
+
+		{Pattern: "POST /api/v1/audit", Handler: h.PostAudit, Timeout: readTimeout, Doc: auditDoc},
+		{Pattern: "POST /api/v1/gpa", Handler: h.PostGPA, Timeout: readTimeout, Doc: gpaDoc},
+		{Pattern: "GET /api/v1/regulations", Handler: h.GetRegulations, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Not ortalaması ve ders yükü hesaplarında kullanılan yönetmelikler (yürürlük yılına göre)", Tag: "Akademik",
+				Response: []academic.Regulation{}}},
+		{Pattern: "POST /api/v1/plan/recommend", Handler: h.PostRecommend, Timeout: readTimeout, Doc: recommendDoc},
+		{Pattern: "POST /api/v1/timetable/conflicts", Handler: h.PostTimetableConflicts, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Seçilen derslerin ders programındaki çakışmaları ve çakışmayı çözen şubeler", Tag: "Akademik",
+				Request: selectionRequest{}, Response: academic.ConflictResult{}}},
+		{Pattern: "POST /api/v1/timetable/feeds", Handler: h.PostTimetableFeed, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Seçilen derslerin haftalık programı için kişisel takvim (.ics) adresi oluşturur; birden fazla şubesi olan derste şube zorunludur, 90 gün okunmayan adres silinir", Tag: "Takvim",
+				Request: selectionRequest{}, Response: models.TimetableFeed{}, Status: http.StatusCreated}},
+		{Pattern: "DELETE /api/v1/timetable/feeds/{token}", Handler: h.DeleteTimetableFeed, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Kişisel takvim adresini iptal eder", Tag: "Takvim", Status: http.StatusNoContent}},
+
+		{Pattern: "GET /api/v1/admin/scrape-runs", Handler: h.GetScrapeRuns, Timeout: readTimeout, Role: models.RoleReader,
+			Doc: routeDoc{Summary: "Tarama geçmişi", Tag: "Yönetim", Response: []models.ScrapeRun{}, Query: []paramDoc{
+				{Name: "limit", Type: "integer", Description: "Varsayılan 20"},
+			}}},
+		{Pattern: "POST /api/v1/admin/scrape-runs", Handler: h.PostScrapeRun, Timeout: readTimeout, Role: models.RoleOperator,
+			Doc: routeDoc{Summary: "EBS taramasını hemen başlatır; sonuç tarama geçmişinde görünür", Tag: "Yönetim", Status: http.StatusAccepted}},
+		{Pattern: "GET /api/v1/admin/equivalences", Handler: h.GetEquivalences, Timeout: readTimeout, Role: models.RoleReader,
+			Doc: routeDoc{Summary: "Eşdeğerlik çiftlerini duruma göre listeler", Tag: "Yönetim", Response: []models.CourseEquivalence{}, Query: []paramDoc{
+				{Name: "status", Type: "string", Enum: []string{"candidate", "confirmed", "rejected"}},
+				{Name: "limit", Type: "integer", Description: "Varsayılan 100"},
+			}}},
+		{Pattern: "POST /api/v1/admin/equivalences/compute", Handler: h.PostComputeEquivalences, Timeout: computeTimeout, Role: models.RoleOperator,
+			Doc: routeDoc{Summary: "Eşdeğerlik adaylarını yeniden hesaplar", Tag: "Yönetim", Response: computeResult{}}},
+		{Pattern: "PUT /api/v1/admin/equivalences/{code}/{other}", Handler: h.PutEquivalence, Timeout: readTimeout, Role: models.RoleAdmin,
+			Doc: routeDoc{Summary: "Eşdeğerlik çiftini onaylar ya da reddeder", Tag: "Yönetim", Request: equivalenceReview{}, Status: http.StatusNoContent}},
+		{Pattern: "PUT /api/v1/admin/departments/{guid}/timetable-source", Handler: h.PutTimetableSource, Timeout: readTimeout, Role: models.RoleAdmin,
+			Doc: routeDoc{Summary: "Bölümün ders programı PDF kaynağını ayarlar", Tag: "Yönetim", Request: timetableSourceRequest{}, Status: http.StatusNoContent}},
+		{Pattern: "GET /api/v1/admin/timetables", Handler: h.GetTimetableSources, Timeout: readTimeout, Role: models.RoleReader,
+			Doc: routeDoc{Summary: "Ders programı kaynakları ve son alım sonuçları", Tag: "Yönetim", Response: []models.TimetableSource{}}},
+		{Pattern: "POST /api/v1/admin/timetables/ingest", Handler: h.PostIngestTimetables, Timeout: readTimeout, Role: models.RoleOperator,
+			Doc: routeDoc{Summary: "Ders programı alımını hemen başlatır; sonuç kaynak listesinde görünür", Tag: "Yönetim", Status: http.StatusAccepted}},
+		{Pattern: "POST /api/v1/admin/webhooks", Handler: h.PostWebhook, Timeout: readTimeout, Role: models.RoleAdmin,
+			Doc: routeDoc{Summary: "Webhook kaydeder; imza anahtarı sadece bu yanıtta döner", Tag: "Webhooks",
+				Request: webhookRequest{}, Response: models.Webhook{}, Status: http.StatusCreated}},
+		{Pattern: "GET /api/v1/admin/webhooks", Handler: h.GetWebhooks, Timeout: readTimeout, Role: models.RoleReader,
+			Doc: routeDoc{Summary: "Kayıtlı webhook'lar", Tag: "Webhooks", Response: []models.Webhook{}}},
+		{Pattern: "DELETE /api/v1/admin/webhooks/{id}", Handler: h.DeleteWebhook, Timeout: readTimeout, Role: models.RoleAdmin,
+			Doc: routeDoc{Summary: "Webhook'u ve gönderim günlüğünü siler", Tag: "Webhooks", Status: http.StatusNoContent}},
+		{Pattern: "GET /api/v1/admin/webhooks/{id}/deliveries", Handler: h.GetWebhookDeliveries, Timeout: readTimeout, Role: models.RoleReader,
+			Doc: routeDoc{Summary: "Webhook'un gönderim günlüğü, yeniden eskiye", Tag: "Webhooks", Response: []models.WebhookDelivery{}, Query: []paramDoc{
+				{Name: "status", Type: "string", Enum: []string{"pending", "succeeded", "failed"}},
+				{Name: "limit", Type: "integer", Description: "Varsayılan 50"},
+			}}},
+		{Pattern: "POST /api/v1/admin/webhooks/deliveries/{id}/replay", Handler: h.PostReplayDelivery, Timeout: readTimeout, Role: models.RoleAdmin,
+			Doc: routeDoc{Summary: "Gönderimin olayını tekrar gönderir", Tag: "Webhooks", Response: models.WebhookDelivery{}, Status: http.StatusAccepted}},
+
+		{Pattern: "POST /graphql", Handler: h.PostGraphQL, Timeout: listTimeout,
+			Doc: routeDoc{Summary: "GraphQL sorgusu çalıştırır; şema /graphql/schema adresinde", Tag: "GraphQL", Request: graphql.Request{}, Response: graphql.Response{}}},
+		{Pattern: "GET /graphql", Handler: h.GetGraphQL, Timeout: listTimeout,
+			Doc: routeDoc{Summary: "GraphQL sorgusunu sorgu parametreleriyle çalıştırır", Tag: "GraphQL", Response: graphql.Response{}, Query: []paramDoc{
+				{Name: "query", Type: "string", Required: true},
+				{Name: "variables", Type: "string", Description: "JSON nesnesi"},
+				{Name: "operationName", Type: "string"},
+			}}},
+		{Pattern: "GET /graphql/schema", Handler: h.GetGraphQLSchema, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "GraphQL şeması (SDL)", Tag: "GraphQL", ContentType: "text/plain"}},
+
+		{Pattern: "GET /api/openapi.json", Handler: h.GetOpenAPI, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Bu belge", Tag: "Belgeler"}},
+		{Pattern: "GET /api/docs", Handler: h.GetDocs, Timeout: readTimeout,
+			Doc: routeDoc{Summary: "Redoc arayüzü", Tag: "Belgeler", ContentType: "text/html"}},
+
+		// Kullanımdan kaldırılan rotalar
+		{Pattern: "GET /api/faculties", Handler: h.GetFaculties, Timeout: listTimeout, Cached: true, Successor: "/api/v1/faculties", Doc: legacyFacultiesDoc},
+		{Pattern: "GET /api/departments", Handler: h.GetDepartments, Timeout: listTimeout, Cached: true, Successor: "/api/v1/departments", Doc: legacyDepartmentsDoc},
+		{Pattern: "GET /api/courses", Handler: h.GetCourses, Timeout: listTimeout, Cached: true, Successor: "/api/v1/departments/{guid}/courses", Doc: legacyCoursesDoc},
+		{Pattern: "GET /api/course-detail", Handler: h.GetCourseDetail, Timeout: readTimeout, Cached: true, Successor: "/api/v1/courses/{code}", Doc: legacyDetailDoc},
+		{Pattern: "GET /api/departments/{guid}/electives", Handler: h.GetElectiveGroups, Timeout: readTimeout, Cached: true, Successor: "/api/v1/departments/{guid}/electives", Doc: electivesDoc},
+		{Pattern: "GET /api/courses/{code}/equivalents", Handler: h.GetCourseEquivalents, Timeout: readTimeout, Cached: true, Successor: "/api/v1/courses/{code}/equivalents", Doc: equivalentsDoc},
+		{Pattern: "POST /api/audit", Handler: h.PostAudit, Timeout: readTimeout, Successor: "/api/v1/audit", Doc: auditDoc},
+		{Pattern: "POST /api/gpa", Handler: h.PostGPA, Timeout: readTimeout, Successor: "/api/v1/gpa", Doc: gpaDoc},
+		{Pattern: "POST /api/plan/recommend", Handler: h.PostRecommend, Timeout: readTimeout, Successor: "/api/v1/plan/recommend", Doc: recommendDoc},
+	}
+}
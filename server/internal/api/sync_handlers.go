@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"companion_server/internal/storage"
+)
+
+// since jetonundan bu yana değişen fakülte, bölüm, ders ve ders detaylarını yeni jetonla döner.
+// Jeton verilmezse tüm veri döner. Kayıtlar tek okuma işleminden okundukça yazılır; yazım
+// başladıktan sonra oluşan hata sadece loglanır ve yanıt yarım kalır.
+func (h *Handler) GetSync(w http.ResponseWriter, r *http.Request) {
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "since")
+			return
+		}
+		since = parsed
+	}
+
+	reader, err := storage.BeginSync(h.db(r), since)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	defer reader.Close()
+
+	faculties, err := reader.Faculties()
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	token, _ := json.Marshal(reader.Token)
+	fmt.Fprintf(w, `{"token":%s,"full":%t`, token, reader.Full)
+	if err := writeSyncSection(w, "faculties", faculties, nil); err == nil {
+		err = streamSync(w, reader)
+	}
+	if err != nil {
+		log.Printf("[%s] %s %s: %v", requestID(r), r.Method, logPath(r), err)
+		return
+	}
+	io.WriteString(w, "}\n")
+}
+
+// Fakülteler dışındaki bölümleri models.SyncChanges'teki sırayla yazar.
+func streamSync(w io.Writer, reader *storage.SyncReader) error {
+	departments, err := reader.Departments()
+	if err := writeSyncSection(w, "departments", departments, err); err != nil {
+		return err
+	}
+	courses, err := reader.Courses()
+	if err := writeSyncSection(w, "courses", courses, err); err != nil {
+		return err
+	}
+	details, err := reader.Details()
+	if err := writeSyncSection(w, "details", details, err); err != nil {
+		return err
+	}
+	removed, err := reader.Removed()
+	return writeSyncSection(w, "removed", removed, err)
+}
+
+func writeSyncSection[T any](w io.Writer, name string, cursor *storage.Cursor[T], err error) error {
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+	io.WriteString(w, `,"`+name+`":[`)
+	if err := writeItems(w, cursor, func(item T) T { return item }); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestGetSync(t *testing.T) {
+	router := SetupRoutes(NewHandler(testDB(t)), Config{})
+
+	rec := serve(t, router, "GET", "/api/v1/sync", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var full models.SyncChanges
+	if err := json.Unmarshal(rec.Body.Bytes(), &full); err != nil {
+		t.Fatalf("%v: %s", err, rec.Body)
+	}
+	if !full.Full || full.Token == "" || len(full.Faculties) != 1 || len(full.Departments) != 1 ||
+		len(full.Courses) != 3 || len(full.Details) != 1 || full.Details[0].Aim != "Analiz" {
+		t.Errorf("full sync = %+v", full)
+	}
+
+	rec = serve(t, router, "GET", "/api/v1/sync?since="+full.Token, "")
+	want := `{"token":"` + full.Token + `","full":false,"faculties":[],"departments":[],"courses":[],"details":[],"removed":[]}` + "\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != want {
+		t.Errorf("up to date: status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	if rec := serve(t, router, "GET", "/api/v1/sync?since=-1", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("since=-1: status = %d, want 400", rec.Code)
+	}
+}
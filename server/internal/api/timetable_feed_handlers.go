@@ -0,0 +1,319 @@
+package api
+
+import (
+	"crypto/sha1"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+	"companion_server/internal/timetable"
+)
+
+// Akademik takvimde sadece başlangıç günü bulunan dönemin varsayılan uzunluğu
+const defaultTermWeeks = 14
+
+// Öğrencinin seçtiği dersler için kişisel takvim adresi oluşturur. Adres anahtarı sadece
+// bu yanıtta döner; akış her istekte güncel ders programından üretilir. Kimlik doğrulaması
+// olmadığı için oluşturma istemci başına sınırlıdır, uzun süre okunmayan akışlar silinir.
+func (h *Handler) PostTimetableFeed(w http.ResponseWriter, r *http.Request) {
+	var req selectionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSelectionBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	q, ok := h.selectionQuery(w, r, req)
+	if !ok {
+		return
+	}
+	for i, c := range req.Courses {
+		req.Courses[i].Section = academic.NormalizeSection(c.Section)
+	}
+
+	// Şubesi seçilmeyen ders bütün şubeleriyle takvime girer; birden fazla şubesi olan
+	// derste bu çakışan etkinlikler üretir.
+	db := h.db(r)
+	slots, _, err := storage.ListTimetableSlots(db, q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if code := ambiguousSection(req.Courses, slots); code != "" {
+		respondError(w, r, http.StatusBadRequest, ErrSectionRequired, code)
+		return
+	}
+
+	feed, err := storage.CreateTimetableFeed(db, q.DepartmentID, req.Courses)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	feed.DepartmentGUID = req.DepartmentGUID
+	feed.URL = h.absoluteURL(r, "/feeds/timetable/"+feed.Token+".ics")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(feed)
+}
+
+// Kişisel takvim adresini iptal eder.
+func (h *Handler) DeleteTimetableFeed(w http.ResponseWriter, r *http.Request) {
+	deleted, err := storage.DeleteTimetableFeed(h.db(r), r.PathValue("token"))
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if !deleted {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Seçilen derslerin haftalık tekrarlanan etkinlikleri. Etkinlikler akademik takvimdeki
+// dönemin ilk ve son ders günüyle sınırlıdır, tatil günleri çıkarılır. Dönem takvimde
+// yoksa akış boş döner.
+func (h *Handler) GetTimetableFeed(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+
+	db := h.db(r)
+	feed, err := storage.GetTimetableFeed(db, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+
+	q := storage.TimetableQuery{DepartmentID: feed.DepartmentID}
+	for _, c := range feed.Courses {
+		q.CourseCodes = append(q.CourseCodes, c.Code)
+	}
+	slots, _, err := storage.ListTimetableSlots(db, q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	term, found, err := currentTerm(db, time.Now().In(models.Istanbul))
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	var holidays []time.Time
+	if found {
+		if holidays, err = termHolidays(db, term); err != nil {
+			respondInternalError(w, r, err)
+			return
+		}
+	}
+	// DTSTAMP veri sürümünden alınır; program ya da takvim değişmedikçe akış aynı kalır.
+	version, err := storage.GetDataVersion(db)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	stamp := version.UpdatedAt
+	if stamp.IsZero() {
+		stamp = feed.CreatedAt
+	}
+
+	cal := icsCalendar{
+		Name:        "Ders Programım",
+		Description: "Seçilen derslerin haftalık programı (İÜC Companion)",
+	}
+	if found {
+		for _, s := range selectedSlots(feed.Courses, slots) {
+			if e, ok := weeklyEvent(s, term, holidays, stamp); ok {
+				cal.Events = append(cal.Events, e)
+			}
+		}
+	}
+	writeICS(w, cal)
+}
+
+// Seçimdeki şubeye ait ve şubesiz (ortak) saatler. Ortak dersler birden fazla bölümün
+// programında aynı saatle yer alabilir; tekrarlar atılır.
+func selectedSlots(courses []models.CourseSelection, slots []models.TimetableSlot) []models.TimetableSlot {
+	sections := make(map[string]string, len(courses))
+	for _, c := range courses {
+		sections[c.Code] = academic.NormalizeSection(c.Section)
+	}
+
+	seen := make(map[models.TimetableSlot]bool)
+	var result []models.TimetableSlot
+	for _, s := range slots {
+		section, ok := sections[s.CourseCode]
+		if !ok || (section != "" && s.Section != "" && !strings.EqualFold(section, s.Section)) {
+			continue
+		}
+		key := s
+		key.ID, key.DepartmentID, key.MatchScore = 0, 0, 0
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Şubesi verilmediği hâlde programda birden fazla şubesi olan ilk dersin kodu; yoksa boş
+func ambiguousSection(courses []models.CourseSelection, slots []models.TimetableSlot) string {
+	sections := make(map[string]map[string]bool)
+	for _, s := range slots {
+		if s.Section == "" {
+			continue
+		}
+		if sections[s.CourseCode] == nil {
+			sections[s.CourseCode] = make(map[string]bool)
+		}
+		sections[s.CourseCode][academic.NormalizeSection(s.Section)] = true
+	}
+	for _, c := range courses {
+		if c.Section == "" && len(sections[c.Code]) > 1 {
+			return c.Code
+		}
+	}
+	return ""
+}
+
+// Derslerin yapıldığı tarih aralığı; iki uç da dahildir.
+type termRange struct {
+	Start, End time.Time
+}
+
+// Akademik takvimdeki ders başlangıç/bitiş etkinliklerinden bugünü içeren ya da sıradaki
+// dönemi bulur. Sadece başlangıç günü biliniyorsa dönem defaultTermWeeks hafta sayılır.
+func currentTerm(db storage.DB, today time.Time) (termRange, bool, error) {
+	events, _, err := storage.ListCalendarEvents(db, storage.CalendarQuery{Categories: []string{models.CalendarClasses}})
+	if err != nil {
+		return termRange{}, false, err
+	}
+
+	terms := make(map[string]termRange)
+	for _, e := range events {
+		start, err1 := time.ParseInLocation("2006-01-02", e.StartDate, models.Istanbul)
+		end, err2 := time.ParseInLocation("2006-01-02", e.EndDate, models.Istanbul)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		key := e.AcademicYear + "/" + e.Term
+		t, ok := terms[key]
+		if !ok || start.Before(t.Start) {
+			t.Start = start
+		}
+		if !ok || end.After(t.End) {
+			t.End = end
+		}
+		terms[key] = t
+	}
+
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, models.Istanbul)
+	var best termRange
+	found := false
+	for _, t := range terms {
+		if t.End.Sub(t.Start) < 7*24*time.Hour {
+			t.End = t.Start.AddDate(0, 0, defaultTermWeeks*7-1)
+		}
+		if t.End.Before(day) {
+			continue
+		}
+		if !found || t.Start.Before(best.Start) {
+			best, found = t, true
+		}
+	}
+	return best, found, nil
+}
+
+// Dönem içindeki tatil günleri
+func termHolidays(db storage.DB, term termRange) ([]time.Time, error) {
+	events, _, err := storage.ListCalendarEvents(db, storage.CalendarQuery{
+		Categories: []string{models.CalendarHoliday},
+		From:       term.Start.Format("2006-01-02"),
+		To:         term.End.Format("2006-01-02"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for _, e := range events {
+		start, err1 := time.ParseInLocation("2006-01-02", e.StartDate, models.Istanbul)
+		end, err2 := time.ParseInLocation("2006-01-02", e.EndDate, models.Istanbul)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+// Ders saatini dönem boyunca haftalık tekrarlanan etkinliğe çevirir.
+func weeklyEvent(s models.TimetableSlot, term termRange, holidays []time.Time, stamp time.Time) (icsEvent, bool) {
+	day := timetable.DayIndex(s.Day)
+	start, ok1 := academic.ParseClock(s.Start)
+	end, ok2 := academic.ParseClock(s.End)
+	if day < 0 || !ok1 || !ok2 || end <= start {
+		return icsEvent{}, false
+	}
+
+	// DayIndex Pazartesi'den başlar, time.Weekday Pazar'dan.
+	weekday := time.Weekday((day + 1) % 7)
+	first := term.Start
+	for first.Weekday() != weekday {
+		first = first.AddDate(0, 0, 1)
+	}
+	if first.After(term.End) {
+		return icsEvent{}, false
+	}
+	at := func(d time.Time, minutes int) time.Time {
+		return time.Date(d.Year(), d.Month(), d.Day(), minutes/60, minutes%60, 0, 0, models.Istanbul)
+	}
+
+	e := icsEvent{
+		UID:      slotUID(s, term),
+		Summary:  strings.TrimSpace(s.CourseCode + " " + s.CourseName),
+		Start:    at(first, start),
+		End:      at(first, end),
+		Stamp:    stamp,
+		Location: s.Room,
+		RRule:    "FREQ=WEEKLY;UNTIL=" + at(term.End, 24*60-1).UTC().Format("20060102T150405Z"),
+	}
+	var details []string
+	if s.Section != "" {
+		details = append(details, s.Section)
+	}
+	if s.Instructor != "" {
+		details = append(details, s.Instructor)
+	}
+	e.Description = strings.Join(details, "\n")
+
+	for _, h := range holidays {
+		if h.Weekday() == weekday && !h.Before(first) && !h.After(term.End) {
+			e.ExDates = append(e.ExDates, at(h, start))
+		}
+	}
+	return e, true
+}
+
+// Aynı dönemde aynı ders saati için değişmeyen kimlik; saat ya da derslik değişirse
+// takvim uygulaması eski etkinliği siler.
+func slotUID(s models.TimetableSlot, term termRange) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s",
+		s.CourseCode, s.Section, s.Day, s.Start, s.End, s.Room, term.Start.Format("2006-01-02"))))
+	return hex.EncodeToString(sum[:10]) + "@iuc-companion"
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+	"companion_server/internal/timetable"
+)
+
+// Bölümün haftalık ders programı. Ders kodu boş satırlar müfredatla eşleşmemiştir.
+func (h *Handler) GetTimetable(w http.ResponseWriter, r *http.Request) {
+	dept, ok := h.departmentByGUID(w, r, r.PathValue("guid"))
+	if !ok {
+		return
+	}
+	opts, badParam := parseListOptions(r, storage.TimetableSortFields)
+	if badParam != "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, badParam)
+		return
+	}
+
+	query := r.URL.Query()
+	q := storage.TimetableQuery{
+		ListOptions:  opts,
+		DepartmentID: dept.ID,
+		Semester:     query.Get("semester"),
+		Day:          query.Get("day"),
+	}
+	if q.Day != "" {
+		i := timetable.DayIndex(q.Day)
+		if i < 0 {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "day")
+			return
+		}
+		q.Day = timetable.Days[i]
+	}
+	if code := query.Get("course_code"); code != "" {
+		q.CourseCodes = []string{code}
+	}
+
+	cursor, err := storage.QueryTimetableSlots(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	streamList(w, r, cursor, opts)
+}
+
+type timetableSourceRequest struct {
+	PageURL string `json:"page_url" doc:"Ders programı PDF bağlantısını içeren sayfa ya da doğrudan PDF adresi; otomatik bulunan kaynağın yerine geçer"`
+}
+
+// Bölümün ders programı kaynağını ayarlar. PDF bir sonraki alımda indirilir. Otomatik
+// bulunamayan ya da yanlış bulunan kaynaklar buradan düzeltilir.
+func (h *Handler) PutTimetableSource(w http.ResponseWriter, r *http.Request) {
+	dept, ok := h.departmentByGUID(w, r, r.PathValue("guid"))
+	if !ok {
+		return
+	}
+
+	var req timetableSourceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	if req.PageURL == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "page_url")
+		return
+	}
+	if u, err := url.Parse(req.PageURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "page_url")
+		return
+	}
+
+	if err := storage.SetTimetableSource(h.db(r), dept.ID, req.PageURL); err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Ders programı kaynakları ve son alım sonuçları
+func (h *Handler) GetTimetableSources(w http.ResponseWriter, r *http.Request) {
+	sources, err := storage.GetTimetableSources(h.db(r))
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if sources == nil {
+		sources = []models.TimetableSource{}
+	}
+	respondJSON(w, sources)
+}
+
+// Zamanlanmış alımı beklemeden bütün ders programlarını alır. İndirme ve ayrıştırma uzun
+// sürebildiği için arka planda çalışır; sonuçlar kaynak listesindeki alanlardan izlenir.
+func (h *Handler) PostIngestTimetables(w http.ResponseWriter, r *http.Request) {
+	if h.TriggerTimetables == nil {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	if !h.TriggerTimetables() {
+		respondError(w, r, http.StatusConflict, ErrIngestRunning)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+const (
+	// Bir seçimde en fazla bulunabilecek ders
+	maxSelectedCourses = 40
+	// 40 ders kodu ve şubesi için fazlasıyla yeterli
+	maxSelectionBodySize = 16 << 10
+)
+
+type selectionRequest struct {
+	DepartmentGUID string                   `json:"department_guid,omitempty" doc:"Verilirse sadece bu bölümün ders programı kullanılır"`
+	Courses        []models.CourseSelection `json:"courses"`
+}
+
+// Ders seçimini doğrular ve seçilen derslerin ders programı sorgusunu döner.
+// Hata yanıtı yazıldıysa false döner.
+func (h *Handler) selectionQuery(w http.ResponseWriter, r *http.Request, req selectionRequest) (storage.TimetableQuery, bool) {
+	q := storage.TimetableQuery{}
+	if len(req.Courses) == 0 {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "courses")
+		return q, false
+	}
+	if len(req.Courses) > maxSelectedCourses {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "courses")
+		return q, false
+	}
+	for _, c := range req.Courses {
+		if c.Code == "" {
+			respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "courses.code")
+			return q, false
+		}
+		q.CourseCodes = append(q.CourseCodes, c.Code)
+	}
+	if req.DepartmentGUID != "" {
+		dept, ok := h.departmentByGUID(w, r, req.DepartmentGUID)
+		if !ok {
+			return q, false
+		}
+		q.DepartmentID = dept.ID
+	}
+	return q, true
+}
+
+// Seçilen derslerin ders programındaki çakışmalarını ve çakışmayı çözen şubeleri döner.
+func (h *Handler) PostTimetableConflicts(w http.ResponseWriter, r *http.Request) {
+	var req selectionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxSelectionBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	q, ok := h.selectionQuery(w, r, req)
+	if !ok {
+		return
+	}
+
+	slots, _, err := storage.ListTimetableSlots(h.db(r), q)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	respondJSON(w, academic.FindConflicts(req.Courses, scheduleSlots(slots)))
+}
+
+// Ders programı satırlarını çakışma hesabı için dönüştürür. Ortak dersler birden fazla
+// bölümün programında aynı saatle yer alabilir; tekrarlar atılır.
+func scheduleSlots(slots []models.TimetableSlot) []academic.ScheduleSlot {
+	seen := make(map[academic.ScheduleSlot]bool)
+	var result []academic.ScheduleSlot
+	for _, s := range slots {
+		slot := academic.ScheduleSlot{Code: s.CourseCode, Day: s.Day, Start: s.Start, End: s.End, Room: s.Room, Section: s.Section}
+		if !seen[slot] {
+			seen[slot] = true
+			result = append(result, slot)
+		}
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"companion_server/internal/academic"
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func timetableDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db := testDB(t)
+	_, err := storage.ReplaceTimetableSlots(db, 10, []models.TimetableSlot{
+		{CourseCode: "MAT101", CourseName: "Matematik I", Day: "Pazartesi", Start: "09:00", End: "10:50"},
+		{CourseCode: "MAT102", CourseName: "Matematik II", Day: "Pazartesi", Start: "10:00", End: "11:50", Section: "Grup 1"},
+		{CourseCode: "MAT102", CourseName: "Matematik II", Day: "Salı", Start: "10:00", End: "11:50", Section: "Grup 2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPostTimetableConflicts(t *testing.T) {
+	router := SetupRoutes(NewHandler(timetableDB(t)), Config{})
+
+	rec := serve(t, router, "POST", "/api/v1/timetable/conflicts",
+		`{"courses":[{"code":"MAT101"},{"code":"MAT102","section":"1"}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var result academic.ConflictResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Start != "10:00" || result.Conflicts[0].End != "10:50" {
+		t.Fatalf("conflicts = %+v", result.Conflicts)
+	}
+	if alts := result.Conflicts[0].Alternatives; len(alts) != 1 || alts[0].Section != "Grup 2" {
+		t.Errorf("alternatives = %+v, want Grup 2", alts)
+	}
+}
+
+func TestTimetableSelectionBodyLimit(t *testing.T) {
+	router := SetupRoutes(NewHandler(timetableDB(t)), Config{})
+	body := `{"courses":[{"code":"MAT101","section":"` + strings.Repeat("x", maxSelectionBodySize) + `"}]}`
+
+	for _, path := range []string{"/api/v1/timetable/conflicts", "/api/v1/timetable/feeds"} {
+		if rec := serve(t, router, "POST", path, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", path, rec.Code)
+		}
+	}
+}
+
+func TestPostTimetableFeedSection(t *testing.T) {
+	router := SetupRoutes(NewHandler(timetableDB(t)), Config{})
+
+	tests := []struct {
+		body string
+		want int
+		code ErrorCode
+	}{
+		{`{"courses":[{"code":"MAT101"}]}`, http.StatusCreated, ""},
+		{`{"courses":[{"code":"MAT101"},{"code":"MAT102","section":"Grup 2"}]}`, http.StatusCreated, ""},
+		{`{"courses":[{"code":"MAT101"},{"code":"MAT102"}]}`, http.StatusBadRequest, ErrSectionRequired},
+	}
+	for _, tt := range tests {
+		rec := serve(t, router, "POST", "/api/v1/timetable/feeds", tt.body)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.body, rec.Code, tt.want)
+			continue
+		}
+		if tt.code != "" {
+			var env errorEnvelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil || env.Error.Code != tt.code {
+				t.Errorf("%s: error = %s, want %s", tt.body, rec.Body, tt.code)
+			}
+		}
+	}
+}
+
+func TestPostTimetableFeedRateLimit(t *testing.T) {
+	router := SetupRoutes(NewHandler(timetableDB(t)), Config{})
+	limit := defaultRouteLimits["POST /api/v1/timetable/feeds"]
+
+	for i := 0; i < limit.Burst; i++ {
+		if rec := serve(t, router, "POST", "/api/v1/timetable/feeds", `{"courses":[{"code":"MAT101"}]}`); rec.Code != http.StatusCreated {
+			t.Fatalf("feed %d: status = %d", i, rec.Code)
+		}
+	}
+	if rec := serve(t, router, "POST", "/api/v1/timetable/feeds", `{"courses":[{"code":"MAT101"}]}`); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("feed %d: status = %d, want 429", limit.Burst, rec.Code)
+	}
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+const maxWebhookBodySize = 4 << 10
+
+type webhookRequest struct {
+	URL    string   `json:"url" doc:"http ya da https adresi"`
+	Events []string `json:"events" doc:"course.added, course.removed, course.updated, detail.updated, announcement.published, scrape.completed ya da hepsi için *"`
+}
+
+// Webhook kaydeder. Yanıttaki imza anahtarı bir daha gösterilmez.
+func (h *Handler) PostWebhook(w http.ResponseWriter, r *http.Request) {
+	var req webhookRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxWebhookBodySize)).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidBody)
+		return
+	}
+	if req.URL == "" {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "url")
+		return
+	}
+	if u, err := url.Parse(req.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "url")
+		return
+	}
+	if len(req.Events) == 0 {
+		respondError(w, r, http.StatusBadRequest, ErrMissingParameter, "events")
+		return
+	}
+	for _, e := range req.Events {
+		if !models.ValidWebhookEvent(e) {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "events")
+			return
+		}
+	}
+
+	hook, err := storage.CreateWebhook(h.db(r), req.URL, req.Events)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+func (h *Handler) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	hooks, err := storage.ListWebhooks(h.db(r))
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	respondJSON(w, hooks)
+}
+
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	found, err := storage.DeleteWebhook(h.db(r), id)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	if !found {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Webhook'un gönderim günlüğü, yeniden eskiye (varsayılan 50 kayıt).
+func (h *Handler) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+
+	status := models.DeliveryStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", models.DeliveryPending, models.DeliverySucceeded, models.DeliveryFailed:
+	default:
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "status")
+		return
+	}
+	limit := 50
+	if l := r.URL.Query().Get("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed <= 0 || parsed > maxPageSize {
+			respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "limit")
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := storage.ListDeliveries(h.db(r), id, status, limit)
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	respondJSON(w, deliveries)
+}
+
+// Gönderimin olayını yeni bir gönderim olarak hemen tekrar kuyruğa alır.
+func (h *Handler) PostReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	delivery, err := storage.ReplayDelivery(h.db(r), id)
+	if err == sql.ErrNoRows {
+		respondError(w, r, http.StatusNotFound, ErrNotFound)
+		return
+	}
+	if err != nil {
+		respondInternalError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		respondError(w, r, http.StatusBadRequest, ErrInvalidParameter, "id")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,220 @@
+package equivalence
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/models"
+)
+
+const (
+	// Bu skorun altındaki çiftler aday olarak saklanmaz.
+	CandidateThreshold = 0.6
+	// Bundan fazla derste geçen ad kelimeleri ("giriş", "laboratuvar") eşleştirmede kullanılmaz.
+	maxTokenFrequency = 200
+
+	nameWeight     = 0.45
+	syllabusWeight = 0.35
+	ectsWeight     = 0.10
+	hoursWeight    = 0.10
+)
+
+// Ders adlarında ayırt edici olmayan kelimeler
+var stopWords = map[string]bool{
+	"ve": true, "ile": true, "için": true, "de": true, "da": true, "bir": true,
+	"ı": true, "ıı": true, "ııı": true, "ıv": true,
+}
+
+func tokenize(s string) []string {
+	lower := strings.ToLowerSpecial(unicode.TurkishCase, s)
+	words := strings.FieldsFunc(lower, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var tokens []string
+	for _, w := range words {
+		if len(w) > 1 && !stopWords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+// Romen rakamıyla yazılan seviyeler; "Fizik II" ile "Fizik 2" aynı seviyededir.
+var romanLevels = map[string]string{
+	"I": "1", "II": "2", "III": "3", "IV": "4", "V": "5", "VI": "6", "VII": "7", "VIII": "8",
+}
+
+// Ders adının sonundaki seviye ("I", "II", "2") farklıysa dersler eşdeğer değildir.
+// Seviye Arap rakamıyla döner.
+func levelSuffix(name string) string {
+	fields := strings.Fields(strings.ToUpperSpecial(unicode.TurkishCase, name))
+	if len(fields) == 0 {
+		return ""
+	}
+	last := strings.TrimSuffix(fields[len(fields)-1], ".")
+	if level, ok := romanLevels[last]; ok {
+		return level
+	}
+	if len(last) == 1 && last >= "1" && last <= "8" {
+		return last
+	}
+	return ""
+}
+
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	setA := make(map[string]bool, len(a))
+	for _, t := range a {
+		setA[t] = true
+	}
+	setB := make(map[string]bool, len(b))
+	intersection := 0
+	for _, t := range b {
+		if setB[t] {
+			continue
+		}
+		setB[t] = true
+		if setA[t] {
+			intersection++
+		}
+	}
+	union := len(setA) + len(setB) - intersection
+	return float64(intersection) / float64(union)
+}
+
+func cosine(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	freqA := make(map[string]float64)
+	for _, t := range a {
+		freqA[t]++
+	}
+	freqB := make(map[string]float64)
+	for _, t := range b {
+		freqB[t]++
+	}
+
+	var dot, normA, normB float64
+	for t, v := range freqA {
+		dot += v * freqB[t]
+		normA += v * v
+	}
+	for _, v := range freqB {
+		normB += v * v
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+type profile struct {
+	models.CourseProfile
+	nameTokens     []string
+	syllabusTokens []string
+	level          string
+}
+
+func newProfile(p models.CourseProfile) profile {
+	return profile{
+		CourseProfile:  p,
+		nameTokens:     tokenize(p.Name),
+		syllabusTokens: tokenize(p.Aim + " " + p.Content),
+		level:          levelSuffix(p.Name),
+	}
+}
+
+// İki dersin 0-1 arası benzerlik skoru. Syllabus metni olmayan derslerde ağırlık ada kaydırılır.
+func score(a, b profile) float64 {
+	if a.level != b.level {
+		return 0
+	}
+
+	name := jaccard(a.nameTokens, b.nameTokens)
+
+	ects := 0.0
+	if a.ECTS > 0 && a.ECTS == b.ECTS {
+		ects = 1
+	} else if a.ECTS > 0 && b.ECTS > 0 {
+		ects = math.Min(a.ECTS, b.ECTS) / math.Max(a.ECTS, b.ECTS)
+	}
+
+	hours := 0.0
+	if a.Theory == b.Theory && a.Practice == b.Practice && a.Lab == b.Lab {
+		hours = 1
+	} else if a.Theory+a.Practice+a.Lab == b.Theory+b.Practice+b.Lab {
+		hours = 0.5
+	}
+
+	if len(a.syllabusTokens) == 0 || len(b.syllabusTokens) == 0 {
+		return (nameWeight+syllabusWeight)*name + ectsWeight*ects + hoursWeight*hours
+	}
+	syllabus := cosine(a.syllabusTokens, b.syllabusTokens)
+	return nameWeight*name + syllabusWeight*syllabus + ectsWeight*ects + hoursWeight*hours
+}
+
+// Dersler arasında eşdeğerlik adaylarını bulur. Sadece ad kelimesi paylaşan dersler karşılaştırılır.
+func FindCandidates(courses []models.CourseProfile) []models.CourseEquivalence {
+	profiles := make([]profile, len(courses))
+	index := make(map[string][]int)
+	for i, c := range courses {
+		profiles[i] = newProfile(c)
+		seen := make(map[string]bool)
+		for _, t := range profiles[i].nameTokens {
+			if !seen[t] {
+				seen[t] = true
+				index[t] = append(index[t], i)
+			}
+		}
+	}
+
+	compared := make(map[[2]int]bool)
+	var candidates []models.CourseEquivalence
+
+	for _, members := range index {
+		if len(members) < 2 || len(members) > maxTokenFrequency {
+			continue
+		}
+		for x := 0; x < len(members); x++ {
+			for y := x + 1; y < len(members); y++ {
+				pair := [2]int{members[x], members[y]}
+				if compared[pair] {
+					continue
+				}
+				compared[pair] = true
+
+				a, b := profiles[pair[0]], profiles[pair[1]]
+				if a.Code == b.Code {
+					continue
+				}
+				s := score(a, b)
+				if s < CandidateThreshold {
+					continue
+				}
+
+				if a.Code > b.Code {
+					a, b = b, a
+				}
+				candidates = append(candidates, models.CourseEquivalence{
+					CourseCode:     a.Code,
+					CourseName:     a.Name,
+					EquivalentCode: b.Code,
+					EquivalentName: b.Name,
+					Score:          math.Round(s*1000) / 1000,
+					Status:         models.EquivalenceCandidate,
+				})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].CourseCode+candidates[i].EquivalentCode < candidates[j].CourseCode+candidates[j].EquivalentCode
+	})
+	return candidates
+}
@@ -0,0 +1,78 @@
+package equivalence
+
+import (
+	"math"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestLevelSuffix(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Matematik I", "1"},
+		{"Matematik 1", "1"},
+		{"Fizik II", "2"},
+		{"FİZİK II.", "2"},
+		{"Programlama IV", "4"},
+		{"Diferansiyel Denklemler", ""},
+		{"Matematik", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := levelSuffix(tt.name); got != tt.want {
+			t.Errorf("levelSuffix(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	base := models.CourseProfile{Name: "Fizik II", ECTS: 6, Theory: 3, Practice: 2,
+		Aim: "elektrik ve manyetizma", Content: "elektrik alan manyetik alan indüksiyon"}
+	with := func(change func(p *models.CourseProfile)) models.CourseProfile {
+		p := base
+		change(&p)
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		other models.CourseProfile
+		want  float64
+	}{
+		{"aynı ders", base, 1},
+		// Romen ve Arap rakamlı seviyeler eşittir.
+		{"Arap rakamlı seviye", with(func(p *models.CourseProfile) { p.Name = "Fizik 2" }), 1},
+		{"farklı seviye", with(func(p *models.CourseProfile) { p.Name = "Fizik I" }), 0},
+		// İzlencesi olmayan derste ağırlık ada kayar: 0.8*1 + 0.1*0.5 + 0.1*1
+		{"izlencesiz", models.CourseProfile{Name: "Fizik II", ECTS: 3, Theory: 3, Practice: 2}, 0.95},
+		// Ad yarı yarıya ortak, izlence aynı, saat toplamı aynı: 0.45*0.5 + 0.35 + 0.1 + 0.05
+		{"benzer ad", with(func(p *models.CourseProfile) { p.Name, p.Theory, p.Practice = "Genel Fizik II", 4, 1 }), 0.725},
+	}
+	for _, tt := range tests {
+		if got := score(newProfile(base), newProfile(tt.other)); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: score = %.3f, want %.3f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFindCandidates(t *testing.T) {
+	courses := []models.CourseProfile{
+		{Code: "MAT201", Name: "Lineer Cebir", ECTS: 5, Theory: 3},
+		{Code: "MAT102", Name: "Lineer Cebir", ECTS: 5, Theory: 3},
+		{Code: "FIZ101", Name: "Fizik I", ECTS: 5, Theory: 3},
+		{Code: "FIZ102", Name: "Fizik II", ECTS: 5, Theory: 3},
+		{Code: "KIM101", Name: "Genel Kimya", ECTS: 4, Theory: 2},
+	}
+
+	got := FindCandidates(courses)
+	if len(got) != 1 {
+		t.Fatalf("candidates = %+v, want one", got)
+	}
+	c := got[0]
+	if c.CourseCode != "MAT102" || c.EquivalentCode != "MAT201" || c.Score != 1 || c.Status != models.EquivalenceCandidate {
+		t.Errorf("candidate = %+v", c)
+	}
+}
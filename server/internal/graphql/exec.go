@@ -0,0 +1,581 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Alan sırasını koruyan JSON nesnesi; yanıt, sorgudaki seçim sırasıyla yazılır.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: map[string]interface{}{}}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type fieldGroup struct {
+	key   string
+	nodes []*FieldNode
+}
+
+// Alt seçimler: aynı anahtarlı alanların seçimleri birleştirilir.
+func (g *fieldGroup) selections() []Selection {
+	var sels []Selection
+	for _, n := range g.nodes {
+		sels = append(sels, n.Selections...)
+	}
+	return sels
+}
+
+type executor struct {
+	schema *Schema
+	doc    *Document
+	vars   map[string]interface{}
+	// Doğrulama sırasında dönüştürülen argümanlar
+	args   map[*FieldNode]map[string]interface{}
+	errors []*Error
+}
+
+// Sorguyu doğrular ve çalıştırır. Ayrıştırma, doğrulama ya da karmaşıklık hatasında
+// ok false döner ve yanıtta sadece hatalar bulunur.
+func (s *Schema) Execute(ctx context.Context, req Request) (resp *Response, ok bool) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}, false
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}, false
+	}
+	if err := checkFragmentCycles(doc); err != nil {
+		return &Response{Errors: []*Error{asError(err)}}, false
+	}
+
+	e := &executor{schema: s, doc: doc, args: map[*FieldNode]map[string]interface{}{}}
+	if e.vars, err = coerceVariables(op.Variables, req.Variables); err != nil {
+		return &Response{Errors: []*Error{asError(err)}}, false
+	}
+
+	cost, err := e.validate(s.Query, op.Selections, 1)
+	if err != nil {
+		return &Response{Errors: []*Error{asError(err)}}, false
+	}
+	if s.MaxComplexity > 0 && cost > s.MaxComplexity {
+		return &Response{Errors: []*Error{{
+			Message: fmt.Sprintf("sorgu çok karmaşık: maliyet %d, sınır %d", cost, s.MaxComplexity),
+		}}}, false
+	}
+
+	data := e.execute(ctx, s.Query, []interface{}{struct{}{}}, op.Selections, nil)
+	return &Response{Data: data[0], Errors: e.errors}, true
+}
+
+func asError(err error) *Error {
+	var gqlErr *Error
+	if errors.As(err, &gqlErr) {
+		return gqlErr
+	}
+	return &Error{Message: err.Error()}
+}
+
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	var op *Operation
+	switch {
+	case name != "":
+		for _, o := range doc.Operations {
+			if o.Name == name {
+				op = o
+			}
+		}
+		if op == nil {
+			return nil, Errorf("%s adlı işlem bulunamadı", name)
+		}
+	case len(doc.Operations) == 1:
+		op = doc.Operations[0]
+	default:
+		return nil, Errorf("birden fazla işlem var, operationName belirtilmeli")
+	}
+	if op.Type != "query" {
+		return nil, &Error{Message: fmt.Sprintf("%s desteklenmiyor, sadece sorgular çalıştırılabilir", op.Type), Locations: []Location{op.Location}}
+	}
+	return op, nil
+}
+
+// İç içe seçimler dahil kendine ulaşan fragmanları reddeder; derinlik sınırı
+// kapalıyken doğrulama bunlarda sonsuza kadar inerdi.
+func checkFragmentCycles(doc *Document) error {
+	done := map[string]bool{}
+	active := map[string]bool{}
+	var visit func(sels []Selection) error
+	visit = func(sels []Selection) error {
+		for _, sel := range sels {
+			switch {
+			case sel.Field != nil:
+				if err := visit(sel.Field.Selections); err != nil {
+					return err
+				}
+			case sel.Inline != nil:
+				if err := visit(sel.Inline.Selections); err != nil {
+					return err
+				}
+			case sel.Spread != "":
+				frag, ok := doc.Fragments[sel.Spread]
+				if !ok || done[frag.Name] {
+					continue
+				}
+				if active[frag.Name] {
+					return &Error{Message: fmt.Sprintf("%s fragmanı kendini içeriyor", frag.Name), Locations: []Location{sel.Location}}
+				}
+				active[frag.Name] = true
+				if err := visit(frag.Selections); err != nil {
+					return err
+				}
+				delete(active, frag.Name)
+				done[frag.Name] = true
+			}
+		}
+		return nil
+	}
+	for _, name := range sortedKeys(doc.Fragments) {
+		if err := visit([]Selection{{Spread: name}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func coerceVariables(defs []VariableDef, input map[string]interface{}) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	for _, def := range defs {
+		if def.Type.Elem != nil {
+			return nil, Errorf("$%s: liste tipli değişkenler desteklenmiyor", def.Name)
+		}
+		value, given := input[def.Name]
+		if !given && def.HasDef {
+			value, given = def.Default, true
+		}
+		if !given {
+			if def.Type.NonNull {
+				return nil, Errorf("$%s değişkeni zorunlu", def.Name)
+			}
+			continue
+		}
+		coerced, err := coerceValue(value, def.Type.String(), nil)
+		if err != nil {
+			return nil, Errorf("$%s: %v", def.Name, err)
+		}
+		vars[def.Name] = coerced
+	}
+	return vars, nil
+}
+
+// Girdi değerini argüman tipine çevirir. JSON'dan gelen sayılar float64 olduğundan
+// tam sayı değerli float'lar Int kabul edilir.
+func coerceValue(value interface{}, typ string, vars map[string]interface{}) (interface{}, error) {
+	if v, isVar := value.(Variable); isVar {
+		if vars == nil {
+			return nil, fmt.Errorf("varsayılan değerde değişken kullanılamaz")
+		}
+		value = vars[string(v)]
+	}
+
+	base := strings.TrimSuffix(typ, "!")
+	if value == nil {
+		if base != typ {
+			return nil, fmt.Errorf("%s tipinde değer zorunlu", typ)
+		}
+		return nil, nil
+	}
+
+	switch base {
+	case "Int":
+		switch n := value.(type) {
+		case int:
+			return n, nil
+		case float64:
+			if n == math.Trunc(n) && math.Abs(n) <= math.MaxInt32 {
+				return int(n), nil
+			}
+		}
+	case "Float":
+		switch n := value.(type) {
+		case int:
+			return float64(n), nil
+		case float64:
+			return n, nil
+		}
+	case "String", "ID":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	default:
+		return nil, fmt.Errorf("bilinmeyen tip %s", typ)
+	}
+	return nil, fmt.Errorf("%s tipinde değer bekleniyor", typ)
+}
+
+// Seçim kümesindeki alanları yanıt anahtarına göre gruplar; fragmanları açar ve
+// @skip/@include yönergelerini uygular.
+func (e *executor) collect(obj *Object, sels []Selection, visited map[string]bool) ([]*fieldGroup, error) {
+	var groups []*fieldGroup
+	index := map[string]*fieldGroup{}
+
+	var walk func(sels []Selection) error
+	walk = func(sels []Selection) error {
+		for _, sel := range sels {
+			include, err := e.included(sel)
+			if err != nil {
+				return err
+			}
+			if !include {
+				continue
+			}
+
+			switch {
+			case sel.Field != nil:
+				key := sel.Field.Key()
+				g, ok := index[key]
+				if !ok {
+					g = &fieldGroup{key: key}
+					index[key] = g
+					groups = append(groups, g)
+				}
+				g.nodes = append(g.nodes, sel.Field)
+
+			case sel.Spread != "":
+				frag, ok := e.doc.Fragments[sel.Spread]
+				if !ok {
+					return &Error{Message: fmt.Sprintf("%s fragmanı tanımlı değil", sel.Spread), Locations: []Location{sel.Location}}
+				}
+				if visited[frag.Name] {
+					return &Error{Message: fmt.Sprintf("%s fragmanı kendini içeriyor", frag.Name), Locations: []Location{sel.Location}}
+				}
+				if frag.On != obj.Name {
+					return &Error{Message: fmt.Sprintf("%s fragmanı %s tipinde kullanılamaz", frag.Name, obj.Name), Locations: []Location{sel.Location}}
+				}
+				visited[frag.Name] = true
+				err := walk(frag.Selections)
+				delete(visited, frag.Name)
+				if err != nil {
+					return err
+				}
+
+			case sel.Inline != nil:
+				if sel.Inline.On != "" && sel.Inline.On != obj.Name {
+					return &Error{Message: fmt.Sprintf("%s koşullu fragman %s tipinde kullanılamaz", sel.Inline.On, obj.Name), Locations: []Location{sel.Location}}
+				}
+				if err := walk(sel.Inline.Selections); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return groups, walk(sels)
+}
+
+func (e *executor) included(sel Selection) (bool, error) {
+	for _, d := range sel.Directives {
+		if d.Name != "skip" && d.Name != "include" {
+			return false, &Error{Message: fmt.Sprintf("bilinmeyen yönerge @%s", d.Name), Locations: []Location{sel.Location}}
+		}
+		if len(d.Args) != 1 || d.Args[0].Name != "if" {
+			return false, &Error{Message: fmt.Sprintf("@%s yönergesi if argümanı ister", d.Name), Locations: []Location{sel.Location}}
+		}
+		v, err := coerceValue(d.Args[0].Value, "Boolean!", e.vars)
+		if err != nil {
+			return false, &Error{Message: fmt.Sprintf("@%s: %v", d.Name, err), Locations: []Location{sel.Location}}
+		}
+		if v.(bool) == (d.Name == "skip") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Seçimleri şemaya göre doğrular, argümanları dönüştürür ve sorgunun maliyetini hesaplar.
+func (e *executor) validate(obj *Object, sels []Selection, depth int) (int, error) {
+	if e.schema.MaxDepth > 0 && depth > e.schema.MaxDepth {
+		return 0, Errorf("sorgu çok derin, en fazla %d seviye", e.schema.MaxDepth)
+	}
+
+	groups, err := e.collect(obj, sels, map[string]bool{})
+	if err != nil {
+		return 0, err
+	}
+
+	cost := 0
+	for _, g := range groups {
+		node := g.nodes[0]
+		for _, other := range g.nodes[1:] {
+			if other.Name != node.Name || !reflect.DeepEqual(other.Args, node.Args) {
+				return 0, &Error{Message: fmt.Sprintf("%s anahtarı farklı alanlar için kullanılmış", g.key), Locations: []Location{other.Location}}
+			}
+		}
+		if node.Name == "__typename" {
+			continue
+		}
+
+		def, ok := obj.Fields[node.Name]
+		if !ok {
+			return 0, &Error{Message: fmt.Sprintf("%s tipinde %s alanı yok", obj.Name, node.Name), Locations: []Location{node.Location}}
+		}
+
+		args, err := e.coerceArgs(def, node)
+		if err != nil {
+			return 0, err
+		}
+		e.args[node] = args
+
+		sub := g.selections()
+		if def.Object == nil {
+			if len(sub) > 0 {
+				return 0, &Error{Message: fmt.Sprintf("%s skaler bir alan, alt seçim yapılamaz", node.Name), Locations: []Location{node.Location}}
+			}
+			cost++
+			continue
+		}
+		if len(sub) == 0 {
+			return 0, &Error{Message: fmt.Sprintf("%s alanı için alt seçim gerekli", node.Name), Locations: []Location{node.Location}}
+		}
+
+		childCost, err := e.validate(def.Object, sub, depth+1)
+		if err != nil {
+			return 0, err
+		}
+		multiplier := 1
+		if def.List {
+			multiplier = def.Size
+			for _, name := range []string{"limit", "first"} {
+				if limit, ok := args[name].(int); ok && limit > 0 {
+					multiplier = limit
+				}
+			}
+			multiplier = max(multiplier, 1)
+		}
+		cost += 1 + childCost*multiplier
+	}
+	return cost, nil
+}
+
+func (e *executor) coerceArgs(def *Field, node *FieldNode) (map[string]interface{}, error) {
+	args := map[string]interface{}{}
+	given := map[string]bool{}
+	for _, a := range node.Args {
+		spec, ok := def.Args[a.Name]
+		if !ok {
+			return nil, &Error{Message: fmt.Sprintf("%s alanında %s argümanı yok", node.Name, a.Name), Locations: []Location{node.Location}}
+		}
+		if v, isVar := a.Value.(Variable); isVar && !e.declared(string(v)) {
+			return nil, &Error{Message: fmt.Sprintf("$%s değişkeni tanımlı değil", v), Locations: []Location{node.Location}}
+		}
+		v, err := coerceValue(a.Value, spec.Type, e.vars)
+		if err != nil {
+			return nil, &Error{Message: fmt.Sprintf("%s.%s: %v", node.Name, a.Name, err), Locations: []Location{node.Location}}
+		}
+		given[a.Name] = true
+		if v != nil {
+			args[a.Name] = v
+		}
+	}
+	for name, spec := range def.Args {
+		if given[name] {
+			continue
+		}
+		if spec.Default != nil {
+			args[name] = spec.Default
+		} else if strings.HasSuffix(spec.Type, "!") {
+			return nil, &Error{Message: fmt.Sprintf("%s alanı için %s argümanı zorunlu", node.Name, name), Locations: []Location{node.Location}}
+		}
+	}
+	return args, nil
+}
+
+func (e *executor) declared(name string) bool {
+	for _, op := range e.doc.Operations {
+		for _, def := range op.Variables {
+			if def.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Seçimleri aynı seviyedeki tüm üst nesneler için birlikte çözer; her alanın çözücüsü
+// seviye başına bir kez çağrılır.
+func (e *executor) execute(ctx context.Context, obj *Object, parents []interface{}, sels []Selection, path []interface{}) []*orderedMap {
+	results := make([]*orderedMap, len(parents))
+	for i := range results {
+		results[i] = newOrderedMap()
+	}
+	// Doğrulamada hata vermeyen seçimler burada da hata vermez.
+	groups, _ := e.collect(obj, sels, map[string]bool{})
+
+	for _, g := range groups {
+		node := g.nodes[0]
+		fieldPath := append(append([]interface{}{}, path...), g.key)
+
+		if node.Name == "__typename" {
+			for _, r := range results {
+				r.set(g.key, obj.Name)
+			}
+			continue
+		}
+
+		def := obj.Fields[node.Name]
+		values, err := e.resolve(ctx, def, node, parents)
+		if err != nil {
+			// Çözücünün kendi hatası dışındakiler (veritabanı vb.) iç hata sayılır.
+			resolved := &Error{Message: "sunucu hatası", Err: err}
+			var gqlErr *Error
+			if errors.As(err, &gqlErr) {
+				resolved.Message, resolved.Err = gqlErr.Message, gqlErr.Err
+			}
+			resolved.Path = fieldPath
+			resolved.Locations = []Location{node.Location}
+			e.errors = append(e.errors, resolved)
+			for _, r := range results {
+				r.set(g.key, nil)
+			}
+			continue
+		}
+
+		if def.Object == nil {
+			for i, r := range results {
+				r.set(g.key, values[i])
+			}
+			continue
+		}
+
+		// Alt nesneler tüm üst nesneler için tek listede toplanır ve birlikte çözülür.
+		var children []interface{}
+		spans := make([][2]int, len(values))
+		nulls := make([]bool, len(values))
+		for i, v := range values {
+			start := len(children)
+			if isNil(v) {
+				nulls[i] = true
+			} else if def.List {
+				rv := reflect.ValueOf(v)
+				for j := 0; j < rv.Len(); j++ {
+					children = append(children, rv.Index(j).Interface())
+				}
+			} else {
+				children = append(children, v)
+			}
+			spans[i] = [2]int{start, len(children)}
+		}
+
+		childResults := e.execute(ctx, def.Object, children, g.selections(), fieldPath)
+		for i, r := range results {
+			switch {
+			case nulls[i]:
+				r.set(g.key, nil)
+			case def.List:
+				items := make([]interface{}, 0, spans[i][1]-spans[i][0])
+				for _, c := range childResults[spans[i][0]:spans[i][1]] {
+					items = append(items, c)
+				}
+				r.set(g.key, items)
+			default:
+				r.set(g.key, childResults[spans[i][0]])
+			}
+		}
+	}
+	return results
+}
+
+func (e *executor) resolve(ctx context.Context, def *Field, node *FieldNode, parents []interface{}) ([]interface{}, error) {
+	if len(parents) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if def.Resolve == nil {
+		values := make([]interface{}, len(parents))
+		for i, p := range parents {
+			v, err := structField(p, node.Name)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	}
+
+	values, err := def.Resolve(ctx, parents, e.args[node])
+	if err != nil {
+		return nil, err
+	}
+	if len(values) != len(parents) {
+		return nil, fmt.Errorf("graphql: %s çözücüsü %d nesne için %d değer döndü", node.Name, len(parents), len(values))
+	}
+	return values, nil
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return rv.IsNil()
+	}
+	return false
+}
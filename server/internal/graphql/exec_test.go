@@ -0,0 +1,179 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type testCourse struct {
+	Code  string  `json:"code"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score"`
+}
+
+func testSchema() *Schema {
+	course := &Object{Name: "Course", Fields: ScalarFields(testCourse{})}
+	course.Fields["related"] = &Field{
+		Type:   "[Course!]!",
+		Object: course,
+		List:   true,
+		Size:   10,
+		Args:   map[string]Arg{"first": {Type: "Int"}},
+		Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+			results := make([]interface{}, len(parents))
+			for i := range parents {
+				results[i] = []interface{}{testCourse{Code: "REL"}}
+			}
+			return results, nil
+		},
+	}
+
+	query := &Object{Name: "Query", Fields: map[string]*Field{
+		"course": {
+			Type:   "Course",
+			Object: course,
+			Args: map[string]Arg{
+				"code":     {Type: "String!"},
+				"minScore": {Type: "Float", Default: 0.0},
+			},
+			Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+				c := testCourse{Code: args["code"].(string), Name: "Analiz", Score: args["minScore"].(float64)}
+				return []interface{}{c}, nil
+			},
+		},
+		"courses": {
+			Type:   "[Course!]!",
+			Object: course,
+			List:   true,
+			Size:   100,
+			Args:   map[string]Arg{"limit": {Type: "Int"}},
+			Resolve: func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error) {
+				return []interface{}{[]interface{}{testCourse{Code: "MAT101"}, testCourse{Code: "MAT102"}}}, nil
+			},
+		},
+	}}
+	return &Schema{Query: query, MaxDepth: 4, MaxComplexity: 1000}
+}
+
+func execute(t *testing.T, s *Schema, query string, vars map[string]interface{}) (*Response, bool) {
+	t.Helper()
+	return s.Execute(context.Background(), Request{Query: query, Variables: vars})
+}
+
+func TestExecute(t *testing.T) {
+	resp, ok := execute(t, testSchema(), `
+		query($code: String!) {
+			c: course(code: $code) { ...F name @skip(if: true) }
+			courses(limit: 2) { code __typename }
+		}
+		fragment F on Course { code score }`, map[string]interface{}{"code": "FIZ101"})
+	if !ok || len(resp.Errors) > 0 {
+		t.Fatalf("errors = %v", resp.Errors)
+	}
+	got, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"c":{"code":"FIZ101","score":0},"courses":[{"code":"MAT101","__typename":"Course"},{"code":"MAT102","__typename":"Course"}]}`
+	if string(got) != want {
+		t.Errorf("data = %s\nwant   %s", got, want)
+	}
+}
+
+func TestExecuteFragmentCycle(t *testing.T) {
+	tests := []string{
+		`{ course(code: "A") { ...F } } fragment F on Course { ...F }`,
+		`{ course(code: "A") { ...F } } fragment F on Course { code ...G } fragment G on Course { ...F }`,
+		`{ course(code: "A") { ...F } } fragment F on Course { related { ...F } }`,
+	}
+	for _, query := range tests {
+		resp, ok := execute(t, testSchema(), query, nil)
+		if ok || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "kendini içeriyor") {
+			t.Errorf("%s: ok = %v, errors = %v", query, ok, resp.Errors)
+		}
+	}
+}
+
+func TestExecuteVariables(t *testing.T) {
+	tests := []struct {
+		query   string
+		vars    map[string]interface{}
+		wantErr string
+		want    string
+	}{
+		{`query($c: String!) { course(code: $c) { code } }`, nil, "$c değişkeni zorunlu", ""},
+		{`query($c: String!) { course(code: $c) { code } }`, map[string]interface{}{"c": 5.0}, "String! tipinde değer bekleniyor", ""},
+		{`query($c: String = "VAR") { course(code: $c) { code } }`, nil, "", `{"course":{"code":"VAR"}}`},
+		{`query($s: Float) { course(code: "A", minScore: $s) { score } }`, map[string]interface{}{"s": 3}, "", `{"course":{"score":3}}`},
+		{`query($n: Int) { courses(limit: $n) { code } }`, map[string]interface{}{"n": 2.5}, "Int tipinde değer bekleniyor", ""},
+		{`query($n: Int) { courses(limit: $n) { code } }`, map[string]interface{}{"n": 2.0}, "", `{"courses":[{"code":"MAT101"},{"code":"MAT102"}]}`},
+		{`query($l: [Int]) { courses { code } }`, nil, "liste tipli değişkenler desteklenmiyor", ""},
+		{`{ course(code: $x) { code } }`, nil, "$x değişkeni tanımlı değil", ""},
+		{`{ course { code } }`, nil, "code argümanı zorunlu", ""},
+		{`{ course(code: "A", x: 1) { code } }`, nil, "x argümanı yok", ""},
+	}
+	for _, tt := range tests {
+		resp, ok := execute(t, testSchema(), tt.query, tt.vars)
+		if tt.wantErr != "" {
+			if ok || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+				t.Errorf("%s: ok = %v, errors = %v, want %q", tt.query, ok, resp.Errors, tt.wantErr)
+			}
+			continue
+		}
+		if !ok || len(resp.Errors) > 0 {
+			t.Errorf("%s: errors = %v", tt.query, resp.Errors)
+			continue
+		}
+		if got, _ := json.Marshal(resp.Data); string(got) != tt.want {
+			t.Errorf("%s: data = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteLimits(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{`{ course(code: "A") { related { related { code } } } }`, ""},
+		{`{ course(code: "A") { related { related { related { code } } } } }`, "sorgu çok derin, en fazla 4 seviye"},
+		// 1 + 100 * (1 + 10 * 1) = 1101
+		{`{ courses { related { code } } }`, "maliyet 1101, sınır 1000"},
+		// first ve limit argümanları liste boyutunun yerine geçer: 1 + 5 * (1 + 2 * 1) = 16
+		{`{ courses(limit: 5) { related(first: 2) { code } } }`, ""},
+		{`{ courses(limit: 50) { related(first: 18) { code } } }`, ""},
+		{`{ courses(limit: 50) { related(first: 20) { code } } }`, "maliyet 1051, sınır 1000"},
+	}
+	for _, tt := range tests {
+		resp, ok := execute(t, testSchema(), tt.query, nil)
+		if tt.wantErr == "" {
+			if !ok || len(resp.Errors) > 0 {
+				t.Errorf("%s: errors = %v", tt.query, resp.Errors)
+			}
+			continue
+		}
+		if ok || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, tt.wantErr) {
+			t.Errorf("%s: ok = %v, errors = %v, want %q", tt.query, ok, resp.Errors, tt.wantErr)
+		}
+	}
+}
+
+func TestExecuteOperations(t *testing.T) {
+	const doc = `query A { courses { code } } query B { course(code: "B") { code } }`
+	s := testSchema()
+
+	resp, ok := s.Execute(context.Background(), Request{Query: doc})
+	if ok || !strings.Contains(resp.Errors[0].Message, "operationName belirtilmeli") {
+		t.Errorf("errors = %v", resp.Errors)
+	}
+	resp, ok = s.Execute(context.Background(), Request{Query: doc, OperationName: "B"})
+	if got, _ := json.Marshal(resp.Data); !ok || string(got) != `{"course":{"code":"B"}}` {
+		t.Errorf("B: data = %s, errors = %v", got, resp.Errors)
+	}
+	resp, ok = s.Execute(context.Background(), Request{Query: `mutation { courses { code } }`})
+	if ok || !strings.Contains(resp.Errors[0].Message, "desteklenmiyor") {
+		t.Errorf("mutation: errors = %v", resp.Errors)
+	}
+}
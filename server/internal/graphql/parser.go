@@ -0,0 +1,691 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sorgu belgesinin ayrıştırılmış hali. Sadece sorgu (query) işlemleri desteklenir.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+type Operation struct {
+	Type       string
+	Name       string
+	Variables  []VariableDef
+	Selections []Selection
+	Location   Location
+}
+
+type VariableDef struct {
+	Name    string
+	Type    TypeRef
+	Default interface{}
+	HasDef  bool
+}
+
+// Değişken tipi: Int, [String!]! gibi
+type TypeRef struct {
+	Name    string
+	Elem    *TypeRef
+	NonNull bool
+}
+
+func (t TypeRef) String() string {
+	s := t.Name
+	if t.Elem != nil {
+		s = "[" + t.Elem.String() + "]"
+	}
+	if t.NonNull {
+		s += "!"
+	}
+	return s
+}
+
+type Fragment struct {
+	Name       string
+	On         string
+	Selections []Selection
+}
+
+// Seçim kümesinin bir elemanı: alan, fragman yayılımı ya da satır içi fragman.
+type Selection struct {
+	Field      *FieldNode
+	Spread     string
+	Inline     *Fragment
+	Directives []Directive
+	Location   Location
+}
+
+type FieldNode struct {
+	Alias      string
+	Name       string
+	Args       []Argument
+	Selections []Selection
+	Location   Location
+}
+
+// Yanıttaki anahtar: takma ad varsa o, yoksa alan adı.
+func (f *FieldNode) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+type Argument struct {
+	Name  string
+	Value interface{}
+}
+
+type Directive struct {
+	Name string
+	Args []Argument
+}
+
+// Değer düğümleri: int, float64, string, bool, nil, []interface{}, map[string]interface{},
+// değişken referansı için Variable ve enum değerleri için Enum.
+type Variable string
+type Enum string
+
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	loc   Location
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	col  int
+}
+
+func (l *lexer) errorf(format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{{l.line, l.col}}}
+}
+
+func (l *lexer) advance(n int) {
+	for i := 0; i < n && l.pos < len(l.src); i++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+			l.col = 1
+		} else {
+			l.col++
+		}
+		l.pos++
+	}
+}
+
+func (l *lexer) next() (token, error) {
+	// Boşluk, virgül ve yorumlar yok sayılır.
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			l.advance(1)
+			continue
+		}
+		if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.advance(1)
+			}
+			continue
+		}
+		if strings.HasPrefix(l.src[l.pos:], "\uFEFF") {
+			l.pos += len("\uFEFF")
+			continue
+		}
+		break
+	}
+
+	loc := Location{l.line, l.col}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, loc: loc}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.advance(3)
+		return token{kind: tokPunct, value: "...", loc: loc}, nil
+	case strings.ContainsRune("!$()[]{}:=@|&", rune(c)):
+		l.advance(1)
+		return token{kind: tokPunct, value: string(c), loc: loc}, nil
+	case c == '_' || isLetter(c):
+		start := l.pos
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.advance(1)
+		}
+		return token{kind: tokName, value: l.src[start:l.pos], loc: loc}, nil
+	case c == '-' || isDigit(c):
+		return l.number(loc)
+	case c == '"':
+		return l.string(loc)
+	}
+	return token{}, l.errorf("beklenmeyen karakter %q", c)
+}
+
+func (l *lexer) number(loc Location) (token, error) {
+	start := l.pos
+	kind := tokInt
+	if l.src[l.pos] == '-' {
+		l.advance(1)
+	}
+	digits := func() int {
+		n := 0
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.advance(1)
+			n++
+		}
+		return n
+	}
+	if digits() == 0 {
+		return token{}, l.errorf("geçersiz sayı")
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokFloat
+		l.advance(1)
+		if digits() == 0 {
+			return token{}, l.errorf("geçersiz sayı")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokFloat
+		l.advance(1)
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.advance(1)
+		}
+		if digits() == 0 {
+			return token{}, l.errorf("geçersiz sayı")
+		}
+	}
+	return token{kind: kind, value: l.src[start:l.pos], loc: loc}, nil
+}
+
+func (l *lexer) string(loc Location) (token, error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		l.advance(3)
+		end := strings.Index(l.src[l.pos:], `"""`)
+		if end < 0 {
+			return token{}, l.errorf("kapatılmamış metin")
+		}
+		value := l.src[l.pos : l.pos+end]
+		l.advance(end + 3)
+		return token{kind: tokString, value: strings.TrimSpace(value), loc: loc}, nil
+	}
+
+	l.advance(1)
+	var sb strings.Builder
+	for {
+		if l.pos >= len(l.src) || l.src[l.pos] == '\n' {
+			return token{}, l.errorf("kapatılmamış metin")
+		}
+		c := l.src[l.pos]
+		if c == '"' {
+			l.advance(1)
+			return token{kind: tokString, value: sb.String(), loc: loc}, nil
+		}
+		if c != '\\' {
+			r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+			sb.WriteRune(r)
+			l.pos += size
+			l.col++
+			continue
+		}
+
+		if l.pos+1 >= len(l.src) {
+			return token{}, l.errorf("kapatılmamış metin")
+		}
+		esc := l.src[l.pos+1]
+		switch esc {
+		case '"', '\\', '/':
+			sb.WriteByte(esc)
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'u':
+			if l.pos+6 > len(l.src) {
+				return token{}, l.errorf("geçersiz kaçış dizisi")
+			}
+			code, err := strconv.ParseUint(l.src[l.pos+2:l.pos+6], 16, 32)
+			if err != nil {
+				return token{}, l.errorf("geçersiz kaçış dizisi")
+			}
+			sb.WriteRune(rune(code))
+			l.advance(6)
+			continue
+		default:
+			return token{}, l.errorf("geçersiz kaçış dizisi \\%c", esc)
+		}
+		l.advance(2)
+	}
+}
+
+func isLetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// Sorgu metnini ayrıştırır. Hatalar konum bilgisiyle *Error olarak döner.
+func Parse(src string) (*Document, error) {
+	p := &parser{lex: &lexer{src: src, line: 1, col: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.peek("{"):
+			loc := p.tok.loc
+			sels, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: sels, Location: loc})
+		case p.tok.kind == tokName && p.tok.value == "fragment":
+			frag, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := doc.Fragments[frag.Name]; dup {
+				return nil, p.errorf("%s fragmanı birden fazla tanımlanmış", frag.Name)
+			}
+			doc.Fragments[frag.Name] = frag
+		case p.tok.kind == tokName:
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		default:
+			return nil, p.unexpected()
+		}
+	}
+	if len(doc.Operations) == 0 {
+		return nil, &Error{Message: "belgede işlem yok"}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{p.tok.loc}}
+}
+
+func (p *parser) unexpected() error {
+	if p.tok.kind == tokEOF {
+		return p.errorf("beklenmeyen belge sonu")
+	}
+	return p.errorf("beklenmeyen %q", p.tok.value)
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.value == punct
+}
+
+func (p *parser) skip(punct string) (bool, error) {
+	if !p.peek(punct) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(punct) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.unexpected()
+	}
+	name := p.tok.value
+	return name, p.advance()
+}
+
+func (p *parser) operation() (*Operation, error) {
+	op := &Operation{Location: p.tok.loc}
+	var err error
+	if op.Type, err = p.name(); err != nil {
+		return nil, err
+	}
+	if op.Type != "query" && op.Type != "mutation" && op.Type != "subscription" {
+		return nil, p.errorf("bilinmeyen işlem tipi %s", op.Type)
+	}
+	if p.tok.kind == tokName {
+		op.Name, _ = p.name()
+	}
+
+	if ok, err := p.skip("("); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(")") {
+			def, err := p.variableDef()
+			if err != nil {
+				return nil, err
+			}
+			op.Variables = append(op.Variables, def)
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+
+	op.Selections, err = p.selectionSet()
+	return op, err
+}
+
+func (p *parser) variableDef() (VariableDef, error) {
+	var def VariableDef
+	if err := p.expect("$"); err != nil {
+		return def, err
+	}
+	var err error
+	if def.Name, err = p.name(); err != nil {
+		return def, err
+	}
+	if err := p.expect(":"); err != nil {
+		return def, err
+	}
+	if def.Type, err = p.typeRef(); err != nil {
+		return def, err
+	}
+	if ok, err := p.skip("="); err != nil {
+		return def, err
+	} else if ok {
+		if def.Default, err = p.value(true); err != nil {
+			return def, err
+		}
+		def.HasDef = true
+	}
+	return def, nil
+}
+
+func (p *parser) typeRef() (TypeRef, error) {
+	var t TypeRef
+	if ok, err := p.skip("["); err != nil {
+		return t, err
+	} else if ok {
+		elem, err := p.typeRef()
+		if err != nil {
+			return t, err
+		}
+		t.Elem = &elem
+		if err := p.expect("]"); err != nil {
+			return t, err
+		}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return t, err
+		}
+		t.Name = name
+	}
+	ok, err := p.skip("!")
+	t.NonNull = ok
+	return t, err
+}
+
+func (p *parser) fragment() (*Fragment, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	frag := &Fragment{}
+	var err error
+	if frag.Name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if frag.Name == "on" {
+		return nil, p.errorf("fragman adı on olamaz")
+	}
+	if on, err := p.name(); err != nil || on != "on" {
+		return nil, p.errorf("fragman tip koşulu (on) bekleniyor")
+	}
+	if frag.On, err = p.name(); err != nil {
+		return nil, err
+	}
+	if _, err := p.directives(); err != nil {
+		return nil, err
+	}
+	frag.Selections, err = p.selectionSet()
+	return frag, err
+}
+
+func (p *parser) selectionSet() ([]Selection, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	var sels []Selection
+	for !p.peek("}") {
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, p.errorf("boş seçim kümesi")
+	}
+	return sels, p.advance()
+}
+
+func (p *parser) selection() (Selection, error) {
+	sel := Selection{Location: p.tok.loc}
+	var err error
+
+	if ok, err := p.skip("..."); err != nil {
+		return sel, err
+	} else if ok {
+		if p.tok.kind == tokName && p.tok.value != "on" {
+			if sel.Spread, err = p.name(); err != nil {
+				return sel, err
+			}
+			sel.Directives, err = p.directives()
+			return sel, err
+		}
+
+		frag := &Fragment{}
+		if p.tok.kind == tokName {
+			if err := p.advance(); err != nil {
+				return sel, err
+			}
+			if frag.On, err = p.name(); err != nil {
+				return sel, err
+			}
+		}
+		if sel.Directives, err = p.directives(); err != nil {
+			return sel, err
+		}
+		if frag.Selections, err = p.selectionSet(); err != nil {
+			return sel, err
+		}
+		sel.Inline = frag
+		return sel, nil
+	}
+
+	field := &FieldNode{Location: p.tok.loc}
+	if field.Name, err = p.name(); err != nil {
+		return sel, err
+	}
+	if ok, err := p.skip(":"); err != nil {
+		return sel, err
+	} else if ok {
+		field.Alias = field.Name
+		if field.Name, err = p.name(); err != nil {
+			return sel, err
+		}
+	}
+	if field.Args, err = p.arguments(); err != nil {
+		return sel, err
+	}
+	if sel.Directives, err = p.directives(); err != nil {
+		return sel, err
+	}
+	if p.peek("{") {
+		if field.Selections, err = p.selectionSet(); err != nil {
+			return sel, err
+		}
+	}
+	sel.Field = field
+	return sel, nil
+}
+
+func (p *parser) arguments() ([]Argument, error) {
+	if ok, err := p.skip("("); err != nil || !ok {
+		return nil, err
+	}
+	var args []Argument
+	for !p.peek(")") {
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		value, err := p.value(false)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, Argument{Name: name, Value: value})
+	}
+	return args, p.advance()
+}
+
+func (p *parser) directives() ([]Directive, error) {
+	var dirs []Directive
+	for p.peek("@") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		if err != nil {
+			return nil, err
+		}
+		args, err := p.arguments()
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, Directive{Name: name, Args: args})
+	}
+	return dirs, nil
+}
+
+// Değer ayrıştırır. const true ise (varsayılan değerler) değişken kullanılamaz.
+func (p *parser) value(constant bool) (interface{}, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokInt:
+		n, err := strconv.Atoi(tok.value)
+		if err != nil {
+			return nil, p.errorf("sayı aralık dışında: %s", tok.value)
+		}
+		return n, p.advance()
+	case tokFloat:
+		f, err := strconv.ParseFloat(tok.value, 64)
+		if err != nil {
+			return nil, p.errorf("geçersiz sayı: %s", tok.value)
+		}
+		return f, p.advance()
+	case tokString:
+		return tok.value, p.advance()
+	case tokName:
+		var v interface{}
+		switch tok.value {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = Enum(tok.value)
+		}
+		return v, p.advance()
+	}
+
+	switch {
+	case p.peek("$") && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.name()
+		return Variable(name), err
+	case p.peek("["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		list := []interface{}{}
+		for !p.peek("]") {
+			v, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, p.advance()
+	case p.peek("{"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		obj := map[string]interface{}{}
+		for !p.peek("}") {
+			name, err := p.name()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			if obj[name], err = p.value(constant); err != nil {
+				return nil, err
+			}
+		}
+		return obj, p.advance()
+	}
+	return nil, p.unexpected()
+}
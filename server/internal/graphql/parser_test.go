@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(`
+		# yorum satırı
+		query Q($code: String!, $n: Int = 5) {
+			c: course(code: $code) { code ...F @include(if: true) }
+			list(first: $n, tags: ["a", "b"], filter: {x: 1.5, y: null, z: ASC})
+		}
+		fragment F on Course { name }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Operations) != 1 || len(doc.Fragments) != 1 {
+		t.Fatalf("operations = %d, fragments = %d", len(doc.Operations), len(doc.Fragments))
+	}
+	op := doc.Operations[0]
+	if op.Type != "query" || op.Name != "Q" {
+		t.Errorf("operation = %s %s", op.Type, op.Name)
+	}
+	if len(op.Variables) != 2 || op.Variables[0].Type.String() != "String!" ||
+		!op.Variables[1].HasDef || op.Variables[1].Default != 5 {
+		t.Errorf("variables = %+v", op.Variables)
+	}
+
+	course := op.Selections[0].Field
+	if course.Key() != "c" || course.Name != "course" || course.Args[0].Value != Variable("code") {
+		t.Errorf("course = %+v", course)
+	}
+	spread := course.Selections[1]
+	if spread.Spread != "F" || len(spread.Directives) != 1 || spread.Directives[0].Name != "include" {
+		t.Errorf("spread = %+v", spread)
+	}
+
+	args := op.Selections[1].Field.Args
+	want := []Argument{
+		{Name: "first", Value: Variable("n")},
+		{Name: "tags", Value: []interface{}{"a", "b"}},
+		{Name: "filter", Value: map[string]interface{}{"x": 1.5, "y": nil, "z": Enum("ASC")}},
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args = %#v", args)
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`"düz"`, "düz"},
+		{`"a\"b\\c\/d"`, `a"b\c/d`},
+		{`"\n\t"`, "\n\t"},
+		{`"ç"`, "ç"},
+		{`"""  çok "satırlı"
+metin  """`, "çok \"satırlı\"\nmetin"},
+	}
+	for _, tt := range tests {
+		doc, err := Parse(`{ f(s: ` + tt.src + `) }`)
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		if got := doc.Operations[0].Selections[0].Field.Args[0].Value; got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want Location
+	}{
+		{"{ a", Location{1, 4}},
+		{"{\n  a(x: )\n}", Location{2, 8}},
+		{`{ a(s: "açık) }`, Location{1, 16}},
+		{`{ a(s: "\q") }`, Location{1, 9}},
+		{"{ a } fragment F on T { b } fragment F on T { c }", Location{1, 50}},
+		{"fragment F on T { b }", Location{}},
+		{"{ a ! }", Location{1, 5}},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		var gqlErr *Error
+		if !errors.As(err, &gqlErr) {
+			t.Errorf("%q: err = %v, want *Error", tt.src, err)
+			continue
+		}
+		var got Location
+		if len(gqlErr.Locations) > 0 {
+			got = gqlErr.Locations[0]
+		}
+		if got != tt.want {
+			t.Errorf("%q: location = %v, want %v (%s)", tt.src, got, tt.want, gqlErr.Message)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Alan çözücüsü. Aynı seviyedeki tüm üst nesneler tek çağrıda verilir, böylece iç içe
+// alanlar (ör. her bölümün dersleri) tek sorguyla toplu yüklenebilir. Dönen dilim
+// parents ile aynı uzunlukta ve aynı sırada olmalıdır.
+type Resolver func(ctx context.Context, parents []interface{}, args map[string]interface{}) ([]interface{}, error)
+
+type Object struct {
+	Name        string
+	Description string
+	Fields      map[string]*Field
+}
+
+type Arg struct {
+	// Int, Float, String, Boolean; zorunlu argümanlar ! ile biter.
+	Type        string
+	Default     interface{}
+	Description string
+}
+
+type Field struct {
+	// Şemadaki tip gösterimi, ör. [Course!]!
+	Type        string
+	Description string
+	Args        map[string]Arg
+	// Alan nesne döndürüyorsa tipi; skaler alanlarda nil.
+	Object *Object
+	List   bool
+	// Liste alanının karmaşıklık hesabında varsayılan eleman sayısı. limit ya da first
+	// argümanı verilmişse o kullanılır.
+	Size int
+	// nil ise değer üst nesnenin aynı json etiketli alanından okunur.
+	Resolve Resolver
+}
+
+type Schema struct {
+	Query *Object
+	// İç içe alan derinliği sınırı
+	MaxDepth int
+	// Sorgunun tahmini maliyet sınırı: her alan 1, liste alanlarının alt seçimleri eleman
+	// sayısıyla çarpılır.
+	MaxComplexity int
+}
+
+// Çözücülerin ortak hatası. Message istemciye gösterilir; Err doluysa iç hatadır ve
+// ayrıntısı istemciye verilmemelidir.
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+	Err       error         `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// İstemciye olduğu gibi gösterilecek çözücü hatası üretir.
+func Errorf(format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...)}
+}
+
+// Şemayı GraphQL SDL olarak yazar; istemcilerin kod üretimi için.
+func (s *Schema) SDL() string {
+	objects := map[string]*Object{}
+	var walk func(o *Object)
+	walk = func(o *Object) {
+		if _, seen := objects[o.Name]; seen {
+			return
+		}
+		objects[o.Name] = o
+		for _, f := range o.Fields {
+			if f.Object != nil {
+				walk(f.Object)
+			}
+		}
+	}
+	walk(s.Query)
+
+	names := sortedKeys(objects)
+	var sb strings.Builder
+	for i, name := range names {
+		o := objects[name]
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		writeDescription(&sb, "", o.Description)
+		sb.WriteString("type " + o.Name + " {\n")
+		for _, fname := range sortedKeys(o.Fields) {
+			f := o.Fields[fname]
+			writeDescription(&sb, "  ", f.Description)
+			sb.WriteString("  " + fname)
+			if len(f.Args) > 0 {
+				var args []string
+				for _, aname := range sortedKeys(f.Args) {
+					a := f.Args[aname]
+					arg := aname + ": " + a.Type
+					if a.Default != nil {
+						arg += fmt.Sprintf(" = %#v", a.Default)
+					}
+					args = append(args, arg)
+				}
+				sb.WriteString("(" + strings.Join(args, ", ") + ")")
+			}
+			sb.WriteString(": " + f.Type + "\n")
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+func writeDescription(sb *strings.Builder, indent, desc string) {
+	if desc != "" {
+		sb.WriteString(indent + `"""` + desc + `"""` + "\n")
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Struct alanlarının json etiketine göre indeksleri, tip başına bir kez hesaplanır.
+var jsonFields sync.Map
+
+func fieldIndex(t reflect.Type) map[string][]int {
+	if cached, ok := jsonFields.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	index := map[string][]int{}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, dup := index[name]; !dup {
+			index[name] = f.Index
+		}
+	}
+	jsonFields.Store(t, index)
+	return index
+}
+
+// Varsayılan çözücü: değeri üst nesnenin json etiketi alan adına eşit olan alanından okur.
+func structField(parent interface{}, name string) (interface{}, error) {
+	v := reflect.ValueOf(parent)
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Map {
+		if mv := v.MapIndex(reflect.ValueOf(name)); mv.IsValid() {
+			return mv.Interface(), nil
+		}
+		return nil, nil
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("graphql: %s alanı %s tipinden okunamaz", name, v.Type())
+	}
+	index, ok := fieldIndex(v.Type())[name]
+	if !ok {
+		return nil, fmt.Errorf("graphql: %s tipinde %s alanı yok", v.Type(), name)
+	}
+	return v.FieldByIndex(index).Interface(), nil
+}
+
+// Struct'ın skaler alanlarını json etiketleriyle şema alanlarına çevirir; şema tipleri
+// böylece modellerle aynı kalır. İç içe struct alanları atlanır, onlar ayrıca tanımlanır.
+func ScalarFields(v interface{}) map[string]*Field {
+	fields := map[string]*Field{}
+	t := reflect.TypeOf(v)
+	for name, index := range fieldIndex(t) {
+		if typ := scalarType(t.FieldByIndex(index).Type); typ != "" {
+			fields[name] = &Field{Type: typ}
+		}
+	}
+	return fields
+}
+
+func scalarType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return strings.TrimSuffix(scalarType(t.Elem()), "!")
+	case reflect.Bool:
+		return "Boolean!"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Int!"
+	case reflect.Float32, reflect.Float64:
+		return "Float!"
+	case reflect.String:
+		return "String!"
+	case reflect.Slice:
+		// nil dilimler null yazıldığından liste tipleri null olabilir.
+		if elem := scalarType(t.Elem()); elem != "" {
+			return "[" + elem + "]"
+		}
+	}
+	return ""
+}
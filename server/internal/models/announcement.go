@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CMS duyuru kaynağı: sitenin anahtarı ve duyuru kategorisi
+type AnnouncementSource struct {
+	SiteKey    string `json:"site"`
+	CategoryID int    `json:"category"`
+}
+
+// Üniversite CMS'inden toplanan duyuru. Aynı duyuru sitenin birden fazla kategorisinde
+// yayımlanabilir; site ve CMS id'sine göre tek kayıt tutulur.
+type Announcement struct {
+	ID          int64     `json:"id" db:"announcement_id"`
+	Site        string    `json:"site" db:"site_key"`
+	Categories  []int     `json:"categories" doc:"Duyurunun görüldüğü kategoriler"`
+	ExternalID  string    `json:"external_id" db:"external_id" doc:"CMS'teki duyuru id'si"`
+	Title       string    `json:"title" db:"title"`
+	URL         string    `json:"url" db:"url"`
+	PublishedAt time.Time `json:"published_at" db:"published_at"`
+	FirstSeenAt time.Time `json:"first_seen_at" db:"first_seen_at" doc:"Sunucunun duyuruyu ilk gördüğü zaman"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Yönetim uçlarındaki yetki seviyesi. Üst rol alt rollerin yetkilerini de kapsar.
+type Role string
+
+const (
+	// Yönetim verilerini okuyabilir (tarama geçmişi, eşdeğerlik adayları)
+	RoleReader Role = "reader"
+	// Tarama ve hesaplama başlatabilir
+	RoleOperator Role = "operator"
+	// Veri düzeltmesi yapabilir ve anahtarları yönetebilir
+	RoleAdmin Role = "admin"
+)
+
+var roleRanks = map[Role]int{RoleReader: 1, RoleOperator: 2, RoleAdmin: 3}
+
+func (r Role) Valid() bool {
+	_, ok := roleRanks[r]
+	return ok
+}
+
+// r rolü required rolünün yetkilerine sahip mi
+func (r Role) Allows(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
+
+// API anahtarı. Anahtarın kendisi saklanmaz, sadece özeti ve tanımak için ilk karakterleri tutulur.
+type APIKey struct {
+	ID         int64      `json:"id" db:"key_id"`
+	Name       string     `json:"name" db:"name"`
+	Prefix     string     `json:"prefix" db:"key_prefix"`
+	Role       Role       `json:"role" db:"role"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Mobil uygulamanın çevrimdışı çalışabilmesi için bir bölümün tüm verisi
+type Bundle struct {
+	// İçeriğin özeti. Değişmediyse uygulama paketi yeniden indirmez.
+	Version        string          `json:"version"`
+	Faculty        Faculty         `json:"faculty"`
+	Department     Department      `json:"department"`
+	Courses        []Course        `json:"courses"`
+	Details        []CourseDetail  `json:"details"`
+	ElectiveGroups []ElectiveGroup `json:"elective_groups"`
+}
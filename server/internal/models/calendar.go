@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Üniversitenin saat dilimi. CMS tarihleri bu bölgede ve saat dilimi olmadan gelir, takvim
+// akışları da bu bölgede yazılır. Sunucuda tzdata yoksa Türkiye'nin 2016'dan beri sabit
+// olan UTC+3 farkı kullanılır.
+var Istanbul = func() *time.Location {
+	if loc, err := time.LoadLocation("Europe/Istanbul"); err == nil {
+		return loc
+	}
+	return time.FixedZone("Europe/Istanbul", 3*60*60)
+}()
+
+// Akademik takvim kategorileri
+const (
+	CalendarRegistration = "registration"
+	CalendarAddDrop      = "add_drop"
+	CalendarClasses      = "classes"
+	CalendarExam         = "exam"
+	CalendarHoliday      = "holiday"
+	CalendarOther        = "other"
+)
+
+var CalendarCategories = []string{
+	CalendarRegistration, CalendarAddDrop, CalendarClasses, CalendarExam, CalendarHoliday, CalendarOther,
+}
+
+// Dönemler
+const (
+	TermFall   = "fall"
+	TermSpring = "spring"
+	TermSummer = "summer"
+)
+
+// Akademik takvimdeki bir satır. Tarihler YYYY-AA-GG biçimindedir, bitiş dahildir.
+type CalendarEvent struct {
+	ID           int64  `json:"id" db:"event_id"`
+	UID          string `json:"uid" db:"uid" doc:"Takvim akışında değişmeyen kimlik"`
+	AcademicYear string `json:"academic_year" db:"academic_year" doc:"ör. 2025-2026"`
+	Term         string `json:"term,omitempty" db:"term" doc:"fall, spring ya da summer"`
+	Category     string `json:"category" db:"category" doc:"registration, add_drop, classes, exam, holiday ya da other"`
+	Title        string `json:"title" db:"title"`
+	StartDate    string `json:"start_date" db:"start_date"`
+	EndDate      string `json:"end_date" db:"end_date"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Müfredat değişikliği türleri
+const (
+	ChangeCourseAdded   = "course.added"
+	ChangeCourseRemoved = "course.removed"
+	ChangeCourseUpdated = "course.updated"
+	ChangeDetailUpdated = "detail.updated"
+)
+
+// İlk tam taramadan sonra tespit edilen ders ya da izlence değişikliği
+type CourseChange struct {
+	ID           int64     `json:"id" db:"change_id"`
+	DepartmentID int       `json:"department_id" db:"department_id"`
+	CourseCode   string    `json:"course_code" db:"course_code"`
+	CourseName   string    `json:"course_name"`
+	Kind         string    `json:"kind" db:"kind" doc:"course.added, course.removed, course.updated ya da detail.updated"`
+	ChangedAt    time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// Değişiklik türlerinin açıklamaları; %s ders kodu ve adıdır.
+var changeTitles = map[string]map[string]string{
+	ChangeCourseAdded:   {LangTR: "%s müfredata eklendi", LangEN: "%s added to the curriculum"},
+	ChangeCourseRemoved: {LangTR: "%s müfredattan kaldırıldı", LangEN: "%s removed from the curriculum"},
+	ChangeCourseUpdated: {LangTR: "%s ders bilgileri güncellendi", LangEN: "%s course information updated"},
+	ChangeDetailUpdated: {LangTR: "%s izlencesi güncellendi", LangEN: "%s syllabus updated"},
+}
+
+// Değişikliği istenen dilde tek cümleyle anlatır (ör. "MAT101 Matematik I müfredata eklendi").
+func (c CourseChange) Describe(lang string) string {
+	course := c.CourseCode
+	if c.CourseName != "" {
+		course += " " + c.CourseName
+	}
+	if lang != LangEN {
+		lang = LangTR
+	}
+	if format, ok := changeTitles[c.Kind][lang]; ok {
+		return fmt.Sprintf(format, course)
+	}
+	return course
+}
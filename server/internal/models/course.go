@@ -8,6 +8,7 @@ type Course struct {
 	DepartmentID   int     `json:"department_id" db:"department_id"`
 	DepartmentGUID string  `json:"department_guid,omitempty"`
 	Name           string  `json:"name" db:"course_name"`
+	NameEn         string  `json:"name_en,omitempty" db:"course_name_en"`
 	Credit         float64 `json:"credit" db:"credit"`
 	ECTS           float64 `json:"ects" db:"ects"`
 	IsMandatory    bool    `json:"is_mandatory" db:"is_mandatory"`
@@ -32,6 +33,15 @@ type CourseDetail struct {
 	Content    string   `json:"content" db:"content"`
 	Resources  string   `json:"resources" db:"resources"`
 	Outcomes   []string `json:"outcomes" db:"outcomes"`
+
+	// İngilizce izlenceden; yoksa boş
+	AimEn       string   `json:"aim_en,omitempty" db:"aim_en"`
+	ContentEn   string   `json:"content_en,omitempty" db:"content_en"`
+	ResourcesEn string   `json:"resources_en,omitempty" db:"resources_en"`
+	OutcomesEn  []string `json:"outcomes_en,omitempty" db:"outcomes_en"`
+
+	// Ön koşul derslerinin kodları
+	Prerequisites []string `json:"prerequisites"`
 }
 
 func (d *CourseDetail) HasContent() bool {
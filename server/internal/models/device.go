@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type Platform string
+
+const (
+	PlatformAndroid Platform = "android"
+	PlatformIOS     Platform = "ios"
+	PlatformWeb     Platform = "web"
+)
+
+func (p Platform) Valid() bool {
+	return p == PlatformAndroid || p == PlatformIOS || p == PlatformWeb
+}
+
+// Cihazın bildirim almak istediği konular
+type Subscriptions struct {
+	Departments []string `json:"departments" doc:"Bölüm guid'leri; müfredat değişiklikleri"`
+	Faculties   []int    `json:"faculties" doc:"Fakülte id'leri; fakültedeki bütün bölümlerin müfredat değişiklikleri"`
+	Sites       []string `json:"sites" doc:"CMS site anahtarları; yeni duyurular"`
+}
+
+// Bildirim alan cihaz. ID sunucunun verdiği tahmin edilemez değerdir ve cihazı yönetmek
+// için kullanılır; push jetonu sadece bildirim servisine iletilir.
+type Device struct {
+	ID            string        `json:"id" db:"device_id"`
+	Token         string        `json:"-" db:"push_token"`
+	Platform      Platform      `json:"platform" db:"platform"`
+	Language      string        `json:"language" db:"language"`
+	Subscriptions Subscriptions `json:"subscriptions"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// Cihazlara gönderilen bildirim
+type Notification struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	URL   string            `json:"url,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+}
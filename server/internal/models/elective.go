@@ -0,0 +1,15 @@
+package models
+
+// Seçmeli ders grubu (havuz). Müfredattaki "Seçmeli I" gibi yer tutucu satırlardan
+// ve havuz başlıkları altındaki derslerden oluşturulur.
+type ElectiveGroup struct {
+	ID            int      `json:"id" db:"group_id"`
+	DepartmentID  int      `json:"department_id" db:"department_id"`
+	Name          string   `json:"name" db:"group_name"`
+	Semester      string   `json:"semester" db:"semester"`
+	RequiredCount int      `json:"required_count" db:"required_count"`
+	RequiredECTS  float64  `json:"required_ects" db:"required_ects"`
+	Year          int      `json:"year" db:"year"`
+	CourseCodes   []string `json:"-"`
+	Courses       []Course `json:"courses"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type EquivalenceStatus string
+
+const (
+	EquivalenceCandidate EquivalenceStatus = "candidate"
+	EquivalenceConfirmed EquivalenceStatus = "confirmed"
+	EquivalenceRejected  EquivalenceStatus = "rejected"
+)
+
+// Farklı kodlu iki dersin eşdeğerliği. Çift, CourseCode < EquivalentCode olacak şekilde saklanır.
+type CourseEquivalence struct {
+	CourseCode     string            `json:"course_code" db:"course_code"`
+	CourseName     string            `json:"course_name"`
+	EquivalentCode string            `json:"equivalent_code" db:"equivalent_code"`
+	EquivalentName string            `json:"equivalent_name"`
+	Score          float64           `json:"score" db:"score"`
+	Status         EquivalenceStatus `json:"status" db:"status"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// Eşdeğerlik karşılaştırmasında kullanılan ders özeti
+type CourseProfile struct {
+	Code     string
+	Name     string
+	ECTS     float64
+	Theory   int
+	Practice int
+	Lab      int
+	Aim      string
+	Content  string
+}
@@ -0,0 +1,70 @@
+package models
+
+import "strings"
+
+const (
+	LangTR = "tr"
+	LangEN = "en"
+)
+
+// İstenen dildeki metni döner; o dilde metin yoksa diğer dile düşer.
+func pick(lang, tr, en string) string {
+	if lang == LangEN {
+		tr, en = en, tr
+	}
+	if strings.TrimSpace(tr) != "" {
+		return tr
+	}
+	return en
+}
+
+func pickList(lang string, tr, en []string) []string {
+	if lang == LangEN {
+		tr, en = en, tr
+	}
+	if len(tr) > 0 {
+		return tr
+	}
+	return en
+}
+
+// Tek dilli fakülte görünümü
+type LocalizedFaculty struct {
+	ID   int    `json:"id"`
+	GUID string `json:"guid"`
+	Name string `json:"name"`
+}
+
+func (f Faculty) Localize(lang string) LocalizedFaculty {
+	return LocalizedFaculty{ID: f.ID, GUID: f.GUID, Name: pick(lang, f.Name, f.NameEn)}
+}
+
+// Tek dilli bölüm görünümü
+type LocalizedDepartment struct {
+	ID        int    `json:"id"`
+	FacultyID int    `json:"faculty_id"`
+	GUID      string `json:"guid"`
+	Name      string `json:"name"`
+}
+
+func (d Department) Localize(lang string) LocalizedDepartment {
+	return LocalizedDepartment{ID: d.ID, FacultyID: d.FacultyID, GUID: d.GUID, Name: pick(lang, d.Name, d.NameEn)}
+}
+
+// Adı istenen dilde olan kopyayı döner; İngilizce alan boşaltılır.
+func (c Course) Localize(lang string) Course {
+	c.Name = pick(lang, c.Name, c.NameEn)
+	c.NameEn = ""
+	return c
+}
+
+// Ders bilgisi, amaç, içerik, kaynaklar ve çıktıları istenen dilde olan kopyayı döner.
+func (d CourseDetail) Localize(lang string) CourseDetail {
+	d.BaseInfo = d.BaseInfo.Localize(lang)
+	d.Aim = pick(lang, d.Aim, d.AimEn)
+	d.Content = pick(lang, d.Content, d.ContentEn)
+	d.Resources = pick(lang, d.Resources, d.ResourcesEn)
+	d.Outcomes = pickList(lang, d.Outcomes, d.OutcomesEn)
+	d.AimEn, d.ContentEn, d.ResourcesEn, d.OutcomesEn = "", "", "", nil
+	return d
+}
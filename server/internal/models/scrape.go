@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type ScrapeStatus string
+
+const (
+	ScrapeRunning   ScrapeStatus = "running"
+	ScrapeSucceeded ScrapeStatus = "succeeded"
+	ScrapeFailed    ScrapeStatus = "failed"
+)
+
+// EBS tarama geçmişi
+type ScrapeRun struct {
+	ID         int64        `json:"id" db:"run_id"`
+	StartedAt  time.Time    `json:"started_at" db:"started_at"`
+	FinishedAt *time.Time   `json:"finished_at,omitempty" db:"finished_at"`
+	Status     ScrapeStatus `json:"status" db:"status"`
+	Error      string       `json:"error,omitempty" db:"error"`
+}
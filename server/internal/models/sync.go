@@ -0,0 +1,21 @@
+package models
+
+// Eşitleme yanıtında silinen kayıt. Type tablo adıdır (faculties, departments, courses,
+// course_details). Key; fakülte/bölüm için id, ders için "kod:bölüm_id",
+// ders detayı için ders kodudur.
+type SyncRemoval struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+}
+
+// Verilen jetondan bu yana eklenen, değişen ve silinen kayıtlar
+type SyncChanges struct {
+	Token string `json:"token"`
+	// Jeton geçersizse (ör. veritabanı sıfırlandıysa) tüm veri döner, istemci yerel veriyi silmelidir.
+	Full        bool           `json:"full"`
+	Faculties   []Faculty      `json:"faculties"`
+	Departments []Department   `json:"departments"`
+	Courses     []Course       `json:"courses"`
+	Details     []CourseDetail `json:"details"`
+	Removed     []SyncRemoval  `json:"removed"`
+}
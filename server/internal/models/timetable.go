@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Bölüm ders programından okunan haftalık ders saati
+type TimetableSlot struct {
+	ID           int64   `json:"id" db:"slot_id"`
+	DepartmentID int     `json:"department_id" db:"department_id"`
+	CourseCode   string  `json:"course_code,omitempty" db:"course_code" doc:"Müfredatla eşleşmediyse boş"`
+	CourseName   string  `json:"course_name" db:"course_name" doc:"PDF'teki ad"`
+	Day          string  `json:"day" db:"day" doc:"Pazartesi, Salı, Çarşamba, Perşembe ya da Cuma"`
+	Start        string  `json:"start" db:"start_time" doc:"SS:DD"`
+	End          string  `json:"end" db:"end_time" doc:"SS:DD"`
+	Room         string  `json:"room,omitempty" db:"room"`
+	Instructor   string  `json:"instructor,omitempty" db:"instructor"`
+	Semester     string  `json:"semester,omitempty" db:"semester" doc:"ör. 3. Yarıyıl"`
+	Section      string  `json:"section,omitempty" db:"section" doc:"ör. Grup 1"`
+	MatchScore   float64 `json:"match_score" db:"match_score" doc:"Ders adı eşleşme skoru (0-1)"`
+}
+
+// Bölümün ders programı PDF'inin bulunduğu sayfa ve son alım sonucu
+type TimetableSource struct {
+	DepartmentID   int        `json:"department_id" db:"department_id"`
+	DepartmentGUID string     `json:"department_guid"`
+	PageURL        string     `json:"page_url" db:"page_url" doc:"PDF bağlantısının arandığı sayfa; doğrudan PDF adresi de olabilir"`
+	PDFURL         string     `json:"pdf_url,omitempty" db:"pdf_url" doc:"Son alınan PDF"`
+	ContentHash    string     `json:"content_hash,omitempty" db:"content_hash"`
+	FetchedAt      *time.Time `json:"fetched_at,omitempty" db:"fetched_at"`
+	SlotCount      int        `json:"slot_count" db:"slot_count"`
+	MatchedCount   int        `json:"matched_count" db:"matched_count" doc:"Ders koduyla eşleşen satır sayısı"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+}
+
+// Öğrencinin seçtiği ders ve varsa şubesi
+type CourseSelection struct {
+	Code string `json:"code"`
+	// Boşsa dersin bütün şubeleri alınır; "Grup 2" ya da "2" verilebilir.
+	Section string `json:"section,omitempty"`
+}
+
+// Öğrencinin seçtiği derslerin haftalık programını içeren kişisel takvim akışı
+type TimetableFeed struct {
+	Token          string            `json:"token,omitempty" doc:"Sadece oluşturulurken döner, tekrar elde edilemez"`
+	URL            string            `json:"url,omitempty" doc:"Takvim uygulamasına eklenecek .ics adresi; sadece oluşturulurken döner"`
+	DepartmentID   int               `json:"-" db:"department_id"`
+	DepartmentGUID string            `json:"department_guid,omitempty"`
+	Courses        []CourseSelection `json:"courses" db:"courses"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Ders olayları müfredat değişiklik türleriyle aynıdır (bkz. ChangeCourseAdded).
+const (
+	EventAnnouncementPublished = "announcement.published"
+	EventScrapeCompleted       = "scrape.completed"
+	// Bütün olaylar
+	EventAll = "*"
+)
+
+var WebhookEvents = []string{
+	ChangeCourseAdded, ChangeCourseRemoved, ChangeCourseUpdated, ChangeDetailUpdated,
+	EventAnnouncementPublished, EventScrapeCompleted,
+}
+
+func ValidWebhookEvent(event string) bool {
+	if event == EventAll {
+		return true
+	}
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Entegrasyonların olay bildirimi aldığı adres. İmza anahtarı sadece oluşturulurken döner.
+type Webhook struct {
+	ID        int64     `json:"id" db:"webhook_id"`
+	URL       string    `json:"url" db:"url"`
+	Events    []string  `json:"events" db:"events"`
+	Secret    string    `json:"secret,omitempty" db:"secret" doc:"HMAC-SHA256 imza anahtarı; sadece oluşturulurken döner"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Bir olayın bir webhook'a gönderim kaydı. Başarısız denemeler üstel artan aralıklarla
+// tekrarlanır; deneme hakkı biten gönderim failed olur.
+type WebhookDelivery struct {
+	ID             int64          `json:"id" db:"delivery_id"`
+	WebhookID      int64          `json:"webhook_id" db:"webhook_id"`
+	EventID        int64          `json:"event_id" db:"event_id"`
+	Event          string         `json:"event"`
+	Status         DeliveryStatus `json:"status" db:"status"`
+	Attempts       int            `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	LastStatusCode int            `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+}
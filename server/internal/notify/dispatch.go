@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+// Tek gönderimdeki en fazla cihaz (FCM multicast sınırı)
+const batchSize = 500
+
+// Bildirim gövdesinde listelenen en fazla kayıt; fazlası sayı olarak belirtilir.
+const maxBodyLines = 3
+
+// Yeni duyuruları ve müfredat değişikliklerini abone cihazlara dağıtır. Her konu için
+// cihaz başına tek bildirim gönderilir; bildirim metni cihazın dilindedir.
+type Dispatcher struct {
+	db       *sql.DB
+	notifier Notifier
+}
+
+func NewDispatcher(db *sql.DB, notifier Notifier) *Dispatcher {
+	return &Dispatcher{db: db, notifier: notifier}
+}
+
+// Duyuruları sitelere göre gruplar ve sitelere abone cihazlara bildirir.
+func (d *Dispatcher) NotifyAnnouncements(ctx context.Context, announcements []models.Announcement) {
+	bySite := make(map[string][]models.Announcement)
+	var sites []string
+	for _, a := range announcements {
+		if _, ok := bySite[a.Site]; !ok {
+			sites = append(sites, a.Site)
+		}
+		bySite[a.Site] = append(bySite[a.Site], a)
+	}
+
+	for _, site := range sites {
+		items := bySite[site]
+		// Hatalar fanOut'ta loglanır; duyurular yeniden bildirilmez.
+		_ = d.fanOut(ctx, []string{storage.SiteTopic(site)}, func(lang string) models.Notification {
+			n := models.Notification{Data: map[string]string{"type": "announcement", "site": site}}
+			if len(items) == 1 {
+				n.Title = pick(lang, "Yeni duyuru", "New announcement")
+				n.Body = items[0].Title
+				n.URL = items[0].URL
+				n.Data["id"] = strconv.FormatInt(items[0].ID, 10)
+				return n
+			}
+			n.Title = fmt.Sprintf(pick(lang, "%d yeni duyuru", "%d new announcements"), len(items))
+			titles := make([]string, len(items))
+			for i, a := range items {
+				titles[i] = a.Title
+			}
+			n.Body = summarize(lang, titles)
+			return n
+		})
+	}
+}
+
+// Değişiklikleri bölümlere göre gruplar; bölüme ya da fakültesine abone cihazlara bildirir.
+// Bir gönderim başarısız olursa hata döner; çağıran değişiklikleri sonra yeniden bildirebilir.
+func (d *Dispatcher) NotifyCourseChanges(ctx context.Context, changes []models.CourseChange) error {
+	byDepartment := make(map[int][]models.CourseChange)
+	var ids []int
+	for _, c := range changes {
+		if _, ok := byDepartment[c.DepartmentID]; !ok {
+			ids = append(ids, c.DepartmentID)
+		}
+		byDepartment[c.DepartmentID] = append(byDepartment[c.DepartmentID], c)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	sort.Ints(ids)
+
+	departments, _, err := storage.ListDepartments(d.db, storage.DepartmentQuery{IDs: ids})
+	if err != nil {
+		return fmt.Errorf("bildirim için bölümler okunamadı: %w", err)
+	}
+
+	var errs []error
+	for _, dept := range departments {
+		items := byDepartment[dept.ID]
+		topics := []string{storage.DepartmentTopic(dept.GUID), storage.FacultyTopic(dept.FacultyID)}
+		errs = append(errs, d.fanOut(ctx, topics, func(lang string) models.Notification {
+			n := models.Notification{
+				Title: dept.Localize(lang).Name,
+				Data:  map[string]string{"type": "curriculum", "department": dept.GUID},
+			}
+			if len(items) == 1 {
+				n.Body = items[0].Describe(lang)
+				n.Data["course"] = items[0].CourseCode
+				return n
+			}
+			lines := make([]string, len(items))
+			for i, c := range items {
+				lines[i] = c.Describe(lang)
+			}
+			n.Body = fmt.Sprintf(pick(lang, "%d müfredat değişikliği", "%d curriculum changes"), len(items)) +
+				"\n" + summarize(lang, lines)
+			return n
+		}))
+	}
+	return errors.Join(errs...)
+}
+
+// Konulara abone cihazları dile göre gruplar ve bildirimi parça parça gönderir.
+// Gönderim hataları loglanır ve döner; bir parçadaki hata diğerlerini durdurmaz.
+func (d *Dispatcher) fanOut(ctx context.Context, topics []string, build func(lang string) models.Notification) error {
+	devices, err := storage.GetSubscribedDevices(d.db, topics)
+	if err != nil {
+		log.Printf("%v için aboneler okunamadı: %v", topics, err)
+		return err
+	}
+
+	byLang := make(map[string][]models.Device)
+	for _, dev := range devices {
+		lang := dev.Language
+		if lang != models.LangEN {
+			lang = models.LangTR
+		}
+		byLang[lang] = append(byLang[lang], dev)
+	}
+
+	var errs []error
+	for _, lang := range []string{models.LangTR, models.LangEN} {
+		group := byLang[lang]
+		if len(group) == 0 {
+			continue
+		}
+		n := build(lang)
+		for start := 0; start < len(group); start += batchSize {
+			batch := group[start:min(start+batchSize, len(group))]
+			invalid, err := d.notifier.Send(ctx, batch, n)
+			if err != nil {
+				log.Printf("%v bildirimi gönderilemedi: %v", topics, err)
+				errs = append(errs, err)
+				continue
+			}
+			if len(invalid) > 0 {
+				if err := storage.DeleteDevicesByToken(d.db, invalid); err != nil {
+					log.Printf("Geçersiz cihazlar silinemedi: %v", err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func pick(lang, tr, en string) string {
+	if lang == models.LangEN {
+		return en
+	}
+	return tr
+}
+
+// İlk birkaç satırı yazar, kalanların sayısını ekler.
+func summarize(lang string, lines []string) string {
+	if len(lines) <= maxBodyLines {
+		return strings.Join(lines, "\n")
+	}
+	rest := len(lines) - maxBodyLines
+	return strings.Join(lines[:maxBodyLines], "\n") + "\n" +
+		fmt.Sprintf(pick(lang, "ve %d tane daha", "and %d more"), rest)
+}
@@ -0,0 +1,113 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+type sent struct {
+	tokens []string
+	n      models.Notification
+}
+
+// Gönderimleri kaydeden, "gecersiz" ile başlayan jetonları geçersiz bildiren notifier
+type fakeNotifier struct {
+	sent []sent
+	err  error
+}
+
+func (f *fakeNotifier) Send(ctx context.Context, devices []models.Device, n models.Notification) ([]string, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	s := sent{n: n}
+	var invalid []string
+	for _, d := range devices {
+		s.tokens = append(s.tokens, d.Token)
+		if strings.HasPrefix(d.Token, "gecersiz") {
+			invalid = append(invalid, d.Token)
+		}
+	}
+	f.sent = append(f.sent, s)
+	return invalid, nil
+}
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertFaculty(db, models.Faculty{ID: 1, GUID: "f1", Name: "Mühendislik"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertDepartment(db, models.Department{ID: 10, FacultyID: 1, GUID: "d1", Name: "Bilgisayar", NameEn: "Computer"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []*models.Device{
+		{Token: "tr", Platform: models.PlatformAndroid, Language: models.LangTR, Subscriptions: models.Subscriptions{Departments: []string{"d1"}}},
+		{Token: "en", Platform: models.PlatformIOS, Language: models.LangEN, Subscriptions: models.Subscriptions{Faculties: []int{1}}},
+		{Token: "gecersiz", Platform: models.PlatformAndroid, Language: models.LangTR, Subscriptions: models.Subscriptions{Departments: []string{"d1"}}},
+	} {
+		if err := storage.SaveDevice(db, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestNotifyCourseChanges(t *testing.T) {
+	db := testDB(t)
+	fake := &fakeNotifier{}
+	d := NewDispatcher(db, fake)
+	changes := []models.CourseChange{
+		{ID: 1, DepartmentID: 10, CourseCode: "MAT101", CourseName: "Matematik I", Kind: models.ChangeCourseAdded},
+		{ID: 2, DepartmentID: 10, CourseCode: "FIZ101", CourseName: "Fizik I", Kind: models.ChangeCourseRemoved},
+	}
+
+	if err := d.NotifyCourseChanges(context.Background(), changes); err != nil {
+		t.Fatal(err)
+	}
+	// Cihazlar dile göre gruplanır: bir Türkçe, bir İngilizce bildirim.
+	if len(fake.sent) != 2 {
+		t.Fatalf("sent = %+v", fake.sent)
+	}
+	tr, en := fake.sent[0], fake.sent[1]
+	sort.Strings(tr.tokens)
+	if strings.Join(tr.tokens, ",") != "gecersiz,tr" || tr.n.Title != "Bilgisayar" || !strings.HasPrefix(tr.n.Body, "2 müfredat") {
+		t.Errorf("tr notification = %+v", tr)
+	}
+	if strings.Join(en.tokens, ",") != "en" || en.n.Title != "Computer" || !strings.HasPrefix(en.n.Body, "2 curriculum") {
+		t.Errorf("en notification = %+v", en)
+	}
+
+	// Geçersiz bildirilen jeton silinir.
+	devices, err := storage.GetSubscribedDevices(db, []string{storage.DepartmentTopic("d1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || devices[0].Token != "tr" {
+		t.Errorf("devices after cleanup = %+v", devices)
+	}
+
+	// Gönderim hatası çağırana döner ki değişiklikler yeniden bildirilebilsin.
+	fake.err = errors.New("servis kapalı")
+	if err := d.NotifyCourseChanges(context.Background(), changes); err == nil {
+		t.Error("NotifyCourseChanges succeeded while the notifier failed")
+	}
+	if err := d.NotifyCourseChanges(context.Background(), nil); err != nil {
+		t.Errorf("no changes: err = %v", err)
+	}
+}
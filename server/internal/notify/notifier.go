@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Bildirimi cihazlara ileten servis (FCM, APNs ya da test için log/webhook).
+// Servisin geçersiz bildirdiği push jetonları döner; bu cihazlar silinir.
+type Notifier interface {
+	Send(ctx context.Context, devices []models.Device, n models.Notification) (invalid []string, err error)
+}
+
+// Bildirimleri sadece loglar; yerel geliştirme için.
+type LogNotifier struct{}
+
+func (LogNotifier) Send(ctx context.Context, devices []models.Device, n models.Notification) ([]string, error) {
+	log.Printf("Bildirim (%d cihaz): %s - %s", len(devices), n.Title, n.Body)
+	return nil, nil
+}
+
+// Bildirimleri JSON olarak bir adrese gönderir. Gerçek push servisine köprü ya da test
+// alıcısı olarak kullanılır. Yanıt gövdesinde invalid_tokens verilebilir.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type webhookTarget struct {
+	Token    string          `json:"token"`
+	Platform models.Platform `json:"platform"`
+}
+
+type webhookPayload struct {
+	Devices      []webhookTarget     `json:"devices"`
+	Notification models.Notification `json:"notification"`
+}
+
+type webhookResult struct {
+	InvalidTokens []string `json:"invalid_tokens"`
+}
+
+func (wn *WebhookNotifier) Send(ctx context.Context, devices []models.Device, n models.Notification) ([]string, error) {
+	payload := webhookPayload{Notification: n}
+	for _, d := range devices {
+		payload.Devices = append(payload.Devices, webhookTarget{Token: d.Token, Platform: d.Platform})
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := wn.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("bildirim gönderilemedi: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("bildirim servisi hata döndü: %s", resp.Status)
+	}
+
+	var result webhookResult
+	// Gövde boş olabilir; sadece geçersiz jeton listesi okunur.
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	return result.InvalidTokens, nil
+}
@@ -0,0 +1,382 @@
+// Ders programı gibi tablo biçimli PDF'lerden konumlu metin çıkaran küçük PDF okuyucu.
+// Sadece metin için gereken kısım desteklenir: nesne akışları, Flate/ASCIIHex/ASCII85
+// filtreleri, basit ve Type0 fontlar (ToUnicode) ve form XObject'leri. Şifreli belgeler okunmaz.
+package pdf
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// Sayfa ağacı ve form iç içeliği için üst sınır; döngülü belgelere karşı
+const maxDepth = 32
+
+// Belge başına çözülen akış boyutu ve çalıştırılan içerik işlemi için üst sınırlar.
+// Sıkıştırma bombaları ve kendini tekrar tekrar çizen formlar ayrıştırmayı kilitlemesin.
+const (
+	maxDecodedSize = 64 << 20
+	maxOperations  = 4 << 20
+)
+
+var ErrTooComplex = errors.New("PDF işlenemeyecek kadar büyük ya da karmaşık")
+
+// Açılmış bir xref tablosuna güvenmek yerine nesne başlıkları dosyada taranır; bozuk ve
+// artımlı güncellenmiş belgelerde de çalışır, sonraki tanım öncekini ezer.
+var objHeaderPattern = regexp.MustCompile(`(?:^|[\r\n\s])(\d+)\s+(\d+)\s+obj\b`)
+
+type Document struct {
+	data    []byte
+	offsets map[int]int
+	// Nesne akışındaki nesneler: numara -> (akış nesnesi, sıra)
+	inStream map[int][2]int
+	cache    map[int]object
+	pages    []*Page
+
+	// Şimdiye kadar çözülen ya da taranan akış baytı ve çalıştırılan işlem sayısı
+	decoded, ops int
+}
+
+func Open(data []byte) (*Document, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data[:min(len(data), 1024)], "\x00\t\r\n "), []byte("%PDF")) {
+		return nil, errors.New("PDF dosyası değil")
+	}
+
+	d := &Document{
+		data:     data,
+		offsets:  make(map[int]int),
+		inStream: make(map[int][2]int),
+		cache:    make(map[int]object),
+	}
+	for _, m := range objHeaderPattern.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		d.offsets[num] = m[2]
+	}
+	d.indexObjectStreams()
+
+	trailer := d.trailer()
+	if _, encrypted := trailer["Encrypt"]; encrypted {
+		return nil, errors.New("şifreli PDF desteklenmiyor")
+	}
+	root, _ := d.resolve(trailer["Root"]).(dict)
+	if root == nil {
+		root = d.findCatalog()
+	}
+	if root == nil {
+		return nil, errors.New("PDF katalog nesnesi bulunamadı")
+	}
+
+	d.collectPages(d.resolve(root["Pages"]), inherited{}, 0)
+	if d.exhausted() {
+		return nil, ErrTooComplex
+	}
+	if len(d.pages) == 0 {
+		return nil, errors.New("PDF'te sayfa bulunamadı")
+	}
+	return d, nil
+}
+
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// Bütçe aşıldıysa belge daha fazla işlenmez.
+func (d *Document) exhausted() bool {
+	return d.decoded > maxDecodedSize || d.ops > maxOperations
+}
+
+// Son "trailer" sözlüğü; yoksa (xref akışlı belgeler) XRef akışının sözlüğü
+func (d *Document) trailer() dict {
+	if idx := bytes.LastIndex(d.data, []byte("trailer")); idx >= 0 {
+		l := &lexer{data: d.data, pos: idx + len("trailer")}
+		if o, err := l.object(); err == nil {
+			if t, ok := o.(dict); ok && t["Root"] != nil {
+				return t
+			}
+		}
+	}
+	best, bestOffset := dict(nil), -1
+	for num, offset := range d.offsets {
+		if s, ok := d.object(num).(*stream); ok && s.dict["Type"] == name("XRef") && offset > bestOffset {
+			best, bestOffset = s.dict, offset
+		}
+	}
+	return best
+}
+
+func (d *Document) findCatalog() dict {
+	for num := range d.offsets {
+		if o, ok := d.object(num).(dict); ok && o["Type"] == name("Catalog") {
+			return o
+		}
+	}
+	return nil
+}
+
+func (d *Document) indexObjectStreams() {
+	for num := range d.offsets {
+		s, ok := d.object(num).(*stream)
+		if !ok || s.dict["Type"] != name("ObjStm") {
+			continue
+		}
+		data, err := d.decode(s)
+		if err != nil {
+			continue
+		}
+		l := &lexer{data: data}
+		n := intOf(d.resolve(s.dict["N"]))
+		for i := 0; i < n; i++ {
+			objNum, ok1 := l.token()
+			_, ok2 := l.token()
+			if !ok1 || !ok2 {
+				break
+			}
+			if on, isInt := objNum.(int64); isInt {
+				// Dosyada doğrudan tanımlı nesne önceliklidir.
+				if _, direct := d.offsets[int(on)]; !direct {
+					d.inStream[int(on)] = [2]int{num, i}
+				}
+			}
+		}
+	}
+}
+
+// Numarası verilen nesneyi okur; yoksa nil döner.
+func (d *Document) object(num int) object {
+	if o, ok := d.cache[num]; ok {
+		return o
+	}
+	d.cache[num] = nil // Döngülü başvurulara karşı
+
+	var o object
+	if offset, ok := d.offsets[num]; ok {
+		o = d.readIndirect(offset)
+	} else if loc, ok := d.inStream[num]; ok {
+		o = d.readFromStream(loc[0], loc[1])
+	}
+	d.cache[num] = o
+	return o
+}
+
+func (d *Document) readIndirect(offset int) object {
+	l := &lexer{data: d.data, pos: offset}
+	for i := 0; i < 3; i++ { // "n g obj"
+		if _, ok := l.token(); !ok {
+			return nil
+		}
+	}
+	o, err := l.object()
+	if err != nil {
+		return nil
+	}
+	sd, isDict := o.(dict)
+	if !isDict {
+		return o
+	}
+
+	save := l.pos
+	if tok, ok := l.token(); !ok || tok != keyword("stream") {
+		l.pos = save
+		return sd
+	}
+	l.skipStreamEOL()
+	start := l.pos
+
+	// Length dolaylı olabilir; tutmuyorsa endstream aranır.
+	if length := intOf(d.resolve(sd["Length"])); length > 0 && start+length <= len(d.data) {
+		rest := bytes.TrimLeft(d.data[start+length:min(len(d.data), start+length+32)], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return &stream{dict: sd, raw: d.data[start : start+length]}
+		}
+	}
+	// Sonu bulunamayan her akış dosyanın geri kalanını taradığından taranan bayt da
+	// bütçeden düşülür; bütçe bitince akışlı nesneler okunmaz.
+	if d.exhausted() {
+		return nil
+	}
+	end := bytes.Index(d.data[start:], []byte("endstream"))
+	if end < 0 {
+		d.decoded += len(d.data) - start
+		return &stream{dict: sd, raw: d.data[start:]}
+	}
+	d.decoded += end
+	raw := bytes.TrimSuffix(d.data[start:start+end], []byte("\n"))
+	raw = bytes.TrimSuffix(raw, []byte("\r"))
+	return &stream{dict: sd, raw: raw}
+}
+
+func (d *Document) readFromStream(streamNum, index int) object {
+	s, ok := d.object(streamNum).(*stream)
+	if !ok {
+		return nil
+	}
+	data, err := d.decode(s)
+	if err != nil {
+		return nil
+	}
+
+	l := &lexer{data: data}
+	offset := -1
+	for i := 0; i <= index; i++ {
+		l.token()
+		off, ok := l.token()
+		if !ok {
+			return nil
+		}
+		if i == index {
+			offset = intOf(off)
+		}
+	}
+	first := intOf(d.resolve(s.dict["First"]))
+	if offset < 0 || first+offset >= len(data) {
+		return nil
+	}
+	l.pos = first + offset
+	o, err := l.object()
+	if err != nil {
+		return nil
+	}
+	return o
+}
+
+// Başvuruyu çözer; başvuru değilse nesnenin kendisini döner.
+func (d *Document) resolve(o object) object {
+	for i := 0; i < maxDepth; i++ {
+		r, ok := o.(ref)
+		if !ok {
+			return o
+		}
+		o = d.object(r.num)
+	}
+	return nil
+}
+
+func (d *Document) dictOf(o object) dict {
+	switch v := d.resolve(o).(type) {
+	case dict:
+		return v
+	case *stream:
+		return v.dict
+	}
+	return nil
+}
+
+// Akışı filtrelerini sırayla uygulayarak çözer.
+func (d *Document) decode(s *stream) ([]byte, error) {
+	if d.exhausted() {
+		return nil, ErrTooComplex
+	}
+	data := s.raw
+	var filters []object
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case name:
+		filters = array{f}
+	case array:
+		filters = f
+	}
+	for _, f := range filters {
+		var err error
+		switch d.resolve(f) {
+		case name("FlateDecode"), name("Fl"):
+			data, err = inflate(data, maxDecodedSize-d.decoded)
+		case name("ASCIIHexDecode"), name("AHx"):
+			data = (&lexer{data: append(append([]byte{'<'}, data...), '>')}).hexString()
+		case name("ASCII85Decode"), name("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("desteklenmeyen PDF filtresi: %v", f)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	d.decoded += len(data)
+	if d.exhausted() {
+		return nil, ErrTooComplex
+	}
+	return data, nil
+}
+
+// Bozuk ya da kesik akışlarda okunabilen kısım döner. Çıktı limit+1 baytta kesilir;
+// aşım belgenin bütçesinde yakalanır.
+func inflate(data []byte, limit int) ([]byte, error) {
+	var r io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		r = zr
+	} else {
+		r = flate.NewReader(bytes.NewReader(data))
+	}
+	out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	data = bytes.TrimPrefix(data, []byte("<~"))
+	if idx := bytes.Index(data, []byte("~>")); idx >= 0 {
+		data = data[:idx]
+	}
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	return out[:n], err
+}
+
+// Sayfa ağacında alt sayfalara geçen öznitelikler
+type inherited struct {
+	resources object
+	mediaBox  object
+	rotate    object
+}
+
+func (d *Document) collectPages(o object, inh inherited, depth int) {
+	node, ok := o.(dict)
+	if !ok || depth > maxDepth {
+		return
+	}
+	if v, ok := node["Resources"]; ok {
+		inh.resources = v
+	}
+	if v, ok := node["MediaBox"]; ok {
+		inh.mediaBox = v
+	}
+	if v, ok := node["Rotate"]; ok {
+		inh.rotate = v
+	}
+
+	if kids, ok := d.resolve(node["Kids"]).(array); ok && node["Type"] != name("Page") {
+		for _, kid := range kids {
+			d.collectPages(d.resolve(kid), inh, depth+1)
+		}
+		return
+	}
+	d.pages = append(d.pages, d.newPage(node, inh))
+}
+
+func intOf(o object) int {
+	switch v := o.(type) {
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+func numOf(o object) (float64, bool) {
+	switch v := o.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,178 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Verilen nesnelerden xref tablosu olmayan küçük bir PDF kurar; okuyucu nesne
+// başlıklarını taradığından bu yeterlidir. İlk nesne katalog olmalıdır.
+func buildPDF(objs ...string) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	for i, o := range objs {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	b.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return b.Bytes()
+}
+
+func streamObj(dict string, data []byte) string {
+	return fmt.Sprintf("<< /Length %d %s >>\nstream\n%s\nendstream", len(data), dict, data)
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	w := zlib.NewWriter(&b)
+	w.Write(data)
+	w.Close()
+	return b.Bytes()
+}
+
+// İçeriği verilen tek sayfalık belge: F1 Helvetica, X1 ise ek nesnelerin ilki (6 0 R).
+func onePage(contentDict string, content []byte, extra ...string) []byte {
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 600 400] /Contents 4 0 R " +
+			"/Resources << /Font << /F1 5 0 R >> /XObject << /X1 6 0 R >> >> >>",
+		streamObj(contentDict, content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	return buildPDF(append(objs, extra...)...)
+}
+
+func lineTexts(t *testing.T, data []byte) ([]string, error) {
+	t.Helper()
+	doc, err := Open(data)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := doc.Pages()[0].Lines()
+	if err != nil {
+		return nil, err
+	}
+	var texts []string
+	for _, l := range lines {
+		texts = append(texts, l.Text)
+	}
+	return texts, nil
+}
+
+func TestPageLines(t *testing.T) {
+	form := func(content string) string {
+		return streamObj("/Type /XObject /Subtype /Form /BBox [0 0 600 400] /Resources << /Font << /F1 5 0 R >> >>", []byte(content))
+	}
+
+	tests := []struct {
+		name    string
+		dict    string
+		content []byte
+		extra   []string
+		want    []string
+	}{
+		{
+			name:    "düz metin",
+			content: []byte("BT /F1 10 Tf 50 350 Td (Veri Yapilari) Tj ET"),
+			want:    []string{"Veri Yapilari"},
+		},
+		{
+			name:    "flate",
+			dict:    "/Filter /FlateDecode",
+			content: deflate([]byte("BT /F1 10 Tf 50 350 Td (Sayisal Analiz) Tj ET")),
+			want:    []string{"Sayisal Analiz"},
+		},
+		{
+			name:    "hex",
+			dict:    "/Filter /ASCIIHexDecode",
+			content: []byte(hex.EncodeToString([]byte("BT /F1 10 Tf 50 350 Td (AB) Tj ET")) + ">"),
+			want:    []string{"AB"},
+		},
+		{
+			name:    "satırlar yukarıdan aşağı",
+			content: []byte("BT /F1 10 Tf 50 100 Td (Alt) Tj ET BT /F1 10 Tf 50 350 Td (Ust) Tj ET"),
+			want:    []string{"Ust", "Alt"},
+		},
+		{
+			name:    "uzak hücreler ayrı",
+			content: []byte("BT /F1 10 Tf 50 350 Td (Pazartesi) Tj 300 0 Td (Sali) Tj ET"),
+			want:    []string{"Pazartesi", "Sali"},
+		},
+		{
+			name:    "TJ aralığı",
+			content: []byte("BT /F1 10 Tf 50 350 Td [(Ders) -300 (Adi)] TJ ET"),
+			want:    []string{"Ders Adi"},
+		},
+		{
+			name:    "form XObject",
+			content: []byte("q 1 0 0 1 0 -100 cm /X1 Do Q"),
+			extra:   []string{form("BT /F1 10 Tf 50 350 Td (Formda) Tj ET")},
+			want:    []string{"Formda"},
+		},
+		{
+			name:    "kendini çizen form",
+			content: []byte("/X1 Do"),
+			extra:   []string{form("/X1 Do")},
+		},
+	}
+	for _, tt := range tests {
+		got, err := lineTexts(t, onePage(tt.dict, tt.content, tt.extra...))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("%s: lines = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"PDF değil", []byte("<html></html>")},
+		{"boş", nil},
+		{"katalog yok", buildPDF("<< /Type /Pages /Kids [] /Count 0 >>")},
+		{"sayfa yok", buildPDF("<< /Type /Catalog /Pages 2 0 R >>", "<< /Type /Pages /Kids [] /Count 0 >>")},
+		{"şifreli", []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R /Encrypt 2 0 R >>\n")},
+	}
+	for _, tt := range tests {
+		if _, err := Open(tt.data); err == nil {
+			t.Errorf("%s: Open succeeded, want error", tt.name)
+		}
+	}
+}
+
+func TestParseBudget(t *testing.T) {
+	// Form kendini iki kez çizer: sınırsız çalıştırılsa 2^32 çağrı olurdu.
+	fanOut := streamObj("/Type /XObject /Subtype /Form /Resources << /XObject << /X1 6 0 R >> >>", []byte("/X1 Do /X1 Do"))
+
+	tests := []struct {
+		name string
+		data []byte
+		// İç içe diziler hatasız atlanır; sadece süre sınırlanır.
+		tooComplex bool
+	}{
+		{"sıkıştırma bombası", onePage("/Filter /FlateDecode", deflate(make([]byte, maxDecodedSize+1))), true},
+		{"çatallanan formlar", onePage("", []byte("/X1 Do"), fanOut), true},
+		{"iç içe diziler", onePage("", []byte(strings.Repeat("[", 1<<20)+" TJ")), false},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		_, err := lineTexts(t, tt.data)
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: took %v", tt.name, elapsed)
+		}
+		if tt.tooComplex && !errors.Is(err, ErrTooComplex) {
+			t.Errorf("%s: err = %v, want ErrTooComplex", tt.name, err)
+		}
+	}
+}
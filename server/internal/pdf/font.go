@@ -0,0 +1,316 @@
+package pdf
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Genişliği bilinmeyen glif için varsayılan (1/1000 em)
+const defaultGlyphWidth = 500
+
+type font struct {
+	// Karakter kodunun bayt uzunluğu: basit fontlarda 1, Type0 fontlarda genelde 2
+	codeLen   int
+	toUnicode map[uint32]string
+	// Basit fontların kod -> karakter tablosu (ToUnicode yoksa)
+	encoding     *[256]rune
+	widths       map[uint32]float64
+	defaultWidth float64
+	// Type3 fontlarda glif uzayından metin uzayına ölçek
+	scale float64
+}
+
+type glyph struct {
+	text  string
+	width float64 // metin uzayında, font boyutu 1 için
+	space bool    // tek baytlık 32 kodu; kelime aralığı uygulanır
+}
+
+func (d *Document) loadFont(o object) *font {
+	fd := d.dictOf(o)
+	f := &font{codeLen: 1, widths: make(map[uint32]float64), defaultWidth: defaultGlyphWidth, scale: 0.001}
+	if fd == nil {
+		f.encoding = &winAnsiEncoding
+		return f
+	}
+
+	if s, ok := d.resolve(fd["ToUnicode"]).(*stream); ok {
+		if data, err := d.decode(s); err == nil {
+			f.toUnicode, f.codeLen = parseCMap(data)
+		}
+	}
+
+	if fd["Subtype"] == name("Type0") {
+		if f.toUnicode == nil {
+			f.codeLen = 2
+		}
+		f.codeLen = max(f.codeLen, 2)
+		f.defaultWidth = 1000
+		if descendants, ok := d.resolve(fd["DescendantFonts"]).(array); ok && len(descendants) > 0 {
+			cid := d.dictOf(descendants[0])
+			if dw, ok := numOf(d.resolve(cid["DW"])); ok {
+				f.defaultWidth = dw
+			}
+			d.readCIDWidths(f, cid["W"])
+		}
+		return f
+	}
+
+	f.encoding = d.simpleEncoding(fd["Encoding"])
+	if fm, ok := d.resolve(fd["FontMatrix"]).(array); ok && len(fm) > 0 {
+		if v, ok := numOf(d.resolve(fm[0])); ok && v != 0 {
+			f.scale = v
+		}
+	}
+	if desc := d.dictOf(fd["FontDescriptor"]); desc != nil {
+		if mw, ok := numOf(d.resolve(desc["MissingWidth"])); ok && mw > 0 {
+			f.defaultWidth = mw
+		}
+	}
+	first := intOf(d.resolve(fd["FirstChar"]))
+	if widths, ok := d.resolve(fd["Widths"]).(array); ok {
+		for i, w := range widths {
+			if v, ok := numOf(d.resolve(w)); ok {
+				f.widths[uint32(first+i)] = v
+			}
+		}
+	}
+	return f
+}
+
+// /W dizisi: "c [w1 w2 ...]" ya da "cİlk cSon w" grupları
+func (d *Document) readCIDWidths(f *font, o object) {
+	w, ok := d.resolve(o).(array)
+	if !ok {
+		return
+	}
+	for i := 0; i < len(w); {
+		start := intOf(d.resolve(w[i]))
+		if i+1 >= len(w) {
+			return
+		}
+		if list, ok := d.resolve(w[i+1]).(array); ok {
+			for j, v := range list {
+				if width, ok := numOf(d.resolve(v)); ok {
+					f.widths[uint32(start+j)] = width
+				}
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			return
+		}
+		end := intOf(d.resolve(w[i+1]))
+		if width, ok := numOf(d.resolve(w[i+2])); ok && end-start < 1<<16 {
+			for c := start; c <= end; c++ {
+				f.widths[uint32(c)] = width
+			}
+		}
+		i += 3
+	}
+}
+
+// Basit fontun kodlaması. Temel kodlama adı ne olursa olsun WinAnsi üzerine
+// /Differences uygulanır; Türkçe karakterler genelde Differences ile gelir.
+func (d *Document) simpleEncoding(o object) *[256]rune {
+	enc := winAnsiEncoding
+	ed, ok := d.resolve(o).(dict)
+	if !ok {
+		return &enc
+	}
+	if diffs, ok := d.resolve(ed["Differences"]).(array); ok {
+		code := 0
+		for _, item := range diffs {
+			switch v := d.resolve(item).(type) {
+			case int64:
+				code = int(v)
+			case name:
+				if code >= 0 && code < 256 {
+					if r, ok := glyphRune(string(v)); ok {
+						enc[code] = r
+					}
+				}
+				code++
+			}
+		}
+	}
+	return &enc
+}
+
+// Dizgeyi fontun kodlarına ayırıp gliflere çevirir.
+func (f *font) decode(s []byte) []glyph {
+	var glyphs []glyph
+	for i := 0; i < len(s); {
+		n := min(f.codeLen, len(s)-i)
+		var code uint32
+		for _, b := range s[i : i+n] {
+			code = code<<8 | uint32(b)
+		}
+		i += n
+
+		g := glyph{space: n == 1 && code == 32}
+		if text, ok := f.toUnicode[code]; ok {
+			g.text = text
+		} else if f.encoding != nil && code < 256 && f.encoding[code] != 0 {
+			g.text = string(f.encoding[code])
+		}
+		w, ok := f.widths[code]
+		if !ok {
+			w = f.defaultWidth
+		}
+		g.width = w * f.scale
+		glyphs = append(glyphs, g)
+	}
+	return glyphs
+}
+
+// ToUnicode CMap'ini okur; kod -> metin tablosunu ve kod uzunluğunu döner.
+func parseCMap(data []byte) (map[uint32]string, int) {
+	table := make(map[uint32]string)
+	codeLen := 1
+	l := &lexer{data: data}
+
+	var operands []object
+	for {
+		tok, ok := l.token()
+		if !ok {
+			break
+		}
+		if tok == keyword("[") || tok == keyword("<<") {
+			o, _ := l.objectFrom(tok)
+			operands = append(operands, o)
+			continue
+		}
+		kw, isKeyword := tok.(keyword)
+		if !isKeyword {
+			operands = append(operands, tok)
+			continue
+		}
+
+		switch kw {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if s, ok := operands[0].(pdfString); ok && len(s) > 0 {
+					codeLen = len(s)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					table[codeOf(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := codeOf(lo), codeOf(hi)
+				if end < start || end-start > 1<<16 {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []rune(utf16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for c := start; c <= end; c++ {
+						r := append([]rune{}, base...)
+						r[len(r)-1] += rune(c - start)
+						table[c] = string(r)
+					}
+				case array:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && start+uint32(j) <= end {
+							table[start+uint32(j)] = utf16BE(s)
+						}
+					}
+				}
+			}
+		}
+		if strings.HasPrefix(string(kw), "end") || strings.HasPrefix(string(kw), "begin") {
+			operands = operands[:0]
+		}
+	}
+	return table, codeLen
+}
+
+func codeOf(s []byte) uint32 {
+	var code uint32
+	for _, b := range s {
+		code = code<<8 | uint32(b)
+	}
+	return code
+}
+
+func utf16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	if len(b) == 1 {
+		units = append(units, uint16(b[0]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// Differences'ta kullanılan glif adları. Harf ve rakamlar dışında tablolarda
+// karşılaşılan noktalama işaretleri ve Türkçe karakterler yeterlidir.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$', "percent": '%',
+	"ampersand": '&', "quotesingle": '\'', "quoteright": '’', "quoteleft": '‘', "parenleft": '(', "parenright": ')',
+	"asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-', "minus": '-', "period": '.', "slash": '/',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>', "question": '?', "at": '@',
+	"bracketleft": '[', "backslash": '\\', "bracketright": ']', "underscore": '_', "bar": '|',
+	"endash": '–', "emdash": '—', "bullet": '•', "nbspace": ' ',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"Ccedilla": 'Ç', "ccedilla": 'ç', "Gbreve": 'Ğ', "gbreve": 'ğ', "Idotaccent": 'İ', "Idot": 'İ',
+	"dotlessi": 'ı', "Odieresis": 'Ö', "odieresis": 'ö', "Scedilla": 'Ş', "scedilla": 'ş',
+	"Udieresis": 'Ü', "udieresis": 'ü', "Acircumflex": 'Â', "acircumflex": 'â',
+}
+
+func glyphRune(n string) (rune, bool) {
+	if len(n) == 1 && (n[0] >= 'a' && n[0] <= 'z' || n[0] >= 'A' && n[0] <= 'Z') {
+		return rune(n[0]), true
+	}
+	if r, ok := glyphNames[n]; ok {
+		return r, true
+	}
+	// uniXXXX ve uXXXX[XX] biçimleri
+	hex, ok := strings.CutPrefix(n, "uni")
+	if ok && len(hex) >= 4 {
+		hex = hex[:4]
+	} else if hex, ok = strings.CutPrefix(n, "u"); !ok || len(hex) < 4 {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, false
+	}
+	return rune(v), true
+}
+
+// WinAnsiEncoding: 0x20-0x7E ASCII, 0xA0-0xFF Latin-1; 0x80-0x9F aşağıdaki tablo.
+var winAnsiEncoding = func() [256]rune {
+	var enc [256]rune
+	for c := 0x20; c < 0x7F; c++ {
+		enc[c] = rune(c)
+	}
+	for c := 0xA0; c <= 0xFF; c++ {
+		enc[c] = rune(c)
+	}
+	high := []rune{
+		'€', 0, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0, 'Ž', 0,
+		0, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0, 'ž', 'Ÿ',
+	}
+	copy(enc[0x80:], high)
+	return enc
+}()
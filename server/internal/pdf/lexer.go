@@ -0,0 +1,348 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// PDF nesne türleri. Sayılar int64 ya da float64, true/false bool, null nil olarak okunur.
+type (
+	object    = interface{}
+	name      string
+	pdfString []byte
+	array     []object
+	dict      map[name]object
+	keyword   string
+	ref       struct{ num, gen int }
+	stream    struct {
+		dict dict
+		raw  []byte
+	}
+)
+
+func isSpace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+// Hem dosya gövdesini hem içerik akışlarını okuyan sözcük çözücü
+type lexer struct {
+	data []byte
+	pos  int
+	// İç içe dizi/sözlük derinliği
+	depth int
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		l.pos++
+	}
+}
+
+// Sıradaki temel sözcüğü döner: sayı, name, pdfString ya da keyword ("<<", "[" gibi
+// ayraçlar dahil). Veri bittiyse nil, false döner.
+func (l *lexer) token() (object, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		return l.name(), true
+	case c == '(':
+		return l.literalString(), true
+	case c == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return keyword("<<"), true
+		}
+		return l.hexString(), true
+	case c == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return keyword(">>"), true
+		}
+		l.pos++
+		return keyword(">"), true
+	case c == '[' || c == ']' || c == '{' || c == '}' || c == ')':
+		l.pos++
+		return keyword(string(c)), true
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if n, ok := parseNumber(word); ok {
+		return n, true
+	}
+	return keyword(word), true
+}
+
+func parseNumber(word string) (object, bool) {
+	if word == "" {
+		return nil, false
+	}
+	c := word[0]
+	if !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+		return nil, false
+	}
+	if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, true
+	}
+	// "--5" ya da "5." gibi bozuk sayılar
+	if f, err := strconv.ParseFloat(trimNumber(word), 64); err == nil {
+		return f, true
+	}
+	return nil, false
+}
+
+func trimNumber(word string) string {
+	for len(word) > 1 && (word[0] == '-' || word[0] == '+') && (word[1] == '-' || word[1] == '+') {
+		word = word[1:]
+	}
+	return word
+}
+
+func (l *lexer) name() name {
+	l.pos++ // '/'
+	var b []byte
+	for l.pos < len(l.data) && !isSpace(l.data[l.pos]) && !isDelimiter(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		l.pos++
+	}
+	return name(b)
+}
+
+func (l *lexer) literalString() pdfString {
+	l.pos++ // '('
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return b
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				// Satır devamı
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+func (l *lexer) hexString() pdfString {
+	l.pos++ // '<'
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	b := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			break
+		}
+		b = append(b, byte(v))
+	}
+	return b
+}
+
+// Tam bir nesne okur: dizi ve sözlükler iç içe, "n g R" dolaylı başvuru olarak döner.
+// İçerik akışlarında operatörler keyword olarak döner.
+func (l *lexer) object() (object, error) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, fmt.Errorf("beklenmeyen dosya sonu")
+	}
+	return l.objectFrom(tok)
+}
+
+func (l *lexer) objectFrom(tok object) (object, error) {
+	if tok == keyword("[") || tok == keyword("<<") {
+		if l.depth >= maxDepth {
+			return nil, fmt.Errorf("çok derin iç içe nesne")
+		}
+		l.depth++
+		defer func() { l.depth-- }()
+	}
+
+	switch t := tok.(type) {
+	case keyword:
+		switch t {
+		case "[":
+			arr := array{}
+			for {
+				next, ok := l.token()
+				if !ok {
+					return arr, fmt.Errorf("kapanmamış dizi")
+				}
+				if next == keyword("]") {
+					return arr, nil
+				}
+				o, err := l.objectFrom(next)
+				if err != nil {
+					return arr, err
+				}
+				arr = append(arr, o)
+			}
+		case "<<":
+			d := dict{}
+			for {
+				next, ok := l.token()
+				if !ok {
+					return d, fmt.Errorf("kapanmamış sözlük")
+				}
+				if next == keyword(">>") {
+					return d, nil
+				}
+				key, isName := next.(name)
+				if !isName {
+					// Bozuk anahtar atlanır.
+					continue
+				}
+				o, err := l.object()
+				if err != nil {
+					return d, err
+				}
+				if o == keyword(">>") {
+					return d, nil
+				}
+				d[key] = o
+			}
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return t, nil
+	case int64:
+		// "n g R" başvurusu mu?
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if g, isInt := gen.(int64); isInt {
+				if r, ok := l.token(); ok && r == keyword("R") {
+					return ref{num: int(t), gen: int(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+		return t, nil
+	}
+	return tok, nil
+}
+
+// "stream" sözcüğünden sonraki satır sonunu atlar.
+func (l *lexer) skipStreamEOL() {
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+}
+
+// Satır içi resim verisini (BI ... ID veri EI) atlar.
+func (l *lexer) skipInlineImage() {
+	idx := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if idx < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += idx + 2
+	for l.pos+2 < len(l.data) {
+		if isSpace(l.data[l.pos]) && l.data[l.pos+1] == 'E' && l.data[l.pos+2] == 'I' &&
+			(l.pos+3 >= len(l.data) || isSpace(l.data[l.pos+3]) || isDelimiter(l.data[l.pos+3])) {
+			l.pos += 3
+			return
+		}
+		l.pos++
+	}
+	l.pos = len(l.data)
+}
@@ -0,0 +1,427 @@
+package pdf
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Sayfadaki bir glif. Koordinatlar sayfanın görüntülendiği hâliyle, sol üst köşe
+// başlangıç ve y aşağı doğrudur; Y taban çizgisidir.
+type Text struct {
+	S        string
+	X, Y, W  float64
+	FontSize float64
+}
+
+// Aynı taban çizgisinde birbirine yakın gliflerden oluşan metin parçası. Tablolarda
+// her hücre satırı ayrı bir Line olur.
+type Line struct {
+	Text                     string
+	Left, Top, Right, Bottom float64
+}
+
+func (l Line) CenterX() float64 { return (l.Left + l.Right) / 2 }
+func (l Line) CenterY() float64 { return (l.Top + l.Bottom) / 2 }
+
+type Page struct {
+	// Görüntülenen boyutlar (Rotate uygulanmış)
+	Width, Height float64
+
+	doc       *Document
+	node      dict
+	resources dict
+	box       [4]float64
+	rotate    int
+}
+
+func (d *Document) newPage(node dict, inh inherited) *Page {
+	p := &Page{doc: d, node: node, resources: d.dictOf(inh.resources), box: [4]float64{0, 0, 612, 792}}
+	if mb, ok := d.resolve(inh.mediaBox).(array); ok && len(mb) == 4 {
+		for i := range mb {
+			p.box[i], _ = numOf(d.resolve(mb[i]))
+		}
+		if p.box[0] > p.box[2] {
+			p.box[0], p.box[2] = p.box[2], p.box[0]
+		}
+		if p.box[1] > p.box[3] {
+			p.box[1], p.box[3] = p.box[3], p.box[1]
+		}
+	}
+	p.rotate = ((intOf(d.resolve(inh.rotate)) % 360) + 360) % 360
+	p.Width, p.Height = p.box[2]-p.box[0], p.box[3]-p.box[1]
+	if p.rotate == 90 || p.rotate == 270 {
+		p.Width, p.Height = p.Height, p.Width
+	}
+	return p
+}
+
+// Kullanıcı uzayındaki noktayı görüntü koordinatına çevirir.
+func (p *Page) toDisplay(x, y float64) (float64, float64) {
+	x0, y0, x1, y1 := p.box[0], p.box[1], p.box[2], p.box[3]
+	switch p.rotate {
+	case 90:
+		return y - y0, x - x0
+	case 180:
+		return x1 - x, y - y0
+	case 270:
+		return y1 - y, x1 - x
+	}
+	return x - x0, y1 - y
+}
+
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// m × n (PDF satır vektörü düzeni)
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2], m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2], m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4], m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m matrix) apply(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+type textState struct {
+	ctm       matrix
+	font      *font
+	size      float64
+	charSpace float64
+	wordSpace float64
+	hscale    float64
+	leading   float64
+	rise      float64
+}
+
+type interpreter struct {
+	page  *Page
+	fonts map[*stream]*font
+	texts []Text
+}
+
+// Sayfanın gliflerini içerik akışındaki sırayla döner.
+func (p *Page) Texts() ([]Text, error) {
+	in := &interpreter{page: p}
+	content := p.contents()
+	in.run(content, p.resources, textState{ctm: identity, hscale: 1}, 0)
+	if p.doc.exhausted() {
+		return nil, ErrTooComplex
+	}
+	return in.texts, nil
+}
+
+func (p *Page) contents() []byte {
+	d := p.doc
+	var parts []object
+	switch c := d.resolve(p.node["Contents"]).(type) {
+	case *stream:
+		parts = array{c}
+	case array:
+		parts = c
+	}
+	var out []byte
+	for _, part := range parts {
+		if s, ok := d.resolve(part).(*stream); ok {
+			if data, err := d.decode(s); err == nil {
+				out = append(out, data...)
+				out = append(out, '\n')
+			}
+		}
+	}
+	return out
+}
+
+func (in *interpreter) run(content []byte, resources dict, gs textState, depth int) {
+	d := in.page.doc
+	fonts := d.dictOf(resources["Font"])
+	loaded := make(map[name]*font)
+
+	var stack []textState
+	tm, tlm := identity, identity
+	var operands []object
+	l := &lexer{data: content}
+
+	num := func(i int) float64 {
+		if i < len(operands) {
+			v, _ := numOf(operands[i])
+			return v
+		}
+		return 0
+	}
+	td := func(tx, ty float64) {
+		tlm = matrix{1, 0, 0, 1, tx, ty}.mul(tlm)
+		tm = tlm
+	}
+	show := func(s pdfString) {
+		if gs.font == nil {
+			return
+		}
+		for _, g := range gs.font.decode(s) {
+			d.ops++
+			trm := matrix{gs.size * gs.hscale, 0, 0, gs.size, 0, gs.rise}.mul(tm).mul(gs.ctm)
+			in.emit(g, trm)
+			tx := g.width*gs.size + gs.charSpace
+			if g.space {
+				tx += gs.wordSpace
+			}
+			tm = matrix{1, 0, 0, 1, tx * gs.hscale, 0}.mul(tm)
+		}
+	}
+
+	for {
+		d.ops++
+		if d.exhausted() {
+			return
+		}
+		tok, ok := l.token()
+		if !ok {
+			return
+		}
+		op, isOp := tok.(keyword)
+		if !isOp || op == "[" || op == "<<" {
+			o, err := l.objectFrom(tok)
+			if err != nil {
+				return
+			}
+			operands = append(operands, o)
+			continue
+		}
+
+		switch op {
+		case "q":
+			stack = append(stack, gs)
+		case "Q":
+			if n := len(stack); n > 0 {
+				gs, stack = stack[n-1], stack[:n-1]
+			}
+		case "cm":
+			if len(operands) >= 6 {
+				gs.ctm = matrix{num(0), num(1), num(2), num(3), num(4), num(5)}.mul(gs.ctm)
+			}
+		case "BT":
+			tm, tlm = identity, identity
+		case "Tf":
+			if len(operands) >= 2 {
+				key, _ := operands[0].(name)
+				f, ok := loaded[key]
+				if !ok {
+					f = d.loadFont(fonts[key])
+					loaded[key] = f
+				}
+				gs.font, gs.size = f, num(1)
+			}
+		case "Tc":
+			gs.charSpace = num(0)
+		case "Tw":
+			gs.wordSpace = num(0)
+		case "Tz":
+			gs.hscale = num(0) / 100
+		case "TL":
+			gs.leading = num(0)
+		case "Ts":
+			gs.rise = num(0)
+		case "Td":
+			td(num(0), num(1))
+		case "TD":
+			gs.leading = -num(1)
+			td(num(0), num(1))
+		case "Tm":
+			if len(operands) >= 6 {
+				tlm = matrix{num(0), num(1), num(2), num(3), num(4), num(5)}
+				tm = tlm
+			}
+		case "T*":
+			td(0, -gs.leading)
+		case "Tj":
+			if len(operands) > 0 {
+				s, _ := operands[len(operands)-1].(pdfString)
+				show(s)
+			}
+		case "'":
+			td(0, -gs.leading)
+			if len(operands) > 0 {
+				s, _ := operands[len(operands)-1].(pdfString)
+				show(s)
+			}
+		case "\"":
+			if len(operands) >= 3 {
+				gs.wordSpace, gs.charSpace = num(0), num(1)
+				td(0, -gs.leading)
+				s, _ := operands[2].(pdfString)
+				show(s)
+			}
+		case "TJ":
+			if len(operands) == 0 {
+				break
+			}
+			items, _ := operands[len(operands)-1].(array)
+			for _, item := range items {
+				switch v := item.(type) {
+				case pdfString:
+					show(v)
+				case int64, float64:
+					adj, _ := numOf(v)
+					tm = matrix{1, 0, 0, 1, -adj / 1000 * gs.size * gs.hscale, 0}.mul(tm)
+				}
+			}
+		case "Do":
+			if len(operands) > 0 && depth < maxDepth {
+				key, _ := operands[0].(name)
+				in.form(d.resolve(d.dictOf(resources["XObject"])[key]), resources, gs, depth)
+			}
+		case "BI":
+			l.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// Form XObject'in içeriğini kendi kaynakları ve matrisiyle çalıştırır.
+func (in *interpreter) form(o object, parent dict, gs textState, depth int) {
+	s, ok := o.(*stream)
+	if !ok || s.dict["Subtype"] != name("Form") {
+		return
+	}
+	d := in.page.doc
+	data, err := d.decode(s)
+	if err != nil {
+		return
+	}
+	if m, ok := d.resolve(s.dict["Matrix"]).(array); ok && len(m) == 6 {
+		var fm matrix
+		for i := range m {
+			fm[i], _ = numOf(d.resolve(m[i]))
+		}
+		gs.ctm = fm.mul(gs.ctm)
+	}
+	resources := d.dictOf(s.dict["Resources"])
+	if resources == nil {
+		resources = parent
+	}
+	in.run(data, resources, gs, depth+1)
+}
+
+func (in *interpreter) emit(g glyph, trm matrix) {
+	if g.text == "" {
+		return
+	}
+	p := in.page
+	x0, y0 := p.toDisplay(trm.apply(0, 0))
+	x1, y1 := p.toDisplay(trm.apply(g.width, 0))
+	xs, ys := p.toDisplay(trm.apply(0, 1))
+
+	dx, dy := x1-x0, y1-y0
+	// Dikey ya da ters yazılmış metin tablo satırlarına karışmasın.
+	if math.Abs(dy) > math.Abs(dx)+0.01 || dx < -0.01 {
+		return
+	}
+	size := math.Hypot(xs-x0, ys-y0)
+	if size <= 0 {
+		return
+	}
+	in.texts = append(in.texts, Text{S: g.text, X: x0, Y: y0, W: dx, FontSize: size})
+}
+
+// Sayfanın metnini satır parçalarına ayırır. Aynı taban çizgisindeki glifler soldan
+// sağa birleştirilir; font boyutunun bir katından geniş boşluk yeni parça başlatır.
+func (p *Page) Lines() ([]Line, error) {
+	texts, err := p.Texts()
+	if err != nil {
+		return nil, err
+	}
+	return buildLines(texts), nil
+}
+
+const (
+	// Taban çizgisi farkı bu oranın altındaysa aynı satır
+	baselineTolerance = 0.4
+	// Glifler arası boşluk bu oranı geçerse araya boşluk konur
+	wordGap = 0.15
+	// Bu oranı geçerse ayrı parça (tablo hücresi) sayılır
+	segmentGap = 1.2
+)
+
+func buildLines(texts []Text) []Line {
+	// Kalın yazı efekti için üst üste basılan glifler tekilleştirilir.
+	type key struct {
+		s    string
+		x, y int
+	}
+	seen := make(map[key]bool)
+	var glyphs []Text
+	for _, t := range texts {
+		if strings.TrimFunc(t.S, unicode.IsControl) == "" {
+			continue
+		}
+		k := key{t.S, int(math.Round(t.X * 2)), int(math.Round(t.Y * 2))}
+		if !seen[k] {
+			seen[k] = true
+			glyphs = append(glyphs, t)
+		}
+	}
+
+	sort.SliceStable(glyphs, func(i, j int) bool {
+		if glyphs[i].Y != glyphs[j].Y {
+			return glyphs[i].Y < glyphs[j].Y
+		}
+		return glyphs[i].X < glyphs[j].X
+	})
+
+	var rows [][]Text
+	for _, g := range glyphs {
+		if n := len(rows); n > 0 {
+			first := rows[n-1][0]
+			if math.Abs(g.Y-first.Y) <= math.Max(first.FontSize, g.FontSize)*baselineTolerance {
+				rows[n-1] = append(rows[n-1], g)
+				continue
+			}
+		}
+		rows = append(rows, []Text{g})
+	}
+
+	var lines []Line
+	for _, row := range rows {
+		sort.SliceStable(row, func(i, j int) bool { return row[i].X < row[j].X })
+
+		var b strings.Builder
+		var seg []Text
+		flush := func() {
+			if text := strings.Join(strings.Fields(b.String()), " "); text != "" {
+				l := Line{Text: text, Left: seg[0].X, Right: seg[0].X + seg[0].W, Top: math.Inf(1), Bottom: math.Inf(-1)}
+				for _, g := range seg {
+					l.Left = math.Min(l.Left, g.X)
+					l.Right = math.Max(l.Right, g.X+g.W)
+					// Yaklaşık yükselen/alçalan paylarıyla
+					l.Top = math.Min(l.Top, g.Y-g.FontSize*0.8)
+					l.Bottom = math.Max(l.Bottom, g.Y+g.FontSize*0.2)
+				}
+				lines = append(lines, l)
+			}
+			b.Reset()
+			seg = seg[:0]
+		}
+
+		for i, g := range row {
+			if i > 0 {
+				prev := row[i-1]
+				gap := g.X - (prev.X + prev.W)
+				size := math.Max(prev.FontSize, g.FontSize)
+				if gap > size*segmentGap {
+					flush()
+				} else if gap > size*wordGap {
+					b.WriteByte(' ')
+				}
+			}
+			b.WriteString(g.S)
+			seg = append(seg, g)
+		}
+		flush()
+	}
+	return lines
+}
@@ -0,0 +1,105 @@
+package scraper
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Duyuru bağlantısı boş gelirse Route değeri bu adrese eklenir.
+const announcementBaseURL = "https://iuc.edu.tr/tr/duyuru/"
+
+var noticeDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+	"02.01.2006",
+	"2006-01-02",
+}
+
+type rawNotice struct {
+	EID    json.RawMessage `json:"EID"`
+	Header string          `json:"Header"`
+	Date   string          `json:"Date"`
+	Link   string          `json:"Link"`
+	Route  string          `json:"Route"`
+}
+
+type noticeResponse struct {
+	Data struct {
+		Data []rawNotice `json:"Data"`
+	} `json:"Data"`
+}
+
+// Kaynağın verilen sayfasındaki duyuruları döner. Sayfalar 1'den başlar, boş sayfa sonu gösterir.
+func (s *Service) GetNotices(src models.AnnouncementSource, page int) ([]models.Announcement, error) {
+	targetURL := fmt.Sprintf("%s/api/webclient/f_getNotices?siteKey=%s&Categoryid=%d&Page=%d",
+		s.CMSURL, url.QueryEscape(src.SiteKey), src.CategoryID, page)
+
+	resp, err := http.Get(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("duyurular alınırken hata oluştu: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("duyurular alınamadı: %s", resp.Status)
+	}
+
+	var body noticeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("duyuru yanıtı çözümlenemedi: %w", err)
+	}
+
+	announcements := make([]models.Announcement, 0, len(body.Data.Data))
+	for _, n := range body.Data.Data {
+		title := CleanText(n.Header)
+		if title == "" {
+			continue
+		}
+		link := strings.TrimSpace(n.Link)
+		if link == "" && n.Route != "" {
+			link = announcementBaseURL + strings.TrimPrefix(n.Route, "/")
+		}
+		announcements = append(announcements, models.Announcement{
+			Site:        src.SiteKey,
+			Categories:  []int{src.CategoryID},
+			ExternalID:  noticeID(n),
+			Title:       title,
+			URL:         link,
+			PublishedAt: parseNoticeDate(n.Date),
+		})
+	}
+	return announcements, nil
+}
+
+// EID sayı ya da metin olarak gelebilir. Yoksa başlık ve tarihten kararlı bir id türetilir.
+func noticeID(n rawNotice) string {
+	id := string(bytes.Trim(bytes.TrimSpace(n.EID), `"`))
+	if id != "" && id != "null" {
+		return id
+	}
+	sum := sha1.Sum([]byte(n.Header + "\x00" + n.Date + "\x00" + n.Route))
+	return "h:" + hex.EncodeToString(sum[:8])
+}
+
+// Tarih çözümlenemezse sıfır zaman döner; kaydedilirken ilk görülme zamanı kullanılır.
+func parseNoticeDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range noticeDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, models.Istanbul); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
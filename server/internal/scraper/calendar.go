@@ -0,0 +1,250 @@
+package scraper
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	academicYearPattern = regexp.MustCompile(`(20\d{2})\s*[-–/]\s*(20\d{2})`)
+	numericDatePattern  = regexp.MustCompile(`(\d{1,2})[./](\d{1,2})[./](\d{4})`)
+	// "15 eylül 2025", "15-19 eylül 2025", "15 eylül" (yıl sonraki tarihten alınır)
+	textDatePattern = regexp.MustCompile(`(\d{1,2})(?:\s*[-–]\s*(\d{1,2}))?\s+(ocak|şubat|mart|nisan|mayıs|haziran|temmuz|ağustos|eylül|ekim|kasım|aralık)(?:\s+(\d{4}))?`)
+)
+
+var turkishMonths = map[string]time.Month{
+	"ocak": time.January, "şubat": time.February, "mart": time.March, "nisan": time.April,
+	"mayıs": time.May, "haziran": time.June, "temmuz": time.July, "ağustos": time.August,
+	"eylül": time.September, "ekim": time.October, "kasım": time.November, "aralık": time.December,
+}
+
+// Satır başlığındaki anahtar kelimelere göre kategori. Sıra önemlidir: "ders ekleme"
+// kayıt, "bayram tatili" sınav haftasıyla karışmasın diye özelden genele gider.
+var calendarKeywords = []struct {
+	category string
+	words    []string
+}{
+	{models.CalendarHoliday, []string{"tatil", "bayram", "resmi"}},
+	{models.CalendarAddDrop, []string{"ekle-sil", "ekle sil", "ders ekleme", "ekleme-bırakma", "ekleme ve bırakma", "ders bırakma"}},
+	{models.CalendarExam, []string{"sınav", "final", "bütünleme", "vize"}},
+	{models.CalendarRegistration, []string{"kayıt", "ders seçim", "danışman onay"}},
+	{models.CalendarClasses, []string{"derslerin başla", "derslerin sona", "derslerin bitiş", "son ders günü", "dersler başla"}},
+}
+
+// Akademik takvim sayfasını tarar. Sayfa dönem başlıkları ve "açıklama | tarih" satırları
+// içeren tablolardan oluşur; sütun sırası sabit değildir, tarih içeren hücreler tarih sayılır.
+func (s *Service) GetAcademicCalendar() ([]models.CalendarEvent, error) {
+	resp, err := http.Get(s.CalendarURL)
+	if err != nil {
+		return nil, fmt.Errorf("akademik takvim alınırken hata oluştu: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("akademik takvim alınamadı: %s", resp.Status)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	events := parseCalendar(doc)
+	if len(events) == 0 {
+		return nil, fmt.Errorf("akademik takvimde etkinlik bulunamadı")
+	}
+	return events, nil
+}
+
+func parseCalendar(doc *goquery.Document) []models.CalendarEvent {
+	year := academicYear(doc.Text())
+	term := ""
+	seen := make(map[string]bool)
+	var events []models.CalendarEvent
+
+	doc.Find("h1, h2, h3, h4, h5, table tr").Each(func(_ int, sel *goquery.Selection) {
+		if !sel.Is("tr") {
+			if t := termOf(sel.Text()); t != "" {
+				term = t
+			}
+			return
+		}
+
+		var titles []string
+		var dates []time.Time
+		sel.Find("td, th").Each(func(_ int, cell *goquery.Selection) {
+			text := strings.Join(strings.Fields(cell.Text()), " ")
+			if d := parseDates(text, year); len(d) > 0 {
+				dates = append(dates, d...)
+			} else if text != "" && !isNumber(text) {
+				titles = append(titles, text)
+			}
+		})
+
+		title := strings.Join(titles, " - ")
+		if len(dates) == 0 {
+			// Tarihsiz satır dönem başlığı olabilir.
+			if t := termOf(title); t != "" {
+				term = t
+			}
+			return
+		}
+		if title == "" {
+			return
+		}
+
+		sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+		e := models.CalendarEvent{
+			AcademicYear: year,
+			Term:         term,
+			Category:     calendarCategory(title),
+			Title:        title,
+			StartDate:    dates[0].Format("2006-01-02"),
+			EndDate:      dates[len(dates)-1].Format("2006-01-02"),
+		}
+		if e.AcademicYear == "" {
+			e.AcademicYear = academicYearOf(dates[0])
+		}
+		e.UID = calendarUID(e)
+		if !seen[e.UID] {
+			seen[e.UID] = true
+			events = append(events, e)
+		}
+	})
+	return events
+}
+
+// Sayfada geçen ilk "2025-2026" biçimli ardışık yıl çifti
+func academicYear(text string) string {
+	for _, m := range academicYearPattern.FindAllStringSubmatch(text, -1) {
+		first, _ := strconv.Atoi(m[1])
+		second, _ := strconv.Atoi(m[2])
+		if second == first+1 {
+			return m[1] + "-" + m[2]
+		}
+	}
+	return ""
+}
+
+// Akademik yıl Eylül'de başlar; Ağustos ve öncesi bir önceki yıla aittir.
+func academicYearOf(t time.Time) string {
+	y := t.Year()
+	if t.Month() < time.September {
+		y--
+	}
+	return fmt.Sprintf("%d-%d", y, y+1)
+}
+
+func termOf(text string) string {
+	lower := turkishLower(text)
+	switch {
+	case containsAny(lower, []string{"güz"}):
+		return models.TermFall
+	case containsAny(lower, []string{"bahar"}):
+		return models.TermSpring
+	case containsAny(lower, []string{"yaz okulu", "yaz dönemi", "yaz yarıyılı"}):
+		return models.TermSummer
+	}
+	return ""
+}
+
+func calendarCategory(title string) string {
+	lower := turkishLower(title)
+	for _, k := range calendarKeywords {
+		if containsAny(lower, k.words) {
+			return k.category
+		}
+	}
+	return models.CalendarOther
+}
+
+// Yıl, sayfa, dönem, başlık ve başlangıçtan türetilir; yeniden taramada değişmez.
+func calendarUID(e models.CalendarEvent) string {
+	sum := sha1.Sum([]byte(e.AcademicYear + "|" + e.Term + "|" + e.Title + "|" + e.StartDate))
+	return hex.EncodeToString(sum[:8]) + "@iuc-companion"
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(strings.TrimSuffix(s, "."))
+	return err == nil
+}
+
+type partialDate struct {
+	pos       int
+	day, year int
+	month     time.Month
+}
+
+// Metindeki tarihleri sırayla döner. Yılı yazılmamış tarihler ("15 Eylül - 3 Ekim 2025")
+// yılı sonraki tarihten alır; o da yoksa akademik yıldan tamamlanır.
+func parseDates(text, academicYear string) []time.Time {
+	var parts []partialDate
+	for _, m := range numericDatePattern.FindAllStringSubmatchIndex(text, -1) {
+		day, _ := strconv.Atoi(text[m[2]:m[3]])
+		month, _ := strconv.Atoi(text[m[4]:m[5]])
+		year, _ := strconv.Atoi(text[m[6]:m[7]])
+		parts = append(parts, partialDate{pos: m[0], day: day, month: time.Month(month), year: year})
+	}
+
+	lower := turkishLower(text)
+	for _, m := range textDatePattern.FindAllStringSubmatchIndex(lower, -1) {
+		month := turkishMonths[lower[m[6]:m[7]]]
+		year := 0
+		if m[8] >= 0 {
+			year, _ = strconv.Atoi(lower[m[8]:m[9]])
+		}
+		day, _ := strconv.Atoi(lower[m[2]:m[3]])
+		parts = append(parts, partialDate{pos: m[0], day: day, month: month, year: year})
+		if m[4] >= 0 {
+			end, _ := strconv.Atoi(lower[m[4]:m[5]])
+			parts = append(parts, partialDate{pos: m[4], day: end, month: month, year: year})
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+	sort.Slice(parts, func(i, j int) bool { return parts[i].pos < parts[j].pos })
+
+	// Yıl sondan başa taşınır; ay sonraki tarihten büyükse yıl dönümü geçilmiştir.
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i].year == 0 && parts[i+1].year != 0 {
+			parts[i].year = parts[i+1].year
+			if parts[i].month > parts[i+1].month {
+				parts[i].year--
+			}
+		}
+	}
+
+	var dates []time.Time
+	for _, p := range parts {
+		if p.year == 0 {
+			p.year = yearInAcademicYear(academicYear, p.month)
+		}
+		t := time.Date(p.year, p.month, p.day, 0, 0, 0, 0, time.UTC)
+		if p.year == 0 || t.Day() != p.day || t.Month() != p.month {
+			continue
+		}
+		dates = append(dates, t)
+	}
+	return dates
+}
+
+func yearInAcademicYear(academicYear string, month time.Month) int {
+	first, err := strconv.Atoi(strings.SplitN(academicYear, "-", 2)[0])
+	if err != nil {
+		return 0
+	}
+	if month < time.September {
+		return first + 1
+	}
+	return first
+}
@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseDates(t *testing.T) {
+	tests := []struct {
+		text         string
+		academicYear string
+		want         []string
+	}{
+		{"15 Eylül 2025", "", []string{"2025-09-15"}},
+		{"15-19 Eylül 2025", "", []string{"2025-09-15", "2025-09-19"}},
+		{"15 – 19 EYLÜL 2025 Pazartesi", "", []string{"2025-09-15", "2025-09-19"}},
+		{"15.09.2025 - 19.09.2025", "", []string{"2025-09-15", "2025-09-19"}},
+		{"15/9/2025", "", []string{"2025-09-15"}},
+		// Yılı yazılmamış tarih yılı sonraki tarihten alır; ay büyükse yıl dönümü geçilmiştir.
+		{"15 Eylül - 3 Ekim 2025", "", []string{"2025-09-15", "2025-10-03"}},
+		{"29 Aralık - 2 Ocak 2026", "", []string{"2025-12-29", "2026-01-02"}},
+		// Yılsız aralıklar akademik yıldan tamamlanır.
+		{"22-26 Aralık", "2025-2026", []string{"2025-12-22", "2025-12-26"}},
+		{"16-20 Şubat", "2025-2026", []string{"2026-02-16", "2026-02-20"}},
+		{"16-20 Şubat", "", nil},
+		// Geçersiz günler atlanır.
+		{"30 Şubat 2026", "", nil},
+		{"Ders kayıtları", "2025-2026", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, d := range parseDates(tt.text, tt.academicYear) {
+			got = append(got, d.Format(time.DateOnly))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseDates(%q, %q) = %v, want %v", tt.text, tt.academicYear, got, tt.want)
+		}
+	}
+}
+
+func TestParseCalendar(t *testing.T) {
+	const page = `
+		<h2>2025-2026 Eğitim-Öğretim Yılı Akademik Takvimi</h2>
+		<h3>Güz Yarıyılı</h3>
+		<table>
+			<tr><th>Açıklama</th><th>Tarih</th></tr>
+			<tr><td>1</td><td>Ders Kayıtları</td><td>15-19 Eylül 2025</td></tr>
+			<tr><td>2</td><td>Derslerin Başlaması</td><td>22 Eylül 2025</td></tr>
+			<tr><td>3</td><td>Ders Kayıtları</td><td>15-19 Eylül 2025</td></tr>
+			<tr><td>4</td><td>Ara Sınavlar</td><td>10-14 Kasım</td></tr>
+			<tr><td>Bahar Yarıyılı</td><td></td></tr>
+			<tr><td>Ekle-Sil Haftası</td><td>23 Şubat</td><td>27 Şubat 2026</td></tr>
+			<tr><td>Ramazan Bayramı Tatili</td><td>19.03.2026 - 22.03.2026</td></tr>
+		</table>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type event struct{ term, category, title, start, end string }
+	want := []event{
+		{models.TermFall, models.CalendarRegistration, "Ders Kayıtları", "2025-09-15", "2025-09-19"},
+		{models.TermFall, models.CalendarClasses, "Derslerin Başlaması", "2025-09-22", "2025-09-22"},
+		{models.TermFall, models.CalendarExam, "Ara Sınavlar", "2025-11-10", "2025-11-14"},
+		{models.TermSpring, models.CalendarAddDrop, "Ekle-Sil Haftası", "2026-02-23", "2026-02-27"},
+		{models.TermSpring, models.CalendarHoliday, "Ramazan Bayramı Tatili", "2026-03-19", "2026-03-22"},
+	}
+
+	var got []event
+	uids := make(map[string]bool)
+	for _, e := range parseCalendar(doc) {
+		if e.AcademicYear != "2025-2026" {
+			t.Errorf("%s: academic year = %q", e.Title, e.AcademicYear)
+		}
+		uids[e.UID] = true
+		got = append(got, event{e.Term, e.Category, e.Title, e.StartDate, e.EndDate})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events:\n got %v\nwant %v", got, want)
+	}
+	if len(uids) != len(want) {
+		t.Errorf("uids = %d, want %d distinct", len(uids), len(want))
+	}
+}
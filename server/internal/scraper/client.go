@@ -1,9 +1,26 @@
 package scraper
 
 import (
+	"regexp"
 	"strings"
 )
 
+var courseCodePattern = regexp.MustCompile(`([A-ZÇĞİÖŞÜ]{2,5})\s?(\d{3,4})`)
+
 func CleanText(s string) string {
 	return strings.TrimSpace(strings.ReplaceAll(s, "\n", " "))
 }
+
+// Metin içinde geçen ders kodlarını ayıklar ("MAT101 Matematik I, FIZ 101" -> [MAT101 FIZ101]).
+func ParseCourseCodes(s string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, m := range courseCodePattern.FindAllStringSubmatch(s, -1) {
+		code := m[1] + m[2]
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
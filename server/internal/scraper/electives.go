@@ -0,0 +1,158 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/models"
+)
+
+// "Dersleri", "Grubu" gibi havuz başlığına eklenen kelimeler. Yer tutucu satır adıyla
+// başlığı eşleştirebilmek için atılır.
+var electiveNoiseWords = regexp.MustCompile(`\b(dersleri|dersler|ders|grubu|havuzu)\b`)
+
+func turkishLower(s string) string {
+	return strings.ToLowerSpecial(unicode.TurkishCase, s)
+}
+
+// Başlık ya da ders adı bir seçmeli havuzunu mu işaret ediyor?
+func isElectiveLabel(s string) bool {
+	return strings.Contains(turkishLower(s), "seçmeli")
+}
+
+// Havuz adlarını karşılaştırılabilir hale getirir ("SEÇMELİ I DERSLERİ" -> "seçmeli i").
+func electiveKey(s string) string {
+	s = turkishLower(CleanText(s))
+	s = strings.Trim(s, " :-")
+	s = electiveNoiseWords.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Linki olmayan "Seçmeli I" satırları gerçek ders değil, havuzdan seçilecek dersin yer tutucusudur.
+func isElectivePlaceholder(c models.Course) bool {
+	return c.LinkID == "" && isElectiveLabel(c.Name)
+}
+
+// Müfredat sayfası taranırken seçmeli havuzlarını toplar.
+type electiveCollector struct {
+	groups []*models.ElectiveGroup
+	byKey  map[string]*models.ElectiveGroup
+
+	// Havuz başlığı olmadan, yer tutucunun bulunduğu dönem tablosundaki seçmeli dersler
+	semesterElectives map[string][]string
+}
+
+func newElectiveCollector() *electiveCollector {
+	return &electiveCollector{
+		byKey:             make(map[string]*models.ElectiveGroup),
+		semesterElectives: make(map[string][]string),
+	}
+}
+
+func (ec *electiveCollector) group(name, semester string) *models.ElectiveGroup {
+	key := electiveKey(name) + "|" + semester
+	if g, ok := ec.byKey[key]; ok {
+		return g
+	}
+
+	g := &models.ElectiveGroup{Name: CleanText(name), Semester: semester}
+	ec.byKey[key] = g
+	ec.groups = append(ec.groups, g)
+	return g
+}
+
+// Dönem tablosundaki yer tutucu satır: havuzdan kaç ders / kaç AKTS alınacağını belirtir.
+func (ec *electiveCollector) addPlaceholder(c models.Course) {
+	g := ec.group(c.Name, c.Semester)
+	g.RequiredCount++
+	g.RequiredECTS += c.ECTS
+}
+
+// Havuz başlığı altındaki ders
+func (ec *electiveCollector) addMember(poolName string, c models.Course) {
+	ec.addCode(ec.group(poolName, c.Semester), c.Code)
+}
+
+// Dönem tablosundaki zorunlu olmayan ders. Havuzu sonradan belirlenir.
+func (ec *electiveCollector) addSemesterElective(c models.Course) {
+	ec.semesterElectives[c.Semester] = append(ec.semesterElectives[c.Semester], c.Code)
+}
+
+func (ec *electiveCollector) addCode(g *models.ElectiveGroup, code string) {
+	for _, existing := range g.CourseCodes {
+		if existing == code {
+			return
+		}
+	}
+	g.CourseCodes = append(g.CourseCodes, code)
+}
+
+func (ec *electiveCollector) result() []models.ElectiveGroup {
+	// Havuz başlığı dönemsizdir, aynı adlı yer tutucuyla birleştirilir. Yer tutucusu olmayan
+	// havuz dönemsiz kalır.
+	merged := make(map[*models.ElectiveGroup]bool)
+	for _, g := range ec.groups {
+		if g.Semester != "" || len(g.CourseCodes) == 0 {
+			continue
+		}
+		for _, target := range ec.groups {
+			if target.Semester != "" && electiveKey(target.Name) == electiveKey(g.Name) {
+				for _, code := range g.CourseCodes {
+					ec.addCode(target, code)
+				}
+				merged[g] = true
+			}
+		}
+	}
+
+	// Dönemde tek bir havuz varsa o dönemin seçmeli dersleri o havuza aittir.
+	perSemester := make(map[string][]*models.ElectiveGroup)
+	for _, g := range ec.groups {
+		if g.Semester != "" {
+			perSemester[g.Semester] = append(perSemester[g.Semester], g)
+		}
+	}
+	for semester, codes := range ec.semesterElectives {
+		if pools := perSemester[semester]; len(pools) == 1 && len(pools[0].CourseCodes) == 0 {
+			for _, code := range codes {
+				ec.addCode(pools[0], code)
+			}
+		}
+	}
+
+	var groups []models.ElectiveGroup
+	for _, g := range ec.groups {
+		if merged[g] || (g.Semester == "" && len(g.CourseCodes) == 0) {
+			continue
+		}
+		groups = append(groups, *g)
+	}
+	return groups
+}
+
+// Havuz başlığı altındaki derslere, havuz tek bir dönemde yer alıyorsa o dönemi verir.
+// Birden fazla dönemde seçilebilen havuzun dersleri dönemsiz kalır.
+func placePoolMembers(courses []models.Course, groups []models.ElectiveGroup) {
+	semesters := make(map[string]map[string]bool)
+	for _, g := range groups {
+		if g.Semester == "" {
+			continue
+		}
+		for _, code := range g.CourseCodes {
+			if semesters[code] == nil {
+				semesters[code] = make(map[string]bool)
+			}
+			semesters[code][g.Semester] = true
+		}
+	}
+
+	for i, c := range courses {
+		if c.Semester != "" || len(semesters[c.Code]) != 1 {
+			continue
+		}
+		for semester := range semesters[c.Code] {
+			courses[i].Semester = semester
+		}
+	}
+}
@@ -15,11 +15,20 @@ import (
 // Service: Scraping metodlarını barındırıyor.
 type Service struct {
 	BaseURL string
+	// Duyuruların alındığı CMS servisi
+	CMSURL string
+	// Akademik takvim sayfası
+	CalendarURL string
+	// Fakülte ve bölüm sitelerine bağlantı veren sayfa; ders programı kaynakları buradan bulunur.
+	UnitsURL string
 }
 
 func NewService() *Service {
 	return &Service{
-		BaseURL: "https://ebs.iuc.edu.tr",
+		BaseURL:     "https://ebs.iuc.edu.tr",
+		CMSURL:      "https://service-cms.iuc.edu.tr",
+		CalendarURL: "https://ogrenciisleri.iuc.edu.tr/tr/_/akademik-takvim",
+		UnitsURL:    "https://iuc.edu.tr/tr/",
 	}
 }
 
@@ -77,28 +86,40 @@ func (s *Service) GetStructure() ([]models.Faculty, []models.Department, error)
 	return faculties, departments, nil
 }
 
-func (s *Service) GetCourses(deptGUID string, year int) ([]models.Course, error) {
+// Bölüm müfredatındaki dersleri ve seçmeli havuzlarını döner.
+func (s *Service) GetCourses(deptGUID string, year int) ([]models.Course, []models.ElectiveGroup, error) {
 
 	targetURL := fmt.Sprintf("%s/home/dersprogram/?id=%s&yil=%d", s.BaseURL, url.QueryEscape(deptGUID), year)
 
 	resp, err := http.Get(targetURL)
 	if err != nil {
-		return nil, fmt.Errorf("dersler alınırken hata oluştu: %w", err)
+		return nil, nil, fmt.Errorf("dersler alınırken hata oluştu: %w", err)
 	}
 	defer resp.Body.Close()
 
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("ders html'i parse edilemedi: %w", err)
+		return nil, nil, fmt.Errorf("ders html'i parse edilemedi: %w", err)
 	}
 
 	var courses []models.Course
 	var currentSemester string
+	var currentPool string
+	electives := newElectiveCollector()
 
 	doc.Find(".panel-body").Children().Each(func(i int, s *goquery.Selection) {
 		if s.Is("h4") {
-			currentSemester = CleanText(s.Text())
-		} else if s.Is("table") && currentSemester != "" {
+			heading := CleanText(s.Text())
+			// Seçmeli havuz başlıkları dönem başlığı değildir, altındaki tablo o havuzun dersleridir.
+			// Havuz dersleri önceki dönem başlığına değil, havuzun yer tutucusunun dönemine aittir.
+			if isElectiveLabel(heading) {
+				currentPool = heading
+				currentSemester = ""
+			} else {
+				currentSemester = heading
+				currentPool = ""
+			}
+		} else if s.Is("table") && (currentSemester != "" || currentPool != "") {
 			s.Find("tbody tr").Each(func(j int, tr *goquery.Selection) {
 				c := models.Course{Semester: currentSemester}
 
@@ -129,19 +150,132 @@ func (s *Service) GetCourses(deptGUID string, year int) ([]models.Course, error)
 						}
 					}
 				})
-				if c.Code != "" {
-					courses = append(courses, c)
+
+				if isElectivePlaceholder(c) {
+					electives.addPlaceholder(c)
+					return
 				}
+				if c.Code == "" {
+					return
+				}
+
+				if currentPool != "" {
+					electives.addMember(currentPool, c)
+				} else if !c.IsMandatory {
+					electives.addSemesterElective(c)
+				}
+				courses = append(courses, c)
 			})
 		}
 	})
 
-	return courses, nil
+	groups := electives.result()
+	placePoolMembers(courses, groups)
+	return courses, groups, nil
+}
+
+// EBS sayfaları lang parametresiyle İngilizce sunulur; ders kodları ve sayfa yapısı aynıdır.
+const englishParam = "&lang=en"
+
+// İzlence sayfasındaki etiket ve başlıklar; dile göre değişir.
+type syllabusLabels struct {
+	form         string
+	name         []string
+	code         []string
+	language     []string
+	instructor   []string
+	prerequisite []string
+	aim          string
+	content      string
+	resources    string
+	outcomes     string
+}
+
+var (
+	turkishLabels = syllabusLabels{
+		form:         "İzlence Formu",
+		name:         []string{"Ders Adı"},
+		code:         []string{"Kod"},
+		language:     []string{"Ders Dili"},
+		instructor:   []string{"Dersi Veren"},
+		prerequisite: []string{"Ön Koşul", "Önkoşul"},
+		aim:          "Dersin Amacı",
+		content:      "İçerik",
+		resources:    "Kaynaklar",
+		outcomes:     "Dersin Öğrenme Çıktıları",
+	}
+	englishLabels = syllabusLabels{
+		form:         "Syllabus",
+		name:         []string{"Course Name"},
+		code:         []string{"Code"},
+		language:     []string{"Language"},
+		instructor:   []string{"Instructor", "Lecturer"},
+		prerequisite: []string{"Prerequisite"},
+		aim:          "Aim",
+		content:      "Content",
+		resources:    "Resources",
+		outcomes:     "Learning Outcomes",
+	}
+)
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Service) GetCourseDetail(id, bid string) (*models.CourseDetail, error) {
 	targetURL := fmt.Sprintf("%s/home/izlence/?id=%s&bid=%s", s.BaseURL, url.QueryEscape(id), url.QueryEscape(bid))
+	return s.fetchCourseDetail(targetURL, turkishLabels)
+}
+
+// İzlencenin İngilizce sürümünü döner. Sonuçta yalnızca Türkçe alanlar doludur;
+// çağıran bunları *En alanlarına taşır (bkz. MergeEnglish).
+func (s *Service) GetCourseDetailEn(id, bid string) (*models.CourseDetail, error) {
+	targetURL := fmt.Sprintf("%s/home/izlence/?id=%s&bid=%s%s", s.BaseURL, url.QueryEscape(id), url.QueryEscape(bid), englishParam)
+	return s.fetchCourseDetail(targetURL, englishLabels)
+}
+
+// İngilizce izlencedeki metinleri Türkçe detayın *En alanlarına yazar.
+func MergeEnglish(detail, en *models.CourseDetail) {
+	detail.BaseInfo.NameEn = en.BaseInfo.Name
+	detail.AimEn = en.Aim
+	detail.ContentEn = en.Content
+	detail.ResourcesEn = en.Resources
+	detail.OutcomesEn = en.Outcomes
+}
+
+// Bölüm müfredatındaki derslerin İngilizce adlarını ders koduna göre döner.
+func (s *Service) GetCourseNamesEn(deptGUID string, year int) (map[string]string, error) {
+	targetURL := fmt.Sprintf("%s/home/dersprogram/?id=%s&yil=%d%s", s.BaseURL, url.QueryEscape(deptGUID), year, englishParam)
+
+	resp, err := http.Get(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("İngilizce ders listesi alınırken hata oluştu: %w", err)
+	}
+	defer resp.Body.Close()
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("ders html'i parse edilemedi: %w", err)
+	}
+
+	names := make(map[string]string)
+	doc.Find(".panel-body table tbody tr").Each(func(i int, tr *goquery.Selection) {
+		cells := tr.Find("td")
+		code := CleanText(cells.Eq(0).Text())
+		name := CleanText(cells.Eq(1).Text())
+		if code != "" && name != "" {
+			names[code] = name
+		}
+	})
+	return names, nil
+}
 
+func (s *Service) fetchCourseDetail(targetURL string, labels syllabusLabels) (*models.CourseDetail, error) {
 	resp, err := http.Get(targetURL)
 	if err != nil {
 		return nil, fmt.Errorf("ders detayı alınırken hata oluştu: %w", err)
@@ -155,19 +289,21 @@ func (s *Service) GetCourseDetail(id, bid string) (*models.CourseDetail, error)
 
 	detail := &models.CourseDetail{}
 
-	doc.Find(".panel-heading:contains('İzlence Formu')").Parent().Find("table tr").Each(func(i int, tr *goquery.Selection) {
+	doc.Find(".panel-heading:contains('" + labels.form + "')").Parent().Find("table tr").Each(func(i int, tr *goquery.Selection) {
 		tr.Find("td").Each(func(j int, td *goquery.Selection) {
 			label := CleanText(td.Text())
 			val := CleanText(td.Next().Text())
 
-			if strings.Contains(label, "Ders Adı") {
+			if containsAny(label, labels.name) {
 				detail.BaseInfo.Name = val
-			} else if strings.Contains(label, "Kod") {
+			} else if containsAny(label, labels.code) {
 				detail.BaseInfo.Code = val
-			} else if strings.Contains(label, "Ders Dili") {
+			} else if containsAny(label, labels.language) {
 				detail.Language = val
-			} else if strings.Contains(label, "Dersi Veren") {
+			} else if containsAny(label, labels.instructor) {
 				detail.Instructor = val
+			} else if containsAny(label, labels.prerequisite) {
+				detail.Prerequisites = ParseCourseCodes(val)
 			}
 		})
 	})
@@ -176,17 +312,17 @@ func (s *Service) GetCourseDetail(id, bid string) (*models.CourseDetail, error)
 		title := CleanText(s.Text())
 		content := CleanText(s.ParentsFiltered(".panel-heading").Next().Text())
 
-		switch title {
-		case "Dersin Amacı":
+		switch {
+		case title == labels.aim || strings.HasPrefix(title, labels.aim+" "):
 			detail.Aim = content
-		case "İçerik":
+		case title == labels.content:
 			detail.Content = content
-		case "Kaynaklar":
+		case title == labels.resources || strings.HasPrefix(title, labels.resources+"/"):
 			detail.Resources = content
 		}
 	})
 
-	doc.Find(".panel-heading:contains('Dersin Öğrenme Çıktıları')").Parent().Find("table tbody tr").Each(func(i int, tr *goquery.Selection) {
+	doc.Find(".panel-heading:contains('" + labels.outcomes + "')").Parent().Find("table tbody tr").Each(func(i int, tr *goquery.Selection) {
 		outcome := CleanText(tr.Find("td").Eq(1).Text())
 		if outcome != "" {
 			detail.Outcomes = append(detail.Outcomes, outcome)
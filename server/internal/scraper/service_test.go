@@ -0,0 +1,79 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const curriculumPage = `<html><body><div class="panel-body">
+<h4>1. Yarıyıl</h4>
+<table><tbody>
+<tr><td>MAT101</td><td><a href="/home/izlence/?id=1&bid=9">Matematik I</a></td><td>4</td><td>6</td><td>Z</td><td>3/2/0</td></tr>
+<tr><td></td><td>Seçmeli I</td><td>2</td><td>3</td><td>S</td><td>2/0/0</td></tr>
+</tbody></table>
+<h4>2. Yarıyıl</h4>
+<table><tbody>
+<tr><td>MAT102</td><td><a href="/home/izlence/?id=2&bid=9">Matematik II</a></td><td>4</td><td>6</td><td>Z</td><td>3/2/0</td></tr>
+</tbody></table>
+<h4>Seçmeli I Dersleri</h4>
+<table><tbody>
+<tr><td>SEC101</td><td><a href="/home/izlence/?id=3&bid=9">Felsefe</a></td><td>2</td><td>3</td><td>S</td><td>2/0/0</td></tr>
+<tr><td>SEC102</td><td><a href="/home/izlence/?id=4&bid=9">Sanat</a></td><td>2</td><td>3</td><td>S</td><td>2/0/0</td></tr>
+</tbody></table>
+<h4>Serbest Seçmeli</h4>
+<table><tbody>
+<tr><td>SRB101</td><td><a href="/home/izlence/?id=5&bid=9">Müzik</a></td><td>2</td><td>3</td><td>S</td><td>2/0/0</td></tr>
+</tbody></table>
+</div></body></html>`
+
+func TestGetCoursesElectivePools(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(curriculumPage))
+	}))
+	defer srv.Close()
+
+	s := NewService()
+	s.BaseURL = srv.URL
+	courses, groups, err := s.GetCourses("d1", 2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	semesters := make(map[string]string)
+	for _, c := range courses {
+		semesters[c.Code] = c.Semester
+	}
+	want := map[string]string{
+		"MAT101": "1. Yarıyıl",
+		"MAT102": "2. Yarıyıl",
+		// Havuz başlığı 2. yarıyıldan sonra gelse de yer tutucusu 1. yarıyıldadır.
+		"SEC101": "1. Yarıyıl",
+		"SEC102": "1. Yarıyıl",
+		// Yer tutucusu olmayan havuzun dersi dönemsiz kalır.
+		"SRB101": "",
+	}
+	if len(semesters) != len(want) {
+		t.Fatalf("courses = %v, want %v", semesters, want)
+	}
+	for code, semester := range want {
+		if semesters[code] != semester {
+			t.Errorf("%s semester = %q, want %q", code, semesters[code], semester)
+		}
+	}
+
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", groups)
+	}
+	pool := groups[0]
+	if pool.Name != "Seçmeli I" || pool.Semester != "1. Yarıyıl" || pool.RequiredCount != 1 || pool.RequiredECTS != 3 {
+		t.Errorf("pool = %+v", pool)
+	}
+	if len(pool.CourseCodes) != 2 || pool.CourseCodes[0] != "SEC101" || pool.CourseCodes[1] != "SEC102" {
+		t.Errorf("pool courses = %v", pool.CourseCodes)
+	}
+	free := groups[1]
+	if free.Name != "Serbest Seçmeli" || free.Semester != "" || len(free.CourseCodes) != 1 {
+		t.Errorf("free pool = %+v", free)
+	}
+}
@@ -0,0 +1,171 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// İndirilecek en büyük ders programı PDF'i
+const MaxTimetablePDFSize = 20 << 20
+
+// Bağlantıları taranan HTML sayfalarının okunacak en büyük boyutu
+const maxLinkPageSize = 4 << 20
+
+// Ders programı bağlantısının metninde ya da adresinde aranan ifadeler
+var timetableKeywords = []string{"ders program", "ders_program", "ders-program", "dersprogram"}
+
+// Bölüm sayfasındaki ders programı PDF'inin adresini bulur. Adres zaten PDF'se olduğu gibi
+// döner. Metni ya da adresi "ders programı" içeren ilk PDF bağlantısı seçilir; böyle bir
+// bağlantı yoksa sayfadaki başka bir PDF tahmin edilmez, hata döner.
+func (s *Service) FindTimetablePDF(pageURL string) (string, error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	if isPDFLink(base.Path) {
+		return pageURL, nil
+	}
+
+	links, isPDF, err := fetchLinks(pageURL)
+	if err != nil {
+		return "", fmt.Errorf("ders programı sayfası alınırken hata oluştu: %w", err)
+	}
+	if isPDF {
+		return pageURL, nil
+	}
+	for _, l := range links {
+		if isPDFLink(l.url.Path) && isTimetableLink(l) {
+			return l.url.String(), nil
+		}
+	}
+	return "", fmt.Errorf("sayfada ders programı PDF'i bulunamadı")
+}
+
+// Bölüm sitesinde ders programına giden bağlantıyı (sayfa ya da PDF) bulur.
+func (s *Service) FindTimetablePage(siteURL string) (string, error) {
+	links, isPDF, err := fetchLinks(siteURL)
+	if err != nil {
+		return "", fmt.Errorf("bölüm sitesi alınırken hata oluştu: %w", err)
+	}
+	if isPDF {
+		return "", fmt.Errorf("bölüm sitesi bir PDF")
+	}
+	for _, l := range links {
+		if isTimetableLink(l) {
+			return l.url.String(), nil
+		}
+	}
+	return "", fmt.Errorf("bölüm sitesinde ders programı bağlantısı bulunamadı")
+}
+
+// Birim listesi sayfasındaki bağlantılardan verilen birimlerin (fakülte ya da bölüm)
+// sitelerini bulur. Bağlantı metni birim adıyla "Bölümü", "Fakültesi" gibi ekler dışında
+// aynı olmalıdır. Sonuç birim adıyla anahtarlanır; bulunamayan birimler sonuçta yer almaz.
+func (s *Service) FindUnitSites(indexURL string, names []string) (map[string]string, error) {
+	links, _, err := fetchLinks(indexURL)
+	if err != nil {
+		return nil, fmt.Errorf("birim listesi alınırken hata oluştu: %w", err)
+	}
+	byKey := make(map[string]string)
+	for _, name := range names {
+		if key := unitKey(name); key != "" {
+			byKey[key] = name
+		}
+	}
+	sites := make(map[string]string)
+	for _, l := range links {
+		name, ok := byKey[unitKey(l.text)]
+		if !ok || isPDFLink(l.url.Path) {
+			continue
+		}
+		if _, seen := sites[name]; !seen {
+			sites[name] = l.url.String()
+		}
+	}
+	return sites, nil
+}
+
+type pageLink struct {
+	text string
+	url  *url.URL
+}
+
+func isTimetableLink(l pageLink) bool {
+	return containsAny(turkishLower(l.text+" "+l.url.Path), timetableKeywords)
+}
+
+// Birim adlarının bağlantı metinlerinde atlanabilen son kelimeleri
+var unitSuffixes = map[string]bool{"bölümü": true, "bölüm": true, "fakültesi": true, "programı": true, "anabilim": true, "dalı": true}
+
+// Birim adını karşılaştırılabilir hale getirir ("Bilgisayar Mühendisliği Bölümü" -> "bilgisayar mühendisliği").
+func unitKey(s string) string {
+	fields := strings.Fields(turkishLower(CleanText(s)))
+	for len(fields) > 0 && unitSuffixes[fields[len(fields)-1]] {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " ")
+}
+
+// Sayfadaki http(s) bağlantılarını göreli adresleri çözerek döner. Yanıt PDF'se
+// bağlantı okunmaz, isPDF true döner.
+func fetchLinks(pageURL string) (links []pageLink, isPDF bool, err error) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := http.Get(pageURL)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("sayfa alınamadı: %s", resp.Status)
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/pdf") {
+		return nil, true, nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(io.LimitReader(resp.Body, maxLinkPageSize))
+	if err != nil {
+		return nil, false, err
+	}
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		link, err := base.Parse(strings.TrimSpace(href))
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") {
+			return
+		}
+		links = append(links, pageLink{text: CleanText(a.Text()), url: link})
+	})
+	return links, false, nil
+}
+
+func isPDFLink(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".pdf")
+}
+
+// PDF'i indirir. MaxTimetablePDFSize'dan büyük dosyalar reddedilir.
+func (s *Service) DownloadPDF(pdfURL string) ([]byte, error) {
+	resp, err := http.Get(pdfURL)
+	if err != nil {
+		return nil, fmt.Errorf("PDF indirilirken hata oluştu: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("PDF indirilemedi: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxTimetablePDFSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxTimetablePDFSize {
+		return nil, fmt.Errorf("PDF çok büyük (en fazla %d MB)", MaxTimetablePDFSize>>20)
+	}
+	return data, nil
+}
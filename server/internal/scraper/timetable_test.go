@@ -0,0 +1,84 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func linkServer(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFindTimetablePDF(t *testing.T) {
+	srv := linkServer(t, map[string]string{
+		"/program": `<a href="/yonetmelik.pdf">Yönetmelik</a> <a href="files/2025-guz.pdf">2025 Güz Ders Programı</a>`,
+		"/diger":   `<a href="/yonetmelik.pdf">Yönetmelik</a> <a href="/staj.pdf">Staj Formu</a>`,
+	})
+	s := &Service{}
+
+	got, err := s.FindTimetablePDF(srv.URL + "/program")
+	if err != nil || got != srv.URL+"/files/2025-guz.pdf" {
+		t.Errorf("FindTimetablePDF = %q, %v", got, err)
+	}
+	// Ders programı bağlantısı yoksa başka bir PDF seçilmemeli.
+	if got, err := s.FindTimetablePDF(srv.URL + "/diger"); err == nil {
+		t.Errorf("FindTimetablePDF = %q, want error", got)
+	}
+	if got, _ := s.FindTimetablePDF("https://example.com/ders.pdf"); got != "https://example.com/ders.pdf" {
+		t.Errorf("direct PDF = %q", got)
+	}
+}
+
+func TestFindUnitSites(t *testing.T) {
+	srv := linkServer(t, map[string]string{
+		"/": `<a href="/muh">Mühendislik Fakültesi</a>
+			<a href="https://bm.example.com/">BİLGİSAYAR MÜHENDİSLİĞİ BÖLÜMÜ</a>
+			<a href="/kimya.pdf">Kimya</a>
+			<a href="mailto:info@example.com">Fizik</a>`,
+	})
+	s := &Service{}
+
+	sites, err := s.FindUnitSites(srv.URL+"/", []string{"Mühendislik", "Bilgisayar Mühendisliği", "Kimya", "Fizik"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Mühendislik":             srv.URL + "/muh",
+		"Bilgisayar Mühendisliği": "https://bm.example.com/",
+	}
+	if len(sites) != len(want) {
+		t.Errorf("sites = %v, want %v", sites, want)
+	}
+	for name, u := range want {
+		if sites[name] != u {
+			t.Errorf("%s = %q, want %q", name, sites[name], u)
+		}
+	}
+}
+
+func TestFindTimetablePage(t *testing.T) {
+	srv := linkServer(t, map[string]string{
+		"/bm":  `<a href="/bm/duyurular">Duyurular</a> <a href="/bm/ders-programi">Ders Programları</a>`,
+		"/fiz": `<a href="/fiz/duyurular">Duyurular</a>`,
+	})
+	s := &Service{}
+
+	if got, err := s.FindTimetablePage(srv.URL + "/bm"); err != nil || got != srv.URL+"/bm/ders-programi" {
+		t.Errorf("FindTimetablePage = %q, %v", got, err)
+	}
+	if got, err := s.FindTimetablePage(srv.URL + "/fiz"); err == nil {
+		t.Errorf("FindTimetablePage = %q, want error", got)
+	}
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Duyuruyu kaydeder, daha önce görülmüşse başlık ve bağlantısını günceller ve kategorisini
+// ekler. Duyuru ilk kez görülüyorsa true döner. a.ID ve a.FirstSeenAt doldurulur.
+func SaveAnnouncement(db DB, a *models.Announcement) (bool, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	if a.PublishedAt.IsZero() {
+		a.PublishedAt = now
+	}
+
+	res, err := db.Exec(`
+		INSERT INTO announcements (site_key, external_id, title, url, published_at, first_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(site_key, external_id) DO NOTHING`,
+		a.Site, a.ExternalID, a.Title, a.URL, a.PublishedAt.UTC(), now,
+	)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if inserted == 0 {
+		if _, err := db.Exec(`
+			UPDATE announcements SET title = ?, url = ?
+			WHERE site_key = ? AND external_id = ? AND (title IS NOT ? OR url IS NOT ?)`,
+			a.Title, a.URL, a.Site, a.ExternalID, a.Title, a.URL,
+		); err != nil {
+			return false, err
+		}
+	}
+
+	if err := db.QueryRow(`
+		SELECT announcement_id, published_at, first_seen_at FROM announcements
+		WHERE site_key = ? AND external_id = ?`, a.Site, a.ExternalID,
+	).Scan(&a.ID, &a.PublishedAt, &a.FirstSeenAt); err != nil {
+		return false, err
+	}
+
+	for _, category := range a.Categories {
+		if _, err := db.Exec(
+			"INSERT OR IGNORE INTO announcement_categories (announcement_id, category_id) VALUES (?, ?)",
+			a.ID, category,
+		); err != nil {
+			return false, err
+		}
+	}
+	return inserted > 0, nil
+}
+
+// Kaynaktan daha önce duyuru alınıp alınmadığı; ilk taramada bildirim gönderilmemesi için.
+func HasAnnouncements(db DB, src models.AnnouncementSource) (bool, error) {
+	var exists bool
+	err := db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM announcements a
+			JOIN announcement_categories ac ON ac.announcement_id = a.announcement_id
+			WHERE a.site_key = ? AND ac.category_id = ?)`, src.SiteKey, src.CategoryID,
+	).Scan(&exists)
+	return exists, err
+}
+
+var AnnouncementSortFields = map[string]string{
+	"id":           "a.announcement_id",
+	"published_at": "a.published_at",
+	"title":        "a.title",
+}
+
+type AnnouncementQuery struct {
+	ListOptions
+	Site       string
+	CategoryID *int
+	// Sunucunun bu zamandan sonra ilk kez gördüğü duyurular; cihazlar son kontrol zamanını verir.
+	Since *time.Time
+}
+
+func ListAnnouncements(db DB, q AnnouncementQuery) ([]models.Announcement, int, error) {
+	return collect(QueryAnnouncements(db, q))
+}
+
+func QueryAnnouncements(db DB, q AnnouncementQuery) (*Cursor[models.Announcement], error) {
+	where := &whereBuilder{}
+	if q.Site != "" {
+		where.add("a.site_key = ?", q.Site)
+	}
+	if q.CategoryID != nil {
+		where.add("EXISTS (SELECT 1 FROM announcement_categories ac WHERE ac.announcement_id = a.announcement_id AND ac.category_id = ?)", *q.CategoryID)
+	}
+	if q.Since != nil {
+		where.add("a.first_seen_at > ?", q.Since.UTC())
+	}
+
+	// Varsayılan sıra en yeni duyuru önce
+	order, err := orderClause(q.Sort, AnnouncementSortFields, "a.published_at DESC, a.announcement_id DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM announcements a"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitArgs := limitClause(q.ListOptions)
+	rows, err := db.Query(`
+		SELECT a.announcement_id, a.site_key, a.external_id, a.title, a.url, a.published_at, a.first_seen_at,
+			COALESCE((SELECT GROUP_CONCAT(category_id) FROM announcement_categories
+				WHERE announcement_id = a.announcement_id), '')
+		`+from+where.String()+order+limit,
+		append(where.args, limitArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.Announcement]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.Announcement, error) {
+		var a models.Announcement
+		var categories string
+		err := rows.Scan(&a.ID, &a.Site, &a.ExternalID, &a.Title, &a.URL, &a.PublishedAt, &a.FirstSeenAt, &categories)
+		a.Categories = parseIntList(categories)
+		return a, err
+	}}, nil
+}
+
+// GROUP_CONCAT çıktısını ("3,1") sıralı id listesine çevirir.
+func parseIntList(s string) []int {
+	ids := []int{}
+	for _, part := range strings.Split(s, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(part)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
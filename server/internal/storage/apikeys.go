@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+const apiKeyPrefix = "icm_"
+
+// Anahtarlar yüksek entropili rastgele değerler olduğu için tuzsuz SHA-256 yeterlidir.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Yeni anahtar üretir ve özetini kaydeder. Anahtarın kendisi sadece burada döner, tekrar elde edilemez.
+func CreateAPIKey(db DB, name string, role models.Role) (string, *models.APIKey, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	k := &models.APIKey{
+		Name:      name,
+		Prefix:    key[:len(apiKeyPrefix)+6],
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+	}
+	res, err := db.Exec(`
+		INSERT INTO api_keys (name, key_prefix, key_hash, role, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		k.Name, k.Prefix, HashAPIKey(key), k.Role, k.CreatedAt,
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	if k.ID, err = res.LastInsertId(); err != nil {
+		return "", nil, err
+	}
+	return key, k, nil
+}
+
+// Anahtarı doğrular. Anahtar yoksa ya da iptal edildiyse sql.ErrNoRows döner.
+func AuthenticateAPIKey(db DB, key string) (*models.APIKey, error) {
+	var k models.APIKey
+	err := db.QueryRow(`
+		SELECT key_id, name, key_prefix, role, created_at, last_used_at
+		FROM api_keys
+		WHERE key_hash = ? AND revoked_at IS NULL`, HashAPIKey(key),
+	).Scan(&k.ID, &k.Name, &k.Prefix, &k.Role, &k.CreatedAt, &k.LastUsedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func TouchAPIKey(db DB, id int64) error {
+	_, err := db.Exec("UPDATE api_keys SET last_used_at = ? WHERE key_id = ?", time.Now().UTC(), id)
+	return err
+}
+
+// Anahtarı iptal eder. Anahtar yoksa ya da zaten iptal edildiyse sql.ErrNoRows döner.
+func RevokeAPIKey(db DB, id int64) error {
+	res, err := db.Exec(`
+		UPDATE api_keys SET revoked_at = ?
+		WHERE key_id = ? AND revoked_at IS NULL`, time.Now().UTC(), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func ListAPIKeys(db DB) ([]models.APIKey, error) {
+	rows, err := db.Query(`
+		SELECT key_id, name, key_prefix, role, created_at, last_used_at, revoked_at
+		FROM api_keys
+		ORDER BY key_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.Role, &k.CreatedAt, &k.LastUsedAt, &k.RevokedAt); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
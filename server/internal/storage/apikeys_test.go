@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestHashAPIKey(t *testing.T) {
+	if got, want := HashAPIKey("icm_test"), "146c45fcd9ab4baaa8e0b00a776e5180975f7d220f12678f0604a4b09691258f"; got != want {
+		t.Errorf("HashAPIKey = %s, want %s", got, want)
+	}
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	db := testDB(t)
+	key, k, err := CreateAPIKey(db, "panel", models.RoleOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) || !strings.HasPrefix(key, k.Prefix) || len(key) != len(apiKeyPrefix)+43 {
+		t.Errorf("key = %q, prefix = %q", key, k.Prefix)
+	}
+	other, _, err := CreateAPIKey(db, "panel", models.RoleOperator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key {
+		t.Error("keys are not random")
+	}
+
+	// Anahtarın kendisi saklanmaz.
+	var stored string
+	if err := db.QueryRow("SELECT key_hash FROM api_keys WHERE key_id = ?", k.ID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored != HashAPIKey(key) {
+		t.Errorf("stored hash = %s", stored)
+	}
+
+	got, err := AuthenticateAPIKey(db, key)
+	if err != nil || got.ID != k.ID || got.Role != models.RoleOperator || got.LastUsedAt != nil {
+		t.Fatalf("AuthenticateAPIKey = %+v, %v", got, err)
+	}
+	if _, err := AuthenticateAPIKey(db, key+"x"); err != sql.ErrNoRows {
+		t.Errorf("wrong key: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := TouchAPIKey(db, k.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := AuthenticateAPIKey(db, key); got == nil || got.LastUsedAt == nil {
+		t.Errorf("last used not set: %+v", got)
+	}
+
+	if err := RevokeAPIKey(db, k.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AuthenticateAPIKey(db, key); err != sql.ErrNoRows {
+		t.Errorf("revoked key: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := RevokeAPIKey(db, k.ID); err != sql.ErrNoRows {
+		t.Errorf("second revoke: err = %v, want sql.ErrNoRows", err)
+	}
+	if err := RevokeAPIKey(db, 999); err != sql.ErrNoRows {
+		t.Errorf("unknown id: err = %v, want sql.ErrNoRows", err)
+	}
+
+	keys, err := ListAPIKeys(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].RevokedAt == nil || keys[1].RevokedAt != nil {
+		t.Errorf("keys = %+v", keys)
+	}
+}
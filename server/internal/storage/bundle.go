@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+func GetFacultyByID(db DB, id int) (*models.Faculty, error) {
+	var f models.Faculty
+	err := db.QueryRow(`
+		SELECT faculty_id, faculty_guid, faculty_name, faculty_name_en
+		FROM faculties WHERE faculty_id = ?`, id).Scan(&f.ID, &f.GUID, &f.Name, &f.NameEn)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Verilen kodların ders detaylarını tek sorguda döner. Detayı olmayan dersler atlanır.
+func GetCourseDetailsByCodes(db DB, codes []string) ([]models.CourseDetail, error) {
+	details := []models.CourseDetail{}
+	if len(codes) == 0 {
+		return details, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(codes)), ",")
+	args := make([]interface{}, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+
+	rows, err := db.Query(`
+		SELECT course_code, `+detailColumns("")+`
+		FROM course_details
+		WHERE course_code IN (`+placeholders+`)
+		ORDER BY course_code`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.CourseDetail
+		var outcomes outcomesJSON
+		if err := rows.Scan(append([]interface{}{&d.BaseInfo.Code}, detailFields(&d, &outcomes)...)...); err != nil {
+			return nil, err
+		}
+		outcomes.decode(&d)
+		details = append(details, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	prerequisites, err := GetPrerequisites(db, codes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range details {
+		details[i].Prerequisites = prerequisites[details[i].BaseInfo.Code]
+		if details[i].Prerequisites == nil {
+			details[i].Prerequisites = []string{}
+		}
+	}
+	return details, nil
+}
+
+// Bölümün çevrimdışı paketini oluşturur. Version alanını çağıran doldurur.
+func GetDepartmentBundle(db DB, dept models.Department) (*models.Bundle, error) {
+	faculty, err := GetFacultyByID(db, dept.FacultyID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	courses, _, err := ListCourses(db, CourseQuery{DepartmentID: dept.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, len(courses))
+	for _, c := range courses {
+		codes = append(codes, c.Code)
+	}
+	details, err := GetCourseDetailsByCodes(db, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Detaylardaki ders bilgisi, paketteki derslerden doldurulur.
+	byCode := make(map[string]models.Course, len(courses))
+	for _, c := range courses {
+		byCode[c.Code] = c
+	}
+	for i := range details {
+		details[i].BaseInfo = byCode[details[i].BaseInfo.Code]
+	}
+
+	groups, err := GetElectiveGroupsByDepartmentID(db, dept.ID)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []models.ElectiveGroup{}
+	}
+
+	bundle := &models.Bundle{
+		Department:     dept,
+		Courses:        courses,
+		Details:        details,
+		ElectiveGroups: groups,
+	}
+	if faculty != nil {
+		bundle.Faculty = *faculty
+	}
+	return bundle, nil
+}
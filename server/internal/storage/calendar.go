@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"database/sql"
+
+	"companion_server/internal/models"
+)
+
+// Taranan akademik takvimi kaydeder. Taranan akademik yıllarda sayfadan kalkan etkinlikler
+// silinir, diğer yıllara dokunulmaz. Takvim değiştiyse true döner.
+func ReplaceCalendarEvents(db DB, events []models.CalendarEvent) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO calendar_events (uid, academic_year, term, category, title, start_date, end_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(uid) DO UPDATE SET
+			category = excluded.category,
+			end_date = excluded.end_date
+		WHERE category != excluded.category OR end_date != excluded.end_date`)
+	if err != nil {
+		return false, err
+	}
+	defer stmt.Close()
+
+	changed := false
+	years := make(map[string]bool)
+	scraped := make(map[string]bool)
+	for _, e := range events {
+		years[e.AcademicYear] = true
+		scraped[e.UID] = true
+		res, err := stmt.Exec(e.UID, e.AcademicYear, e.Term, e.Category, e.Title, e.StartDate, e.EndDate)
+		if err != nil {
+			return false, err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			changed = true
+		}
+	}
+
+	rows, err := tx.Query("SELECT uid, academic_year FROM calendar_events")
+	if err != nil {
+		return false, err
+	}
+	var stale []string
+	for rows.Next() {
+		var uid, year string
+		if err := rows.Scan(&uid, &year); err != nil {
+			rows.Close()
+			return false, err
+		}
+		if years[year] && !scraped[uid] {
+			stale = append(stale, uid)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	for _, uid := range stale {
+		if _, err := tx.Exec("DELETE FROM calendar_events WHERE uid = ?", uid); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	return changed, tx.Commit()
+}
+
+var CalendarSortFields = map[string]string{
+	"start_date": "start_date",
+	"end_date":   "end_date",
+	"title":      "title",
+}
+
+type CalendarQuery struct {
+	ListOptions
+	AcademicYear string
+	Term         string
+	// nil değilse sadece bu kategoriler döner.
+	Categories []string
+	// YYYY-AA-GG; aralıkla kesişen etkinlikler döner.
+	From, To string
+}
+
+func ListCalendarEvents(db DB, q CalendarQuery) ([]models.CalendarEvent, int, error) {
+	return collect(QueryCalendarEvents(db, q))
+}
+
+func QueryCalendarEvents(db DB, q CalendarQuery) (*Cursor[models.CalendarEvent], error) {
+	where := &whereBuilder{}
+	if q.AcademicYear != "" {
+		where.add("academic_year = ?", q.AcademicYear)
+	}
+	if q.Term != "" {
+		where.add("term = ?", q.Term)
+	}
+	if q.Categories != nil {
+		where.inStrings("category", q.Categories)
+	}
+	if q.From != "" {
+		where.add("end_date >= ?", q.From)
+	}
+	if q.To != "" {
+		where.add("start_date <= ?", q.To)
+	}
+
+	order, err := orderClause(q.Sort, CalendarSortFields, "start_date, event_id")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM calendar_events"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitArgs := limitClause(q.ListOptions)
+	rows, err := db.Query(
+		"SELECT event_id, uid, academic_year, term, category, title, start_date, end_date "+
+			from+where.String()+order+limit,
+		append(where.args, limitArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.CalendarEvent]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.CalendarEvent, error) {
+		var e models.CalendarEvent
+		err := rows.Scan(&e.ID, &e.UID, &e.AcademicYear, &e.Term, &e.Category, &e.Title, &e.StartDate, &e.EndDate)
+		return e, err
+	}}, nil
+}
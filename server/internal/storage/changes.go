@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"companion_server/internal/models"
+)
+
+// İlk başarılı taramadan önceki yüklemeler değişiklik sayılmaz; aksi halde boş bir
+// veritabanındaki bütün dersler "eklendi" olarak görünürdü.
+const changeLogEnabled = "EXISTS (SELECT 1 FROM scrape_runs WHERE status = 'succeeded')"
+
+const changeTime = "strftime('%Y-%m-%d %H:%M:%S', 'now')"
+
+// Ders değişiklikleri tetikleyicilerle kaydedilir; izlence değişikliklerinin bölümü
+// okunurken dersin bölümlerinden bulunur, bu yüzden department_id boş kalır.
+func createChangeTriggers(db *sql.DB) error {
+	triggers := []string{
+		fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS courses_change_insert AFTER INSERT ON courses
+		WHEN NEW.is_removed = 0 AND %[1]s BEGIN
+			INSERT INTO course_changes (department_id, course_code, kind, changed_at)
+			VALUES (NEW.department_id, NEW.course_code, '%[2]s', %[3]s);
+		END;`, changeLogEnabled, models.ChangeCourseAdded, changeTime),
+		fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS courses_change_removed AFTER UPDATE OF is_removed ON courses
+		WHEN OLD.is_removed IS NOT NEW.is_removed AND %[1]s BEGIN
+			INSERT INTO course_changes (department_id, course_code, kind, changed_at)
+			VALUES (NEW.department_id, NEW.course_code,
+				CASE WHEN NEW.is_removed THEN '%[2]s' ELSE '%[3]s' END, %[4]s);
+		END;`, changeLogEnabled, models.ChangeCourseRemoved, models.ChangeCourseAdded, changeTime),
+		fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS courses_change_update
+		AFTER UPDATE OF course_name, credit, ects, is_mandatory, theory_hours, practice_hours, lab_hours, semester ON courses
+		WHEN (OLD.course_name IS NOT NEW.course_name OR OLD.credit IS NOT NEW.credit OR OLD.ects IS NOT NEW.ects
+			OR OLD.is_mandatory IS NOT NEW.is_mandatory OR OLD.theory_hours IS NOT NEW.theory_hours
+			OR OLD.practice_hours IS NOT NEW.practice_hours OR OLD.lab_hours IS NOT NEW.lab_hours
+			OR OLD.semester IS NOT NEW.semester) AND %[1]s BEGIN
+			INSERT INTO course_changes (department_id, course_code, kind, changed_at)
+			VALUES (NEW.department_id, NEW.course_code, '%[2]s', %[3]s);
+		END;`, changeLogEnabled, models.ChangeCourseUpdated, changeTime),
+		fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS course_details_change_insert AFTER INSERT ON course_details
+		WHEN %[1]s BEGIN
+			INSERT INTO course_changes (department_id, course_code, kind, changed_at)
+			VALUES (NULL, NEW.course_code, '%[2]s', %[3]s);
+		END;`, changeLogEnabled, models.ChangeDetailUpdated, changeTime),
+		fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS course_details_change_update
+		AFTER UPDATE OF instructor, language, aim, content, resources, outcomes ON course_details
+		WHEN (OLD.instructor IS NOT NEW.instructor OR OLD.language IS NOT NEW.language OR OLD.aim IS NOT NEW.aim
+			OR OLD.content IS NOT NEW.content OR OLD.resources IS NOT NEW.resources
+			OR OLD.outcomes IS NOT NEW.outcomes) AND %[1]s BEGIN
+			INSERT INTO course_changes (department_id, course_code, kind, changed_at)
+			VALUES (NULL, NEW.course_code, '%[2]s', %[3]s);
+		END;`, changeLogEnabled, models.ChangeDetailUpdated, changeTime),
+	}
+	for _, trigger := range triggers {
+		if _, err := db.Exec(trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Bölümün en yeni müfredat değişikliklerini döner; izlence değişiklikleri bölümde
+// okutulan derslerden bulunur.
+func GetDepartmentChanges(db DB, departmentID, limit int) ([]models.CourseChange, error) {
+	rows, err := db.Query(`
+		SELECT ch.change_id, ch.course_code, ch.kind, ch.changed_at,
+			COALESCE((SELECT c.course_name FROM courses c
+				WHERE c.course_code = ch.course_code AND c.department_id = ?
+				ORDER BY c.year DESC LIMIT 1), '')
+		FROM course_changes ch
+		WHERE ch.department_id = ?
+			OR (ch.department_id IS NULL AND ch.course_code IN
+				(SELECT course_code FROM courses WHERE department_id = ? AND is_removed = 0))
+		ORDER BY ch.change_id DESC
+		LIMIT ?`, departmentID, departmentID, departmentID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.CourseChange{}
+	for rows.Next() {
+		c := models.CourseChange{DepartmentID: departmentID}
+		if err := rows.Scan(&c.ID, &c.CourseCode, &c.Kind, &c.ChangedAt, &c.CourseName); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Değişiklik günlüğü okuyucuları (change_cursors.name)
+const (
+	WebhookCursor = "webhooks"
+	PushCursor    = "push"
+)
+
+// Okuyucunun işlediği son değişikliğin id'si; GetChangesAfter ile kullanılır. İlk çağrıda
+// imleç o anki son değişikliğe kurulur, okuyucu eklenmeden önceki geçmiş bildirilmez.
+func ChangeCursor(db DB, name string) (int64, error) {
+	if _, err := db.Exec(`
+		INSERT OR IGNORE INTO change_cursors (name, change_id)
+		SELECT ?, COALESCE(MAX(change_id), 0) FROM course_changes`, name); err != nil {
+		return 0, err
+	}
+	var id int64
+	err := db.QueryRow("SELECT change_id FROM change_cursors WHERE name = ?", name).Scan(&id)
+	return id, err
+}
+
+// İmleci id'ye ilerletir; imleç geri alınmaz.
+func AdvanceChangeCursor(db DB, name string, id int64) error {
+	return advanceChangeCursor(db, name, id)
+}
+
+func advanceChangeCursor(db execer, name string, id int64) error {
+	_, err := db.Exec("UPDATE change_cursors SET change_id = MAX(change_id, ?) WHERE name = ?", id, name)
+	return err
+}
+
+// id'den sonraki değişiklikleri bölüm bazında döner. İzlence değişiklikleri dersi
+// okutan her aktif bölüm için ayrı satır olarak yer alır.
+func GetChangesAfter(db DB, id int64) ([]models.CourseChange, error) {
+	rows, err := db.Query(`
+		SELECT ch.change_id, c.department_id, ch.course_code, c.course_name, ch.kind, ch.changed_at
+		FROM course_changes ch
+		JOIN courses c ON c.course_code = ch.course_code
+			AND (c.department_id = ch.department_id OR (ch.department_id IS NULL AND c.is_removed = 0))
+		WHERE ch.change_id > ?
+		ORDER BY ch.change_id, c.department_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.CourseChange{}
+	for rows.Next() {
+		var c models.CourseChange
+		if err := rows.Scan(&c.ID, &c.DepartmentID, &c.CourseCode, &c.CourseName, &c.Kind, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// Bölümdeki aktif dersleri pasif yapar; güncel müfredatta bulunmayan dersler kaldırılmıştır.
+func MarkRemovedCourses(db DB, departmentID int, currentCodes []string) (int64, error) {
+	if len(currentCodes) == 0 {
+		return 0, nil
+	}
+	args := []interface{}{departmentID}
+	placeholders := ""
+	for i, code := range currentCodes {
+		if i > 0 {
+			placeholders += ","
+		}
+		placeholders += "?"
+		args = append(args, code)
+	}
+	res, err := db.Exec(`
+		UPDATE courses SET is_removed = 1
+		WHERE department_id = ? AND is_removed = 0 AND course_code NOT IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestEnqueueChangesCursor(t *testing.T) {
+	db := testDB(t)
+	// Değişiklik günlüğü ilk başarılı taramadan sonra tutulur.
+	run, err := StartScrapeRun(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := FinishScrapeRun(db, run, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertCourse(db, models.Course{Code: "ESK101", Name: "Eski", Semester: "1. Yarıyıl"}, 10); err != nil {
+		t.Fatal(err)
+	}
+	// İmleç kurulmadan önceki değişiklikler bildirilmez.
+	start, err := ChangeCursor(db, WebhookCursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changes, _ := GetChangesAfter(db, start); len(changes) != 0 {
+		t.Errorf("changes before cursor = %+v", changes)
+	}
+
+	if _, err := CreateWebhook(db, "https://example.com/hook", []string{"*"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"YEN101", "YEN102"} {
+		if err := InsertCourse(db, models.Course{Code: code, Name: code, Semester: "1. Yarıyıl"}, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	changes, err := GetChangesAfter(db, start)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("changes = %+v, %v", changes, err)
+	}
+
+	// Kuyruğa ekleme başarısız olursa imleç ilerlememeli.
+	if _, err := db.Exec("ALTER TABLE webhook_deliveries RENAME TO deliveries_off"); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnqueueChanges(db, changes); err == nil {
+		t.Fatal("EnqueueChanges succeeded without deliveries table")
+	}
+	if cur, _ := ChangeCursor(db, WebhookCursor); cur != start {
+		t.Errorf("cursor after failure = %d, want %d", cur, start)
+	}
+	var events int
+	db.QueryRow("SELECT COUNT(*) FROM webhook_events").Scan(&events)
+	if events != 0 {
+		t.Errorf("events after failure = %d, want 0", events)
+	}
+
+	if _, err := db.Exec("ALTER TABLE deliveries_off RENAME TO webhook_deliveries"); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnqueueChanges(db, changes); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := ChangeCursor(db, WebhookCursor); cur != changes[1].ID {
+		t.Errorf("cursor = %d, want %d", cur, changes[1].ID)
+	}
+	var deliveries int
+	db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries").Scan(&deliveries)
+	if deliveries != 2 {
+		t.Errorf("deliveries = %d, want 2", deliveries)
+	}
+
+	// İmleç geri alınmaz.
+	if err := AdvanceChangeCursor(db, WebhookCursor, start); err != nil {
+		t.Fatal(err)
+	}
+	if cur, _ := ChangeCursor(db, WebhookCursor); cur != changes[1].ID {
+		t.Errorf("cursor moved back to %d", cur)
+	}
+}
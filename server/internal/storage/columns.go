@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+// Ders satırının kolonları. alias tablo takma adıdır (ör. "c."); sıra courseFields ile aynıdır.
+func courseColumns(alias string) string {
+	columns := []string{
+		"course_code", "department_id", "course_name", "course_name_en", "credit", "ects", "is_mandatory",
+		"theory_hours", "practice_hours", "lab_hours",
+		"semester", "link_id", "unit_id", "year", "is_removed",
+	}
+	return alias + strings.Join(columns, ", "+alias)
+}
+
+func courseFields(c *models.Course) []interface{} {
+	return []interface{}{
+		&c.Code, &c.DepartmentID, &c.Name, &c.NameEn, &c.Credit, &c.ECTS, &c.IsMandatory,
+		&c.Theory, &c.Practice, &c.Lab, &c.Semester, &c.LinkID, &c.UnitID,
+		&c.Year, &c.IsRemoved,
+	}
+}
+
+// İzlence içeriğinin kolonları (ders kodu hariç); sıra detailFields ile aynıdır.
+func detailColumns(alias string) string {
+	columns := []string{
+		"instructor", "language", "aim", "content", "resources", "outcomes",
+		"aim_en", "content_en", "resources_en", "outcomes_en",
+	}
+	return alias + strings.Join(columns, ", "+alias)
+}
+
+// Öğrenme çıktıları JSON olarak saklanır; okunan değerler decode ile açılır.
+type outcomesJSON struct {
+	tr, en string
+}
+
+func detailFields(d *models.CourseDetail, outcomes *outcomesJSON) []interface{} {
+	return []interface{}{
+		&d.Instructor, &d.Language, &d.Aim, &d.Content, &d.Resources, &outcomes.tr,
+		&d.AimEn, &d.ContentEn, &d.ResourcesEn, &outcomes.en,
+	}
+}
+
+func (o outcomesJSON) decode(d *models.CourseDetail) {
+	_ = json.Unmarshal([]byte(o.tr), &d.Outcomes)
+	if o.en != "" {
+		_ = json.Unmarshal([]byte(o.en), &d.OutcomesEn)
+	}
+}
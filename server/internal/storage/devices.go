@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Abonelik konuları "department:<guid>", "faculty:<id>" ve "site:<siteKey>" biçimindedir.
+const (
+	topicDepartment = "department:"
+	topicFaculty    = "faculty:"
+	topicSite       = "site:"
+)
+
+func DepartmentTopic(guid string) string { return topicDepartment + guid }
+func FacultyTopic(id int) string         { return topicFaculty + strconv.Itoa(id) }
+func SiteTopic(siteKey string) string    { return topicSite + siteKey }
+
+func subscriptionTopics(s models.Subscriptions) []string {
+	var topics []string
+	for _, guid := range s.Departments {
+		topics = append(topics, DepartmentTopic(guid))
+	}
+	for _, id := range s.Faculties {
+		topics = append(topics, FacultyTopic(id))
+	}
+	for _, site := range s.Sites {
+		topics = append(topics, SiteTopic(site))
+	}
+	return topics
+}
+
+// Cihazı kaydeder ya da aynı push jetonlu cihazı günceller; abonelikler verilenlerle
+// değiştirilir. d.ID, d.CreatedAt ve d.UpdatedAt doldurulur.
+func SaveDevice(db DB, d *models.Device) error {
+	now := time.Now().UTC().Truncate(time.Second)
+	if d.ID == "" {
+		err := db.QueryRow("SELECT device_id FROM devices WHERE push_token = ?", d.Token).Scan(&d.ID)
+		if err == sql.ErrNoRows {
+			raw := make([]byte, 18)
+			if _, err := rand.Read(raw); err != nil {
+				return err
+			}
+			d.ID = base64.RawURLEncoding.EncodeToString(raw)
+		} else if err != nil {
+			return err
+		}
+	} else {
+		// Jeton başka bir cihaz kaydına aitse (ör. uygulama yeniden kuruldu) eski kayıt kaldırılır.
+		if _, err := db.Exec(`
+			DELETE FROM device_subscriptions WHERE device_id IN
+				(SELECT device_id FROM devices WHERE push_token = ? AND device_id != ?)`, d.Token, d.ID); err != nil {
+			return err
+		}
+		if _, err := db.Exec("DELETE FROM devices WHERE push_token = ? AND device_id != ?", d.Token, d.ID); err != nil {
+			return err
+		}
+	}
+
+	if _, err := db.Exec(`
+		INSERT INTO devices (device_id, push_token, platform, language, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(device_id) DO UPDATE SET
+			push_token = excluded.push_token,
+			platform = excluded.platform,
+			language = excluded.language,
+			updated_at = excluded.updated_at`,
+		d.ID, d.Token, d.Platform, d.Language, now, now,
+	); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec("DELETE FROM device_subscriptions WHERE device_id = ?", d.ID); err != nil {
+		return err
+	}
+	for _, topic := range subscriptionTopics(d.Subscriptions) {
+		if _, err := db.Exec(
+			"INSERT OR IGNORE INTO device_subscriptions (device_id, topic) VALUES (?, ?)", d.ID, topic,
+		); err != nil {
+			return err
+		}
+	}
+
+	return db.QueryRow("SELECT created_at, updated_at FROM devices WHERE device_id = ?", d.ID).
+		Scan(&d.CreatedAt, &d.UpdatedAt)
+}
+
+// Cihazı abonelikleriyle döner. Cihaz yoksa sql.ErrNoRows döner.
+func GetDevice(db DB, id string) (*models.Device, error) {
+	d := models.Device{ID: id}
+	err := db.QueryRow(`
+		SELECT push_token, platform, language, created_at, updated_at
+		FROM devices WHERE device_id = ?`, id,
+	).Scan(&d.Token, &d.Platform, &d.Language, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT topic FROM device_subscriptions WHERE device_id = ? ORDER BY topic", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	d.Subscriptions = models.Subscriptions{Departments: []string{}, Faculties: []int{}, Sites: []string{}}
+	for rows.Next() {
+		var topic string
+		if err := rows.Scan(&topic); err != nil {
+			return nil, err
+		}
+		switch {
+		case strings.HasPrefix(topic, topicDepartment):
+			d.Subscriptions.Departments = append(d.Subscriptions.Departments, strings.TrimPrefix(topic, topicDepartment))
+		case strings.HasPrefix(topic, topicFaculty):
+			if id, err := strconv.Atoi(strings.TrimPrefix(topic, topicFaculty)); err == nil {
+				d.Subscriptions.Faculties = append(d.Subscriptions.Faculties, id)
+			}
+		case strings.HasPrefix(topic, topicSite):
+			d.Subscriptions.Sites = append(d.Subscriptions.Sites, strings.TrimPrefix(topic, topicSite))
+		}
+	}
+	return &d, rows.Err()
+}
+
+// Cihazı ve aboneliklerini siler. Cihaz yoksa false döner.
+func DeleteDevice(db DB, id string) (bool, error) {
+	if _, err := db.Exec("DELETE FROM device_subscriptions WHERE device_id = ?", id); err != nil {
+		return false, err
+	}
+	res, err := db.Exec("DELETE FROM devices WHERE device_id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Push jetonu bildirim servisince geçersiz bildirilen cihazları siler.
+func DeleteDevicesByToken(db DB, tokens []string) error {
+	for _, token := range tokens {
+		var id string
+		err := db.QueryRow("SELECT device_id FROM devices WHERE push_token = ?", token).Scan(&id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := DeleteDevice(db, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Konulardan en az birine abone olan cihazları döner; her cihaz bir kez yer alır.
+func GetSubscribedDevices(db DB, topics []string) ([]models.Device, error) {
+	devices := []models.Device{}
+	if len(topics) == 0 {
+		return devices, nil
+	}
+	args := make([]interface{}, len(topics))
+	for i, t := range topics {
+		args[i] = t
+	}
+	rows, err := db.Query(`
+		SELECT device_id, push_token, platform, language
+		FROM devices
+		WHERE device_id IN (SELECT device_id FROM device_subscriptions
+			WHERE topic IN (`+strings.TrimSuffix(strings.Repeat("?,", len(topics)), ",")+`))
+		ORDER BY device_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.Device
+		if err := rows.Scan(&d.ID, &d.Token, &d.Platform, &d.Language); err != nil {
+			return nil, err
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
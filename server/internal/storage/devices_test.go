@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestGetSubscribedDevices(t *testing.T) {
+	db := testDB(t)
+	devices := []*models.Device{
+		{Token: "bolum", Platform: models.PlatformAndroid, Language: models.LangTR,
+			Subscriptions: models.Subscriptions{Departments: []string{"d1"}}},
+		{Token: "fakulte", Platform: models.PlatformIOS, Language: models.LangEN,
+			Subscriptions: models.Subscriptions{Faculties: []int{1}}},
+		// İki konuya da abone; bir kez dönmeli.
+		{Token: "ikisi", Platform: models.PlatformWeb, Language: models.LangTR,
+			Subscriptions: models.Subscriptions{Departments: []string{"d1"}, Faculties: []int{1}}},
+		{Token: "site", Platform: models.PlatformAndroid, Language: models.LangTR,
+			Subscriptions: models.Subscriptions{Sites: []string{"bm"}}},
+	}
+	for _, d := range devices {
+		if err := SaveDevice(db, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tokens := func(topics ...string) map[string]bool {
+		t.Helper()
+		got, err := GetSubscribedDevices(db, topics)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set := make(map[string]bool)
+		for _, d := range got {
+			if set[d.Token] {
+				t.Errorf("%v: device %s returned twice", topics, d.Token)
+			}
+			set[d.Token] = true
+		}
+		return set
+	}
+
+	tests := []struct {
+		topics []string
+		want   []string
+	}{
+		{[]string{DepartmentTopic("d1"), FacultyTopic(1)}, []string{"bolum", "fakulte", "ikisi"}},
+		{[]string{DepartmentTopic("d1")}, []string{"bolum", "ikisi"}},
+		{[]string{SiteTopic("bm")}, []string{"site"}},
+		{[]string{DepartmentTopic("d2")}, nil},
+		{nil, nil},
+	}
+	for _, tt := range tests {
+		got := tokens(tt.topics...)
+		if len(got) != len(tt.want) {
+			t.Errorf("%v: devices = %v, want %v", tt.topics, got, tt.want)
+			continue
+		}
+		for _, token := range tt.want {
+			if !got[token] {
+				t.Errorf("%v: missing %s", tt.topics, token)
+			}
+		}
+	}
+
+	// Geçersiz jetonlu cihaz abonelikleriyle birlikte silinir.
+	if err := DeleteDevicesByToken(db, []string{"ikisi", "bilinmeyen"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := tokens(DepartmentTopic("d1"), FacultyTopic(1)); got["ikisi"] || len(got) != 2 {
+		t.Errorf("after delete: devices = %v", got)
+	}
+	var subs int
+	db.QueryRow("SELECT COUNT(*) FROM device_subscriptions WHERE device_id = ?", devices[2].ID).Scan(&subs)
+	if subs != 0 {
+		t.Errorf("subscriptions left = %d", subs)
+	}
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"companion_server/internal/models"
+)
+
+// Bölümün seçmeli havuzlarını ve üye derslerini taranan müfredattakilerle değiştirir. Aynı
+// ad/dönemdeki havuz güncellenir, böylece kimliği değişmez; müfredattan kalkan havuzlar silinir.
+func ReplaceElectiveGroups(db DB, departmentID int, groups []models.ElectiveGroup) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	keep := make(map[int]bool, len(groups))
+	for _, g := range groups {
+		_, err = tx.Exec(`
+			INSERT INTO elective_groups
+			(department_id, group_name, semester, required_count, required_ects, year)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(department_id, group_name, semester) DO UPDATE SET
+				required_count = excluded.required_count,
+				required_ects = excluded.required_ects,
+				year = excluded.year`,
+			departmentID, g.Name, g.Semester, g.RequiredCount, g.RequiredECTS, g.Year,
+		)
+		if err != nil {
+			return err
+		}
+
+		var groupID int
+		err = tx.QueryRow(`
+			SELECT group_id FROM elective_groups
+			WHERE department_id = ? AND group_name = ? AND semester = ?`,
+			departmentID, g.Name, g.Semester,
+		).Scan(&groupID)
+		if err != nil {
+			return err
+		}
+		keep[groupID] = true
+
+		if _, err := tx.Exec("DELETE FROM elective_group_courses WHERE group_id = ?", groupID); err != nil {
+			return err
+		}
+		for _, code := range g.CourseCodes {
+			if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO elective_group_courses (group_id, course_code)
+				VALUES (?, ?)`, groupID, code); err != nil {
+				return err
+			}
+		}
+	}
+
+	rows, err := tx.Query("SELECT group_id FROM elective_groups WHERE department_id = ?", departmentID)
+	if err != nil {
+		return err
+	}
+	var stale []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		if !keep[id] {
+			stale = append(stale, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, id := range stale {
+		if _, err := tx.Exec("DELETE FROM elective_group_courses WHERE group_id = ?", id); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM elective_groups WHERE group_id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func GetElectiveGroupsByDepartmentID(db DB, departmentID int) ([]models.ElectiveGroup, error) {
+	rows, err := db.Query(`
+		SELECT group_id, department_id, group_name, semester, required_count, required_ects, year
+		FROM elective_groups
+		WHERE department_id = ?
+		ORDER BY semester, group_name`, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []models.ElectiveGroup
+	index := make(map[int]int)
+	for rows.Next() {
+		var g models.ElectiveGroup
+		if err := rows.Scan(&g.ID, &g.DepartmentID, &g.Name, &g.Semester,
+			&g.RequiredCount, &g.RequiredECTS, &g.Year); err != nil {
+			return nil, err
+		}
+		g.Courses = []models.Course{}
+		index[g.ID] = len(groups)
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	memberRows, err := db.Query(`
+		SELECT
+			m.group_id,
+			`+courseColumns("c.")+`
+		FROM elective_group_courses m
+		JOIN elective_groups g ON g.group_id = m.group_id
+		JOIN courses c ON c.course_code = m.course_code AND c.department_id = g.department_id
+		WHERE g.department_id = ?
+		ORDER BY c.course_code`, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer memberRows.Close()
+
+	for memberRows.Next() {
+		var groupID int
+		var c models.Course
+		if err := memberRows.Scan(append([]interface{}{&groupID}, courseFields(&c)...)...); err != nil {
+			return nil, err
+		}
+		if i, ok := index[groupID]; ok {
+			groups[i].Courses = append(groups[i].Courses, c)
+			groups[i].CourseCodes = append(groups[i].CourseCodes, c.Code)
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,231 @@
+package storage
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Her ders kodunun en güncel kaydını izlence metniyle birlikte döner.
+func GetCourseProfiles(db DB) ([]models.CourseProfile, error) {
+	rows, err := db.Query(`
+		SELECT
+			c.course_code, c.course_name, c.ects, c.theory_hours, c.practice_hours, c.lab_hours,
+			COALESCE(d.aim, ''), COALESCE(d.content, '')
+		FROM courses c
+		LEFT JOIN course_details d ON d.course_code = c.course_code
+		WHERE c.rowid = (
+			SELECT c2.rowid FROM courses c2
+			WHERE c2.course_code = c.course_code
+			ORDER BY c2.is_removed ASC, c2.year DESC
+			LIMIT 1
+		)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var profiles []models.CourseProfile
+	for rows.Next() {
+		var p models.CourseProfile
+		if err := rows.Scan(&p.Code, &p.Name, &p.ECTS, &p.Theory, &p.Practice, &p.Lab, &p.Aim, &p.Content); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, rows.Err()
+}
+
+// Hesaplanan adayları kaydeder. Yöneticinin onayladığı/reddettiği çiftlerin durumu korunur;
+// yeni hesapta çıkmayan (ör. ders adı değişti) onay bekleyen adaylar silinir.
+func UpsertEquivalenceCandidates(db DB, candidates []models.CourseEquivalence) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := deleteStaleCandidates(tx, candidates); err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO course_equivalences (course_code, equivalent_code, score, status, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(course_code, equivalent_code) DO UPDATE SET
+			score = excluded.score,
+			updated_at = CASE WHEN course_equivalences.score != excluded.score
+				THEN excluded.updated_at ELSE course_equivalences.updated_at END`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC()
+	for _, c := range candidates {
+		if _, err := stmt.Exec(c.CourseCode, c.EquivalentCode, c.Score, models.EquivalenceCandidate, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func deleteStaleCandidates(tx *sql.Tx, candidates []models.CourseEquivalence) error {
+	current := make(map[[2]string]bool, len(candidates))
+	for _, c := range candidates {
+		current[[2]string{c.CourseCode, c.EquivalentCode}] = true
+	}
+
+	rows, err := tx.Query("SELECT course_code, equivalent_code FROM course_equivalences WHERE status = ?", models.EquivalenceCandidate)
+	if err != nil {
+		return err
+	}
+	var stale [][2]string
+	for rows.Next() {
+		var pair [2]string
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			rows.Close()
+			return err
+		}
+		if !current[pair] {
+			stale = append(stale, pair)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, pair := range stale {
+		if _, err := tx.Exec(`
+			DELETE FROM course_equivalences
+			WHERE course_code = ? AND equivalent_code = ? AND status = ?`,
+			pair[0], pair[1], models.EquivalenceCandidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Çiftin durumunu değiştirir. Aday listesinde olmayan çift elle eklenebilir.
+func SetEquivalenceStatus(db DB, code, equivalent string, status models.EquivalenceStatus) error {
+	if code > equivalent {
+		code, equivalent = equivalent, code
+	}
+	_, err := db.Exec(`
+		INSERT INTO course_equivalences (course_code, equivalent_code, status, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(course_code, equivalent_code) DO UPDATE SET
+			status = excluded.status,
+			updated_at = excluded.updated_at`,
+		code, equivalent, status, time.Now().UTC(),
+	)
+	return err
+}
+
+const equivalenceColumns = `
+	e.course_code,
+	COALESCE((SELECT course_name FROM courses WHERE course_code = e.course_code ORDER BY year DESC LIMIT 1), ''),
+	e.equivalent_code,
+	COALESCE((SELECT course_name FROM courses WHERE course_code = e.equivalent_code ORDER BY year DESC LIMIT 1), ''),
+	e.score, e.status, e.updated_at`
+
+func scanEquivalences(rows *sql.Rows) ([]models.CourseEquivalence, error) {
+	defer rows.Close()
+
+	var result []models.CourseEquivalence
+	for rows.Next() {
+		var e models.CourseEquivalence
+		if err := rows.Scan(&e.CourseCode, &e.CourseName, &e.EquivalentCode, &e.EquivalentName,
+			&e.Score, &e.Status, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// Duruma göre eşdeğerlik çiftlerini skor sırasıyla döner.
+func GetEquivalencesByStatus(db DB, status models.EquivalenceStatus, limit int) ([]models.CourseEquivalence, error) {
+	rows, err := db.Query(`
+		SELECT `+equivalenceColumns+`
+		FROM course_equivalences e
+		WHERE e.status = ?
+		ORDER BY e.score DESC, e.course_code
+		LIMIT ?`, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanEquivalences(rows)
+}
+
+// Dersin onaylanmış eşdeğerlerini döner. Sonuçta CourseCode her zaman verilen koddur.
+func GetConfirmedEquivalents(db DB, code string) ([]models.CourseEquivalence, error) {
+	rows, err := db.Query(`
+		SELECT `+equivalenceColumns+`
+		FROM course_equivalences e
+		WHERE e.status = ? AND (e.course_code = ? OR e.equivalent_code = ?)
+		ORDER BY e.score DESC`, models.EquivalenceConfirmed, code, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scanEquivalences(rows)
+	if err != nil {
+		return nil, err
+	}
+	for i, e := range result {
+		if e.CourseCode != code {
+			result[i].CourseCode, result[i].EquivalentCode = e.EquivalentCode, e.CourseCode
+			result[i].CourseName, result[i].EquivalentName = e.EquivalentName, e.CourseName
+		}
+	}
+	return result, nil
+}
+
+// GetConfirmedEquivalents'in toplu karşılığı: her kodun onaylı eşdeğerlikleri tek sorguyla,
+// kod CourseCode tarafında olacak şekilde döner.
+func GetConfirmedEquivalentsByCodes(db DB, codes []string) (map[string][]models.CourseEquivalence, error) {
+	result := make(map[string][]models.CourseEquivalence)
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(codes)), ",")
+	args := []interface{}{models.EquivalenceConfirmed}
+	for range 2 {
+		for _, code := range codes {
+			args = append(args, code)
+		}
+	}
+	rows, err := db.Query(`
+		SELECT `+equivalenceColumns+`
+		FROM course_equivalences e
+		WHERE e.status = ? AND (e.course_code IN (`+placeholders+`) OR e.equivalent_code IN (`+placeholders+`))
+		ORDER BY e.score DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	equivalences, err := scanEquivalences(rows)
+	if err != nil {
+		return nil, err
+	}
+	wanted := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		wanted[code] = true
+	}
+	for _, e := range equivalences {
+		if wanted[e.CourseCode] {
+			result[e.CourseCode] = append(result[e.CourseCode], e)
+		}
+		if wanted[e.EquivalentCode] {
+			e.CourseCode, e.EquivalentCode = e.EquivalentCode, e.CourseCode
+			e.CourseName, e.EquivalentName = e.EquivalentName, e.CourseName
+			result[e.CourseCode] = append(result[e.CourseCode], e)
+		}
+	}
+	return result, nil
+}
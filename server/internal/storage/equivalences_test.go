@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestGetConfirmedEquivalentsByCodes(t *testing.T) {
+	db := testDB(t)
+	for _, pair := range [][2]string{{"FIZ101", "PHY101"}, {"KIM101", "MAT101"}, {"MAT101", "MATH101"}} {
+		if err := SetEquivalenceStatus(db, pair[0], pair[1], models.EquivalenceConfirmed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetEquivalenceStatus(db, "FIZ101", "FIZ111", models.EquivalenceRejected); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetConfirmedEquivalentsByCodes(db, []string{"FIZ101", "MAT101", "YOK101"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"FIZ101": {"PHY101"}, "MAT101": {"KIM101", "MATH101"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d codes, want %d: %+v", len(got), len(want), got)
+	}
+	for code, equivalents := range want {
+		if len(got[code]) != len(equivalents) {
+			t.Errorf("%s: got %+v, want %v", code, got[code], equivalents)
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, e := range got[code] {
+			if e.CourseCode != code {
+				t.Errorf("%s: course code = %s", code, e.CourseCode)
+			}
+			seen[e.EquivalentCode] = true
+		}
+		for _, e := range equivalents {
+			if !seen[e] {
+				t.Errorf("%s: missing equivalent %s", code, e)
+			}
+		}
+	}
+}
+
+func TestUpsertEquivalenceCandidatesDeletesStale(t *testing.T) {
+	db := testDB(t)
+	if err := UpsertEquivalenceCandidates(db, []models.CourseEquivalence{
+		{CourseCode: "FIZ101", EquivalentCode: "PHY101", Score: 0.9},
+		{CourseCode: "KIM101", EquivalentCode: "KIM111", Score: 0.7},
+		{CourseCode: "MAT101", EquivalentCode: "MATH101", Score: 0.8},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetEquivalenceStatus(db, "MAT101", "MATH101", models.EquivalenceConfirmed); err != nil {
+		t.Fatal(err)
+	}
+
+	// KIM101 ve onaylanan MAT101 çifti yeni hesapta yok: aday silinir, onaylı çift kalır.
+	if err := UpsertEquivalenceCandidates(db, []models.CourseEquivalence{
+		{CourseCode: "FIZ101", EquivalentCode: "PHY101", Score: 0.95},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates, err := GetEquivalencesByStatus(db, models.EquivalenceCandidate, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 1 || candidates[0].CourseCode != "FIZ101" || candidates[0].Score != 0.95 {
+		t.Errorf("candidates = %+v", candidates)
+	}
+	confirmed, err := GetEquivalencesByStatus(db, models.EquivalenceConfirmed, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(confirmed) != 1 || confirmed[0].CourseCode != "MAT101" {
+		t.Errorf("confirmed = %+v", confirmed)
+	}
+}
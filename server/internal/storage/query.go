@@ -0,0 +1,407 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Liste sorgularının ortak sayfalama ve sıralama seçenekleri. Limit 0 ise tüm kayıtlar döner.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Sort   []SortField
+}
+
+// WHERE koşullarını ve argümanlarını birlikte biriktirir; değerler her zaman placeholder ile geçer.
+type whereBuilder struct {
+	clauses []string
+	args    []interface{}
+}
+
+func (b *whereBuilder) add(clause string, args ...interface{}) {
+	b.clauses = append(b.clauses, clause)
+	b.args = append(b.args, args...)
+}
+
+// column IN (...) koşulu ekler. Liste boşsa hiçbir satır eşleşmez.
+func (b *whereBuilder) in(column string, values []int) {
+	if len(values) == 0 {
+		b.add("0")
+		return
+	}
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	b.add(column+" IN ("+strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")+")", args...)
+}
+
+// in'in metin değerli karşılığı
+func (b *whereBuilder) inStrings(column string, values []string) {
+	if len(values) == 0 {
+		b.add("0")
+		return
+	}
+	args := make([]interface{}, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	b.add(column+" IN ("+strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")+")", args...)
+}
+
+// Sütunlardan herhangi biri metni içeriyorsa eşleşir; % ve _ joker olarak yorumlanmaz.
+func (b *whereBuilder) contains(text string, columns ...string) {
+	pattern := "%" + likeEscaper.Replace(text) + "%"
+	parts := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		parts[i] = column + ` LIKE ? ESCAPE '\'`
+		args[i] = pattern
+	}
+	b.add("("+strings.Join(parts, " OR ")+")", args...)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (b *whereBuilder) String() string {
+	if len(b.clauses) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(b.clauses, " AND ")
+}
+
+// Sıralama alanları sadece izin verilen kolonlara çevrilir, bilinmeyen alan hata döner.
+func orderClause(sort []SortField, columns map[string]string, fallback string) (string, error) {
+	if len(sort) == 0 {
+		return " ORDER BY " + fallback, nil
+	}
+
+	parts := make([]string, 0, len(sort)+1)
+	for _, s := range sort {
+		column, ok := columns[s.Field]
+		if !ok {
+			return "", fmt.Errorf("sıralanamayan alan: %s", s.Field)
+		}
+		if s.Desc {
+			column += " DESC"
+		}
+		parts = append(parts, column)
+	}
+	// Sayfalar arası kararlı sıra için
+	parts = append(parts, fallback)
+	return " ORDER BY " + strings.Join(parts, ", "), nil
+}
+
+func limitClause(opts ListOptions) (string, []interface{}) {
+	if opts.Limit <= 0 {
+		if opts.Offset > 0 {
+			return " LIMIT -1 OFFSET ?", []interface{}{opts.Offset}
+		}
+		return "", nil
+	}
+	return " LIMIT ? OFFSET ?", []interface{}{opts.Limit, opts.Offset}
+}
+
+// Sorgu sonucunu satır satır okur; büyük listelerin belleğe alınmadan yazılabilmesi için.
+// Total, sayfalamadan bağımsız toplam kayıt sayısıdır. Kullanım sonrası Close çağrılmalıdır.
+type Cursor[T any] struct {
+	Total int
+	rows  *sql.Rows
+	scan  func(*sql.Rows) (T, error)
+}
+
+func (c *Cursor[T]) Next() bool       { return c.rows.Next() }
+func (c *Cursor[T]) Item() (T, error) { return c.scan(c.rows) }
+func (c *Cursor[T]) Err() error       { return c.rows.Err() }
+func (c *Cursor[T]) Close() error     { return c.rows.Close() }
+
+func collect[T any](c *Cursor[T], err error) ([]T, int, error) {
+	if err != nil {
+		return nil, 0, err
+	}
+	defer c.Close()
+
+	items := []T{}
+	for c.Next() {
+		item, err := c.Item()
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	return items, c.Total, c.Err()
+}
+
+// İç içe listelerin üst nesne (fakülte, bölüm) başına sayfası. First 0 ise sınır yoktur.
+// After verilirse aynı üst nesnede anahtarı After olan öğeden sonrakiler döner; anahtar o
+// üst nesnede yoksa hiçbiri dönmez.
+type ParentPage struct {
+	First int
+	After string
+}
+
+// Sorguyu üst nesne başına sayfalar: satırlar parent'a göre bölünüp order ile numaralanır,
+// her üst nesneden sadece istenen aralık okunur. columns iç sorgunun, names dış sorgunun
+// aynı sıradaki kolonlarıdır. Sonuç üst nesneye, sonra order'a göre sıralıdır.
+func parentPageQuery(columns, names, from string, args []interface{}, parent, key, order string, page ParentPage) (string, []interface{}) {
+	query := `
+		WITH page AS (
+			SELECT ` + columns + `, ` + parent + ` AS page_parent, ` + key + ` AS page_key,
+				ROW_NUMBER() OVER (PARTITION BY ` + parent + order + `) AS page_rn
+			` + from + `
+		)
+		SELECT ` + names + ` FROM page p`
+	args = append([]interface{}{}, args...)
+
+	var conds []string
+	start := "0"
+	if page.After != "" {
+		// Anahtar bulunamazsa alt sorgu NULL olur ve hiçbir satır seçilmez.
+		start = "(SELECT a.page_rn FROM page a WHERE a.page_parent = p.page_parent AND a.page_key = ?)"
+		conds = append(conds, "p.page_rn > "+start)
+		args = append(args, page.After)
+	}
+	if page.First > 0 {
+		conds = append(conds, "p.page_rn <= "+start+" + ?")
+		if page.After != "" {
+			args = append(args, page.After)
+		}
+		args = append(args, page.First)
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	return query + " ORDER BY p.page_parent, p.page_rn", args
+}
+
+func countRows(db DB, from string, where *whereBuilder) (int, error) {
+	var total int
+	err := db.QueryRow("SELECT COUNT(*) "+from+where.String(), where.args...).Scan(&total)
+	return total, err
+}
+
+var FacultySortFields = map[string]string{
+	"id":      "faculty_id",
+	"name":    "faculty_name",
+	"name_en": "faculty_name_en",
+}
+
+type FacultyQuery struct {
+	ListOptions
+	// nil değilse sadece bu id'ler döner.
+	IDs    []int
+	Search string
+}
+
+func ListFaculties(db DB, q FacultyQuery) ([]models.Faculty, int, error) {
+	return collect(QueryFaculties(db, q))
+}
+
+func QueryFaculties(db DB, q FacultyQuery) (*Cursor[models.Faculty], error) {
+	where := &whereBuilder{}
+	if q.IDs != nil {
+		where.in("faculty_id", q.IDs)
+	}
+	if q.Search != "" {
+		where.contains(q.Search, "faculty_name", "faculty_name_en")
+	}
+
+	order, err := orderClause(q.Sort, FacultySortFields, "faculty_id")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM faculties"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitArgs := limitClause(q.ListOptions)
+	rows, err := db.Query(
+		"SELECT faculty_id, faculty_guid, faculty_name, faculty_name_en "+from+where.String()+order+limit,
+		append(where.args, limitArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.Faculty]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.Faculty, error) {
+		var f models.Faculty
+		err := rows.Scan(&f.ID, &f.GUID, &f.Name, &f.NameEn)
+		return f, err
+	}}, nil
+}
+
+var DepartmentSortFields = map[string]string{
+	"id":         "department_id",
+	"faculty_id": "faculty_id",
+	"name":       "department_name",
+	"name_en":    "department_name_en",
+}
+
+type DepartmentQuery struct {
+	ListOptions
+	FacultyID *int
+	// nil değilse sadece bu id'ler ya da bu fakültelerin bölümleri döner.
+	IDs        []int
+	FacultyIDs []int
+	Search     string
+	// nil değilse her fakültenin bölümleri ayrı sayfalanır (anahtar guid); Limit kullanılmaz.
+	PerFaculty *ParentPage
+}
+
+func ListDepartments(db DB, q DepartmentQuery) ([]models.Department, int, error) {
+	return collect(QueryDepartments(db, q))
+}
+
+func QueryDepartments(db DB, q DepartmentQuery) (*Cursor[models.Department], error) {
+	where := &whereBuilder{}
+	if q.FacultyID != nil {
+		where.add("faculty_id = ?", *q.FacultyID)
+	}
+	if q.IDs != nil {
+		where.in("department_id", q.IDs)
+	}
+	if q.FacultyIDs != nil {
+		where.in("faculty_id", q.FacultyIDs)
+	}
+	if q.Search != "" {
+		where.contains(q.Search, "department_name", "department_name_en")
+	}
+
+	order, err := orderClause(q.Sort, DepartmentSortFields, "department_id")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM departments"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	const columns = "department_id, faculty_id, department_guid, department_name, department_name_en"
+	limit, limitArgs := limitClause(q.ListOptions)
+	query, args := "SELECT "+columns+" "+from+where.String()+order+limit, append(where.args, limitArgs...)
+	if q.PerFaculty != nil {
+		query, args = parentPageQuery(columns, columns, from+where.String(), where.args,
+			"faculty_id", "department_guid", order, *q.PerFaculty)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.Department]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.Department, error) {
+		var d models.Department
+		err := rows.Scan(&d.ID, &d.FacultyID, &d.GUID, &d.Name, &d.NameEn)
+		return d, err
+	}}, nil
+}
+
+var CourseSortFields = map[string]string{
+	"code":     "c.course_code",
+	"name":     "c.course_name",
+	"credit":   "c.credit",
+	"ects":     "c.ects",
+	"semester": semesterOrder,
+	"year":     "c.year",
+}
+
+// academic.ParseSemester'in SQL karşılığı: "2. Sınıf Bahar" 4, "10. Yarıyıl" 10 olarak sıralanır.
+// CAST baştaki sayıyı alır, sayı yoksa 0 olur.
+const semesterOrder = `(CASE
+	WHEN c.semester LIKE '%s_n_f%g_z%' THEN CAST(c.semester AS INTEGER) * 2 - 1
+	WHEN c.semester LIKE '%s_n_f%bahar%' THEN CAST(c.semester AS INTEGER) * 2
+	ELSE CAST(c.semester AS INTEGER) END)`
+
+type CourseQuery struct {
+	ListOptions
+	DepartmentID int
+	// DepartmentID yerine birden fazla bölümün dersleri için
+	DepartmentIDs []int
+	Semester      string
+	IsMandatory   *bool
+	IsRemoved     *bool
+	Year          *int
+	MinECTS       *float64
+	MaxECTS       *float64
+	// Ders dili izlenceden (course_details.language) gelir.
+	Language string
+	// nil değilse her bölümün dersleri ayrı sayfalanır (anahtar ders kodu); Limit kullanılmaz.
+	PerDepartment *ParentPage
+}
+
+func ListCourses(db DB, q CourseQuery) ([]models.Course, int, error) {
+	return collect(QueryCourses(db, q))
+}
+
+func QueryCourses(db DB, q CourseQuery) (*Cursor[models.Course], error) {
+	where := &whereBuilder{}
+	if q.DepartmentIDs != nil {
+		where.in("c.department_id", q.DepartmentIDs)
+	} else {
+		where.add("c.department_id = ?", q.DepartmentID)
+	}
+	if q.Semester != "" {
+		where.add("c.semester = ?", q.Semester)
+	}
+	if q.IsMandatory != nil {
+		where.add("c.is_mandatory = ?", *q.IsMandatory)
+	}
+	if q.IsRemoved != nil {
+		where.add("c.is_removed = ?", *q.IsRemoved)
+	}
+	if q.Year != nil {
+		where.add("c.year = ?", *q.Year)
+	}
+	if q.MinECTS != nil {
+		where.add("c.ects >= ?", *q.MinECTS)
+	}
+	if q.MaxECTS != nil {
+		where.add("c.ects <= ?", *q.MaxECTS)
+	}
+	if q.Language != "" {
+		where.contains(q.Language, "d.language")
+	}
+
+	order, err := orderClause(q.Sort, CourseSortFields, "c.course_code")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM courses c LEFT JOIN course_details d ON d.course_code = c.course_code"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitArgs := limitClause(q.ListOptions)
+	query, args := `
+		SELECT
+			`+courseColumns("c.")+`
+		`+from+where.String()+order+limit, append(where.args, limitArgs...)
+	if q.PerDepartment != nil {
+		query, args = parentPageQuery(courseColumns("c."), courseColumns("p."), from+where.String(), where.args,
+			"c.department_id", "c.course_code", order, *q.PerDepartment)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.Course]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.Course, error) {
+		var c models.Course
+		err := rows.Scan(courseFields(&c)...)
+		return c, err
+	}}, nil
+}
@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestQuerySearchEscapesWildcards(t *testing.T) {
+	db := testDB(t)
+	if err := InsertDepartment(db, models.Department{ID: 11, FacultyID: 1, GUID: "d2", Name: "İnşaat_Mühendisliği"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertDepartment(db, models.Department{ID: 12, FacultyID: 1, GUID: "d3", Name: "%100 İngilizce"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		search string
+		want   int
+	}{
+		{"_", 1},
+		{"%", 1},
+		{`\`, 0},
+		{"Bilgi", 1},
+		{"i", 3},
+	}
+	for _, tt := range tests {
+		departments, total, err := ListDepartments(db, DepartmentQuery{Search: tt.search})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != tt.want || len(departments) != tt.want {
+			t.Errorf("search %q: total = %d, len = %d, want %d", tt.search, total, len(departments), tt.want)
+		}
+	}
+}
+
+func TestQueryCoursesSemesterSort(t *testing.T) {
+	db := testDB(t)
+	for _, c := range []models.Course{
+		{Code: "A", Semester: "10. Yarıyıl"},
+		{Code: "B", Semester: "2. Yarıyıl"},
+		{Code: "C", Semester: "2. Sınıf Güz"},
+		{Code: "D", Semester: "1. Sınıf Bahar"},
+		{Code: "E", Semester: "1. Yarıyıl"},
+	} {
+		if err := InsertCourse(db, c, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	courses, _, err := ListCourses(db, CourseQuery{DepartmentID: 10, ListOptions: ListOptions{
+		Sort: []SortField{{Field: "semester"}, {Field: "code"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got string
+	for _, c := range courses {
+		got += c.Code
+	}
+	// 1, 2, 2, 3, 10
+	if got != "EBDCA" {
+		t.Errorf("order = %s, want EBDCA", got)
+	}
+
+	courses, _, err = ListCourses(db, CourseQuery{DepartmentID: 10, ListOptions: ListOptions{
+		Sort: []SortField{{Field: "semester", Desc: true}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if courses[0].Code != "A" {
+		t.Errorf("first desc = %s, want A", courses[0].Code)
+	}
+}
+
+func TestParentPage(t *testing.T) {
+	db := testDB(t)
+	if err := InsertFaculty(db, models.Faculty{ID: 2, GUID: "f2", Name: "Fen"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []models.Department{
+		{ID: 11, FacultyID: 1, GUID: "d2", Name: "Elektrik"},
+		{ID: 12, FacultyID: 1, GUID: "d3", Name: "İnşaat"},
+		{ID: 20, FacultyID: 2, GUID: "d4", Name: "Fizik"},
+		{ID: 21, FacultyID: 2, GUID: "d5", Name: "Kimya"},
+	} {
+		if err := InsertDepartment(db, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, c := range []struct {
+		code string
+		dept int
+	}{{"A1", 10}, {"A2", 10}, {"A3", 10}, {"B1", 11}, {"B2", 11}} {
+		if err := InsertCourse(db, models.Course{Code: c.code, Name: c.code}, c.dept); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	departments := func(page ParentPage) string {
+		t.Helper()
+		depts, _, err := ListDepartments(db, DepartmentQuery{FacultyIDs: []int{1, 2}, PerFaculty: &page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var guids []string
+		for _, d := range depts {
+			guids = append(guids, d.GUID)
+		}
+		return strings.Join(guids, ",")
+	}
+	courses := func(page ParentPage) string {
+		t.Helper()
+		list, _, err := ListCourses(db, CourseQuery{DepartmentIDs: []int{10, 11}, PerDepartment: &page})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var codes []string
+		for _, c := range list {
+			codes = append(codes, c.Code)
+		}
+		return strings.Join(codes, ",")
+	}
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"bölümler sınırsız", departments(ParentPage{}), "d1,d2,d3,d4,d5"},
+		{"fakülte başına 1", departments(ParentPage{First: 1}), "d1,d4"},
+		{"d2'den sonra", departments(ParentPage{After: "d2"}), "d3"},
+		{"d4'ten sonra 1", departments(ParentPage{First: 1, After: "d4"}), "d5"},
+		{"bilinmeyen anahtar", departments(ParentPage{After: "yok"}), ""},
+		{"bölüm başına 2", courses(ParentPage{First: 2}), "A1,A2,B1,B2"},
+		{"A1'den sonra 1", courses(ParentPage{First: 1, After: "A1"}), "A2"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
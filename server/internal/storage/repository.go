@@ -1,14 +1,13 @@
 package storage
 
 import (
-	"database/sql"
 	"encoding/json"
-	"fmt"
+	"strings"
 
 	"companion_server/internal/models"
 )
 
-func GetExistingCourseCodes(db *sql.DB, departmentID int) (map[string]bool, error) {
+func GetExistingCourseCodes(db DB, departmentID int) (map[string]bool, error) {
 	rows, err := db.Query("SELECT course_code FROM courses WHERE department_id = ?", departmentID)
 	if err != nil {
 		return nil, err
@@ -25,8 +24,46 @@ func GetExistingCourseCodes(db *sql.DB, departmentID int) (map[string]bool, erro
 	return exists, nil
 }
 
-func GetCoursesWithValidDetails(db *sql.DB) (map[string]bool, error) {
-	rows, err := db.Query("SELECT course_code FROM course_details WHERE length(aim) > 0 OR length(content) > 0")
+// İngilizce adı boş olan derslerin kodları
+func GetCoursesMissingNameEn(db DB, departmentID int) (map[string]bool, error) {
+	rows, err := db.Query("SELECT course_code FROM courses WHERE department_id = ? AND course_name_en = ''", departmentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	missing := make(map[string]bool)
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err == nil {
+			missing[code] = true
+		}
+	}
+	return missing, rows.Err()
+}
+
+// Dersin boş İngilizce adını doldurur; dolu adlar değiştirilmez.
+func FillCourseNameEn(db DB, departmentID int, courseCode, nameEn string) error {
+	_, err := db.Exec(
+		"UPDATE courses SET course_name_en = ? WHERE course_code = ? AND department_id = ? AND course_name_en = ''",
+		nameEn, courseCode, departmentID,
+	)
+	return err
+}
+
+// İzlence ayrıştırıcısının sürümü. Ayrıştırıcı yeni bir alan okumaya başladığında artırılır;
+// eski sürümle alınmış izlenceler bir sonraki taramada yeniden alınır.
+//
+//	1: ön koşullar ve İngilizce izlence
+const DetailParserVersion = 1
+
+// İçeriği olan ve güncel ayrıştırıcıyla alınmış izlencelerin ders kodları
+func GetCoursesWithValidDetails(db DB) (map[string]bool, error) {
+	rows, err := db.Query(`
+		SELECT d.course_code
+		FROM course_details d
+		JOIN course_detail_fetches f ON f.course_code = d.course_code
+		WHERE (length(d.aim) > 0 OR length(d.content) > 0) AND f.parser_version >= ?`, DetailParserVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -42,17 +79,37 @@ func GetCoursesWithValidDetails(db *sql.DB) (map[string]bool, error) {
 	return exists, nil
 }
 
-func InsertFaculty(db *sql.DB, f models.Faculty) error {
+// İzlencenin güncel ayrıştırıcıyla tam olarak alındığını kaydeder. İngilizcesi alınamayan
+// izlenceler kaydedilmez, bir sonraki taramada yeniden denenir.
+func MarkDetailFetched(db DB, courseCode string) error {
 	_, err := db.Exec(`
-		INSERT OR REPLACE INTO faculties
+		INSERT INTO course_detail_fetches (course_code, parser_version, fetched_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT(course_code) DO UPDATE SET
+			parser_version = excluded.parser_version,
+			fetched_at = excluded.fetched_at`,
+		courseCode, DetailParserVersion)
+	return err
+}
+
+func InsertFaculty(db DB, f models.Faculty) error {
+	_, err := db.Exec(`
+		INSERT INTO faculties
 		(faculty_id, faculty_guid, faculty_name, faculty_name_en)
-		VALUES (?, ?, ?, ?)`,
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(faculty_id) DO UPDATE SET
+			faculty_guid = excluded.faculty_guid,
+			faculty_name = excluded.faculty_name,
+			faculty_name_en = excluded.faculty_name_en
+		WHERE faculty_guid IS NOT excluded.faculty_guid
+			OR faculty_name IS NOT excluded.faculty_name
+			OR faculty_name_en IS NOT excluded.faculty_name_en`,
 		f.ID, f.GUID, f.Name, f.NameEn,
 	)
 	return err
 }
 
-func GetAllFaculties(db *sql.DB) ([]models.Faculty, error) {
+func GetAllFaculties(db DB) ([]models.Faculty, error) {
 	rows, err := db.Query(`SELECT faculty_id, faculty_guid, faculty_name, faculty_name_en FROM faculties`)
 	if err != nil {
 		return nil, err
@@ -70,17 +127,26 @@ func GetAllFaculties(db *sql.DB) ([]models.Faculty, error) {
 	return faculties, nil
 }
 
-func InsertDepartment(db *sql.DB, d models.Department) error {
+func InsertDepartment(db DB, d models.Department) error {
 	_, err := db.Exec(`
-		INSERT OR REPLACE INTO departments
+		INSERT INTO departments
 		(department_id, faculty_id, department_guid, department_name, department_name_en)
-		VALUES (?, ?, ?, ?, ?)`,
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(department_id) DO UPDATE SET
+			faculty_id = excluded.faculty_id,
+			department_guid = excluded.department_guid,
+			department_name = excluded.department_name,
+			department_name_en = excluded.department_name_en
+		WHERE faculty_id IS NOT excluded.faculty_id
+			OR department_guid IS NOT excluded.department_guid
+			OR department_name IS NOT excluded.department_name
+			OR department_name_en IS NOT excluded.department_name_en`,
 		d.ID, d.FacultyID, d.GUID, d.Name, d.NameEn,
 	)
 	return err
 }
 
-func GetAllDepartments(db *sql.DB) ([]models.Department, error) {
+func GetAllDepartments(db DB) ([]models.Department, error) {
 	rows, err := db.Query(`SELECT department_id, faculty_id, department_guid, department_name, department_name_en FROM departments`)
 	if err != nil {
 		return nil, err
@@ -98,7 +164,7 @@ func GetAllDepartments(db *sql.DB) ([]models.Department, error) {
 	return departments, nil
 }
 
-func GetDepartmentsByFacultyID(db *sql.DB, facultyID int) ([]models.Department, error) {
+func GetDepartmentsByFacultyID(db DB, facultyID int) ([]models.Department, error) {
 	rows, err := db.Query(`
 		SELECT department_id, faculty_id, department_guid, department_name, department_name_en 
 		FROM departments 
@@ -119,7 +185,7 @@ func GetDepartmentsByFacultyID(db *sql.DB, facultyID int) ([]models.Department,
 	return departments, nil
 }
 
-func GetDepartmentByGUID(db *sql.DB, guid string) (*models.Department, error) {
+func GetDepartmentByGUID(db DB, guid string) (*models.Department, error) {
 	row := db.QueryRow(`
 		SELECT department_id, faculty_id, department_guid, department_name, department_name_en 
 		FROM departments 
@@ -132,24 +198,49 @@ func GetDepartmentByGUID(db *sql.DB, guid string) (*models.Department, error) {
 	return &d, nil
 }
 
-func InsertCourse(db *sql.DB, c models.Course, departmentID int) error {
+func InsertCourse(db DB, c models.Course, departmentID int) error {
 	_, err := db.Exec(`
-		INSERT OR REPLACE INTO courses
-		(course_code, department_id, course_name, credit, ects, is_mandatory,
+		INSERT INTO courses
+		(course_code, department_id, course_name, course_name_en, credit, ects, is_mandatory,
 		 theory_hours, practice_hours, lab_hours, semester, link_id, unit_id, year, is_removed)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-		c.Code, departmentID, c.Name, c.Credit, c.ECTS, c.IsMandatory,
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(course_code, department_id) DO UPDATE SET
+			course_name = excluded.course_name,
+			course_name_en = COALESCE(NULLIF(excluded.course_name_en, ''), course_name_en),
+			credit = excluded.credit,
+			ects = excluded.ects,
+			is_mandatory = excluded.is_mandatory,
+			theory_hours = excluded.theory_hours,
+			practice_hours = excluded.practice_hours,
+			lab_hours = excluded.lab_hours,
+			semester = excluded.semester,
+			link_id = excluded.link_id,
+			unit_id = excluded.unit_id,
+			year = excluded.year,
+			is_removed = excluded.is_removed
+		WHERE course_name IS NOT excluded.course_name
+			OR (excluded.course_name_en != '' AND course_name_en IS NOT excluded.course_name_en)
+			OR credit IS NOT excluded.credit
+			OR ects IS NOT excluded.ects
+			OR is_mandatory IS NOT excluded.is_mandatory
+			OR theory_hours IS NOT excluded.theory_hours
+			OR practice_hours IS NOT excluded.practice_hours
+			OR lab_hours IS NOT excluded.lab_hours
+			OR semester IS NOT excluded.semester
+			OR link_id IS NOT excluded.link_id
+			OR unit_id IS NOT excluded.unit_id
+			OR year IS NOT excluded.year
+			OR is_removed IS NOT excluded.is_removed`,
+		c.Code, departmentID, c.Name, c.NameEn, c.Credit, c.ECTS, c.IsMandatory,
 		c.Theory, c.Practice, c.Lab, c.Semester, c.LinkID, c.UnitID, c.Year, c.IsRemoved,
 	)
 	return err
 }
 
-func GetCoursesByDepartmentID(db *sql.DB, departmentID int) ([]models.Course, error) {
+func GetCoursesByDepartmentID(db DB, departmentID int) ([]models.Course, error) {
 	rows, err := db.Query(`
 		SELECT
-			course_code, department_id, course_name, credit, ects, is_mandatory,
-			theory_hours, practice_hours, lab_hours,
-			semester, link_id, unit_id, year, is_removed
+			`+courseColumns("")+`
 		FROM courses
 		WHERE department_id = ?`, departmentID)
 	if err != nil {
@@ -160,11 +251,7 @@ func GetCoursesByDepartmentID(db *sql.DB, departmentID int) ([]models.Course, er
 	var courses []models.Course
 	for rows.Next() {
 		var c models.Course
-		if err := rows.Scan(
-			&c.Code, &c.DepartmentID, &c.Name, &c.Credit, &c.ECTS, &c.IsMandatory,
-			&c.Theory, &c.Practice, &c.Lab, &c.Semester, &c.LinkID, &c.UnitID,
-			&c.Year, &c.IsRemoved,
-		); err != nil {
+		if err := rows.Scan(courseFields(&c)...); err != nil {
 			return nil, err
 		}
 		courses = append(courses, c)
@@ -172,71 +259,189 @@ func GetCoursesByDepartmentID(db *sql.DB, departmentID int) ([]models.Course, er
 	return courses, nil
 }
 
-func InsertCourseDetail(db *sql.DB, d models.CourseDetail) error {
-	outcomesJSON, err := json.Marshal(d.Outcomes)
+func InsertCourseDetail(db DB, d models.CourseDetail) error {
+	outcomes, err := json.Marshal(d.Outcomes)
 	if err != nil {
 		return err
 	}
+	outcomesEn := ""
+	if len(d.OutcomesEn) > 0 {
+		encoded, err := json.Marshal(d.OutcomesEn)
+		if err != nil {
+			return err
+		}
+		outcomesEn = string(encoded)
+	}
 
+	// İngilizce izlence alınamadıysa (boş gelirse) önceki çeviri korunur.
 	_, err = db.Exec(`
-		INSERT OR REPLACE INTO course_details
-		(course_code, instructor, language, aim, content, resources, outcomes)
-		VALUES (?, ?, ?, ?, ?, ?, ?)`,
-		d.BaseInfo.Code, d.Instructor, d.Language, d.Aim, d.Content, d.Resources, string(outcomesJSON),
+		INSERT INTO course_details
+		(course_code, instructor, language, aim, content, resources, outcomes,
+		 aim_en, content_en, resources_en, outcomes_en)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(course_code) DO UPDATE SET
+			instructor = excluded.instructor,
+			language = excluded.language,
+			aim = excluded.aim,
+			content = excluded.content,
+			resources = excluded.resources,
+			outcomes = excluded.outcomes,
+			aim_en = COALESCE(NULLIF(excluded.aim_en, ''), aim_en),
+			content_en = COALESCE(NULLIF(excluded.content_en, ''), content_en),
+			resources_en = COALESCE(NULLIF(excluded.resources_en, ''), resources_en),
+			outcomes_en = COALESCE(NULLIF(excluded.outcomes_en, ''), outcomes_en)
+		WHERE instructor IS NOT excluded.instructor
+			OR language IS NOT excluded.language
+			OR aim IS NOT excluded.aim
+			OR content IS NOT excluded.content
+			OR resources IS NOT excluded.resources
+			OR outcomes IS NOT excluded.outcomes
+			OR (excluded.aim_en != '' AND aim_en IS NOT excluded.aim_en)
+			OR (excluded.content_en != '' AND content_en IS NOT excluded.content_en)
+			OR (excluded.resources_en != '' AND resources_en IS NOT excluded.resources_en)
+			OR (excluded.outcomes_en != '' AND outcomes_en IS NOT excluded.outcomes_en)`,
+		d.BaseInfo.Code, d.Instructor, d.Language, d.Aim, d.Content, d.Resources, string(outcomes),
+		d.AimEn, d.ContentEn, d.ResourcesEn, outcomesEn,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Ön koşullar değişmediyse yeniden yazılmaz; yazmak ders detayının eşitleme sürümünü artırır.
+	current, err := GetPrerequisites(db, []string{d.BaseInfo.Code})
+	if err != nil {
+		return err
+	}
+	if samePrerequisites(current[d.BaseInfo.Code], d.Prerequisites) {
+		return nil
+	}
+
+	if _, err := db.Exec("DELETE FROM course_prerequisites WHERE course_code = ?", d.BaseInfo.Code); err != nil {
+		return err
+	}
+	for _, p := range d.Prerequisites {
+		if _, err := db.Exec(`
+			INSERT OR IGNORE INTO course_prerequisites (course_code, prerequisite_code)
+			VALUES (?, ?)`, d.BaseInfo.Code, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func GetCourseDetail(db *sql.DB, courseCode string) (*models.CourseDetail, error) {
-	row := db.QueryRow(`
-		SELECT
-			c.course_code, c.course_name, c.credit, c.ects, c.is_mandatory,
-			c.theory_hours, c.practice_hours, c.lab_hours,
-			c.semester, c.link_id, c.unit_id,
-			c.department_id, c.year, c.is_removed,
-			d.instructor, d.language, d.aim, d.content, d.resources, d.outcomes
+func GetCourseDetail(db DB, courseCode string) (*models.CourseDetail, error) {
+	var detail models.CourseDetail
+	var outcomes outcomesJSON
+	err := db.QueryRow(`
+		SELECT `+courseColumns("c.")+`, `+detailColumns("d.")+`
 		FROM courses c
 		JOIN course_details d ON c.course_code = d.course_code
 		WHERE c.course_code = ?
 		ORDER BY c.year DESC, c.is_removed ASC
 		LIMIT 1`,
 		courseCode,
-	)
+	).Scan(append(courseFields(&detail.BaseInfo), detailFields(&detail, &outcomes)...)...)
+	if err != nil {
+		// Ders ya da detayı yoksa sql.ErrNoRows döner.
+		return nil, err
+	}
 
-	var detail models.CourseDetail
-	var outcomesJSON string
-
-	err := row.Scan(
-		&detail.BaseInfo.Code,
-		&detail.BaseInfo.Name,
-		&detail.BaseInfo.Credit,
-		&detail.BaseInfo.ECTS,
-		&detail.BaseInfo.IsMandatory,
-		&detail.BaseInfo.Theory,
-		&detail.BaseInfo.Practice,
-		&detail.BaseInfo.Lab,
-		&detail.BaseInfo.Semester,
-		&detail.BaseInfo.LinkID,
-		&detail.BaseInfo.UnitID,
-		&detail.BaseInfo.DepartmentID,
-		&detail.BaseInfo.Year,
-		&detail.BaseInfo.IsRemoved,
-		&detail.Instructor,
-		&detail.Language,
-		&detail.Aim,
-		&detail.Content,
-		&detail.Resources,
-		&outcomesJSON,
-	)
+	outcomes.decode(&detail)
+
+	prerequisites, err := GetPrerequisites(db, []string{courseCode})
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("ders bulunamadı ya da ders detayı mevcut değil")
+		return nil, err
+	}
+	detail.Prerequisites = prerequisites[courseCode]
+	if detail.Prerequisites == nil {
+		detail.Prerequisites = []string{}
+	}
+
+	return &detail, nil
+}
+
+// Kayıtlı ön koşullar kod sırasına göre gelir, yeni liste sırası farklı olabilir.
+func samePrerequisites(stored, incoming []string) bool {
+	set := make(map[string]bool, len(incoming))
+	for _, p := range incoming {
+		set[p] = true
+	}
+	if len(set) != len(stored) {
+		return false
+	}
+	for _, p := range stored {
+		if !set[p] {
+			return false
 		}
+	}
+	return true
+}
+
+// Ders kodu -> ön koşul kodları
+func GetPrerequisites(db DB, codes []string) (map[string][]string, error) {
+	result := make(map[string][]string)
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(codes)), ",")
+	args := make([]interface{}, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+
+	rows, err := db.Query(`
+		SELECT course_code, prerequisite_code
+		FROM course_prerequisites
+		WHERE course_code IN (`+placeholders+`)
+		ORDER BY course_code, prerequisite_code`, args...)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	_ = json.Unmarshal([]byte(outcomesJSON), &detail.Outcomes)
+	for rows.Next() {
+		var code, prerequisite string
+		if err := rows.Scan(&code, &prerequisite); err != nil {
+			return nil, err
+		}
+		result[code] = append(result[code], prerequisite)
+	}
+	return result, nil
+}
 
-	return &detail, nil
+// Verilen kodlara sahip dersleri bölümden bağımsız döner. Aynı kod birden fazla bölümde
+// varsa en güncel kayıt alınır.
+func GetCoursesByCodes(db DB, codes []string) (map[string]models.Course, error) {
+	result := make(map[string]models.Course)
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(codes)), ",")
+	args := make([]interface{}, len(codes))
+	for i, code := range codes {
+		args[i] = code
+	}
+
+	rows, err := db.Query(`
+		SELECT
+			`+courseColumns("")+`
+		FROM courses
+		WHERE course_code IN (`+placeholders+`)
+		ORDER BY year ASC, is_removed DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Course
+		if err := rows.Scan(courseFields(&c)...); err != nil {
+			return nil, err
+		}
+		result[c.Code] = c
+	}
+	return result, nil
 }
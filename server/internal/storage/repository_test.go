@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestGetCoursesWithValidDetails(t *testing.T) {
+	db := testDB(t)
+	for _, d := range []models.CourseDetail{
+		{BaseInfo: models.Course{Code: "MAT101"}, Aim: "Analiz", Prerequisites: []string{}},
+		{BaseInfo: models.Course{Code: "MAT102"}, Aim: "Analiz II", Prerequisites: []string{"MAT101"}},
+		{BaseInfo: models.Course{Code: "FIZ101"}},
+	} {
+		if err := InsertCourseDetail(db, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Sürüm kaydı olmayan izlenceler eski ayrıştırıcıyla alınmıştır.
+	valid, err := GetCoursesWithValidDetails(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(valid) != 0 {
+		t.Errorf("valid = %v, want none before marking", valid)
+	}
+
+	for _, code := range []string{"MAT101", "FIZ101"} {
+		if err := MarkDetailFetched(db, code); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO course_detail_fetches (course_code, parser_version) VALUES ('MAT102', ?)", DetailParserVersion-1); err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err = GetCoursesWithValidDetails(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// FIZ101'in içeriği yok, MAT102 eski sürümle alınmış.
+	if len(valid) != 1 || !valid["MAT101"] {
+		t.Errorf("valid = %v, want only MAT101", valid)
+	}
+
+	prerequisites, err := GetPrerequisites(db, []string{"MAT102"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prerequisites["MAT102"]; len(got) != 1 || got[0] != "MAT101" {
+		t.Errorf("prerequisites = %v", got)
+	}
+}
+
+func TestFillCourseNameEn(t *testing.T) {
+	db := testDB(t)
+	for _, c := range []models.Course{
+		{Code: "MAT101", Name: "Matematik I", NameEn: "Mathematics I"},
+		{Code: "TAR101", Name: "Tarih", Year: 2021, IsRemoved: true},
+	} {
+		if err := InsertCourse(db, c, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	missing, err := GetCoursesMissingNameEn(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 1 || !missing["TAR101"] {
+		t.Fatalf("missing = %v, want TAR101", missing)
+	}
+
+	// Dolu ad değiştirilmez.
+	for code, name := range map[string]string{"TAR101": "History", "MAT101": "Calculus"} {
+		if err := FillCourseNameEn(db, 10, code, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	courses, err := GetCoursesByDepartmentID(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range courses {
+		want := map[string]string{"MAT101": "Mathematics I", "TAR101": "History"}[c.Code]
+		if c.NameEn != want {
+			t.Errorf("%s name_en = %q, want %q", c.Code, c.NameEn, want)
+		}
+	}
+	if missing, _ := GetCoursesMissingNameEn(db, 10); len(missing) != 0 {
+		t.Errorf("missing = %v, want none", missing)
+	}
+}
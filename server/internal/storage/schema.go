@@ -1,6 +1,9 @@
 package storage
 
-import "database/sql"
+import (
+	"database/sql"
+	"fmt"
+)
 
 func CreateTables(db *sql.DB) error {
 
@@ -9,7 +12,8 @@ func CreateTables(db *sql.DB) error {
 		faculty_id INTEGER PRIMARY KEY,
 		faculty_guid TEXT,
 		faculty_name TEXT NOT NULL,
-		faculty_name_en TEXT
+		faculty_name_en TEXT,
+		row_version INTEGER NOT NULL DEFAULT 0
 	);`
 
 	departmentTable := `
@@ -19,6 +23,7 @@ func CreateTables(db *sql.DB) error {
 		department_guid TEXT,
 		department_name TEXT NOT NULL,
 		department_name_en TEXT,
+		row_version INTEGER NOT NULL DEFAULT 0,
 		FOREIGN KEY(faculty_id) REFERENCES faculties(faculty_id)
 	);`
 
@@ -27,6 +32,7 @@ func CreateTables(db *sql.DB) error {
 		course_code TEXT,
 		department_id INTEGER,
 		course_name TEXT NOT NULL,
+		course_name_en TEXT NOT NULL DEFAULT '',
 		credit REAL,
 		ects REAL,
 		is_mandatory BOOLEAN,
@@ -38,6 +44,7 @@ func CreateTables(db *sql.DB) error {
 		unit_id TEXT,
 		year INTEGER,
 		is_removed BOOLEAN DEFAULT 0,
+		row_version INTEGER NOT NULL DEFAULT 0,
 		PRIMARY KEY (course_code, department_id),
 		FOREIGN KEY(department_id) REFERENCES departments(department_id)
 	);`
@@ -50,7 +57,270 @@ func CreateTables(db *sql.DB) error {
 		aim TEXT,
 		content TEXT,
 		resources TEXT,
-		outcomes TEXT
+		outcomes TEXT,
+		aim_en TEXT NOT NULL DEFAULT '',
+		content_en TEXT NOT NULL DEFAULT '',
+		resources_en TEXT NOT NULL DEFAULT '',
+		outcomes_en TEXT NOT NULL DEFAULT '',
+		row_version INTEGER NOT NULL DEFAULT 0
+	);`
+
+	prerequisiteTable := `
+	CREATE TABLE IF NOT EXISTS course_prerequisites (
+		course_code TEXT,
+		prerequisite_code TEXT,
+		PRIMARY KEY (course_code, prerequisite_code)
+	);`
+
+	// İzlencenin hangi ayrıştırıcı sürümüyle alındığı; bkz. DetailParserVersion
+	detailFetchTable := `
+	CREATE TABLE IF NOT EXISTS course_detail_fetches (
+		course_code TEXT PRIMARY KEY,
+		parser_version INTEGER NOT NULL,
+		fetched_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	electiveGroupTable := `
+	CREATE TABLE IF NOT EXISTS elective_groups (
+		group_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		department_id INTEGER,
+		group_name TEXT NOT NULL,
+		semester TEXT,
+		required_count INTEGER DEFAULT 0,
+		required_ects REAL DEFAULT 0,
+		year INTEGER,
+		UNIQUE (department_id, group_name, semester),
+		FOREIGN KEY(department_id) REFERENCES departments(department_id)
+	);`
+
+	electiveGroupCourseTable := `
+	CREATE TABLE IF NOT EXISTS elective_group_courses (
+		group_id INTEGER,
+		course_code TEXT,
+		PRIMARY KEY (group_id, course_code),
+		FOREIGN KEY(group_id) REFERENCES elective_groups(group_id)
+	);`
+
+	equivalenceTable := `
+	CREATE TABLE IF NOT EXISTS course_equivalences (
+		course_code TEXT,
+		equivalent_code TEXT,
+		score REAL DEFAULT 0,
+		status TEXT NOT NULL DEFAULT 'candidate',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (course_code, equivalent_code)
+	);`
+
+	scrapeRunTable := `
+	CREATE TABLE IF NOT EXISTS scrape_runs (
+		run_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		started_at DATETIME NOT NULL,
+		finished_at DATETIME,
+		status TEXT NOT NULL DEFAULT 'running',
+		error TEXT
+	);`
+
+	// Tek satırlık tablo: veri her değiştiğinde version artar.
+	dataStateTable := `
+	CREATE TABLE IF NOT EXISTS data_state (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		version INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL
+	);`
+
+	// data_state'in duyurular için olanı; yeni duyurular katalog sürümünü değiştirmez.
+	announcementStateTable := `
+	CREATE TABLE IF NOT EXISTS announcement_state (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		version INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL
+	);`
+
+	// Satır sürümlerinin alındığı sayaç. Eşitleme jetonu bu sayacın değeridir.
+	syncStateTable := `
+	CREATE TABLE IF NOT EXISTS sync_state (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		seq INTEGER NOT NULL DEFAULT 0
+	);
+	INSERT OR IGNORE INTO sync_state (id, seq) VALUES (1, 0);`
+
+	// Silinen satırların istemcilere bildirilmesi için
+	syncTombstoneTable := `
+	CREATE TABLE IF NOT EXISTS sync_tombstones (
+		entity TEXT NOT NULL,
+		entity_key TEXT NOT NULL,
+		row_version INTEGER NOT NULL,
+		PRIMARY KEY (entity, entity_key)
+	);`
+
+	apiKeyTable := `
+	CREATE TABLE IF NOT EXISTS api_keys (
+		key_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		key_prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		role TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		last_used_at DATETIME,
+		revoked_at DATETIME
+	);`
+
+	// Aynı duyuru sitenin birden fazla kategorisinde görünebilir; site ve CMS id'si tekildir.
+	announcementTable := `
+	CREATE TABLE IF NOT EXISTS announcements (
+		announcement_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		site_key TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		title TEXT NOT NULL,
+		url TEXT NOT NULL DEFAULT '',
+		published_at DATETIME NOT NULL,
+		first_seen_at DATETIME NOT NULL,
+		UNIQUE (site_key, external_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_announcements_published ON announcements (published_at);`
+
+	announcementCategoryTable := `
+	CREATE TABLE IF NOT EXISTS announcement_categories (
+		announcement_id INTEGER,
+		category_id INTEGER,
+		PRIMARY KEY (announcement_id, category_id),
+		FOREIGN KEY(announcement_id) REFERENCES announcements(announcement_id)
+	);`
+
+	// Müfredat değişiklik günlüğü; tetikleyicilerle doldurulur (bkz. createChangeTriggers).
+	courseChangeTable := `
+	CREATE TABLE IF NOT EXISTS course_changes (
+		change_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		department_id INTEGER,
+		course_code TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		changed_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_course_changes_department ON course_changes (department_id);
+	CREATE INDEX IF NOT EXISTS idx_course_changes_code ON course_changes (course_code);`
+
+	// Değişiklik günlüğünü okuyanların (webhook kuyruğu, anlık bildirimler) en son işlediği değişiklik
+	changeCursorTable := `
+	CREATE TABLE IF NOT EXISTS change_cursors (
+		name TEXT PRIMARY KEY,
+		change_id INTEGER NOT NULL
+	);`
+
+	// Bildirim alan cihazlar ve abone oldukları konular (bkz. storage.DepartmentTopic)
+	deviceTable := `
+	CREATE TABLE IF NOT EXISTS devices (
+		device_id TEXT PRIMARY KEY,
+		push_token TEXT NOT NULL UNIQUE,
+		platform TEXT NOT NULL,
+		language TEXT NOT NULL DEFAULT 'tr',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);`
+
+	deviceSubscriptionTable := `
+	CREATE TABLE IF NOT EXISTS device_subscriptions (
+		device_id TEXT,
+		topic TEXT,
+		PRIMARY KEY (device_id, topic),
+		FOREIGN KEY(device_id) REFERENCES devices(device_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_device_subscriptions_topic ON device_subscriptions (topic);`
+
+	// Akademik takvim; uid takvim akışındaki kimliktir ve yeniden taramada korunur.
+	calendarTable := `
+	CREATE TABLE IF NOT EXISTS calendar_events (
+		event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		uid TEXT NOT NULL UNIQUE,
+		academic_year TEXT NOT NULL,
+		term TEXT NOT NULL DEFAULT '',
+		category TEXT NOT NULL,
+		title TEXT NOT NULL,
+		start_date TEXT NOT NULL,
+		end_date TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_calendar_events_dates ON calendar_events (start_date, end_date);`
+
+	// Entegrasyon webhook'ları. events virgülle ayrılmış olay türleridir, "*" hepsi demektir.
+	webhookTable := `
+	CREATE TABLE IF NOT EXISTS webhooks (
+		webhook_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL,
+		events TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);`
+
+	// Olaylar bir kez yazılır; her webhook için ayrı gönderim kaydı tutulur.
+	webhookEventTable := `
+	CREATE TABLE IF NOT EXISTS webhook_events (
+		event_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);`
+
+	webhookDeliveryTable := `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		delivery_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL,
+		event_id INTEGER NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		next_attempt_at DATETIME,
+		last_status_code INTEGER,
+		last_error TEXT,
+		created_at DATETIME NOT NULL,
+		delivered_at DATETIME,
+		FOREIGN KEY(webhook_id) REFERENCES webhooks(webhook_id),
+		FOREIGN KEY(event_id) REFERENCES webhook_events(event_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);`
+
+	// Bölümlerin ders programı PDF kaynağı ve son alım sonucu
+	timetableSourceTable := `
+	CREATE TABLE IF NOT EXISTS timetable_sources (
+		department_id INTEGER PRIMARY KEY,
+		page_url TEXT NOT NULL,
+		pdf_url TEXT NOT NULL DEFAULT '',
+		content_hash TEXT NOT NULL DEFAULT '',
+		fetched_at DATETIME,
+		slot_count INTEGER NOT NULL DEFAULT 0,
+		matched_count INTEGER NOT NULL DEFAULT 0,
+		last_error TEXT NOT NULL DEFAULT '',
+		FOREIGN KEY(department_id) REFERENCES departments(department_id)
+	);`
+
+	// weekday sıralama içindir (Pazartesi = 0).
+	timetableSlotTable := `
+	CREATE TABLE IF NOT EXISTS timetable_slots (
+		slot_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		department_id INTEGER NOT NULL,
+		course_code TEXT NOT NULL DEFAULT '',
+		course_name TEXT NOT NULL,
+		day TEXT NOT NULL,
+		weekday INTEGER NOT NULL,
+		start_time TEXT NOT NULL,
+		end_time TEXT NOT NULL,
+		room TEXT NOT NULL DEFAULT '',
+		instructor TEXT NOT NULL DEFAULT '',
+		semester TEXT NOT NULL DEFAULT '',
+		section TEXT NOT NULL DEFAULT '',
+		match_score REAL NOT NULL DEFAULT 0,
+		FOREIGN KEY(department_id) REFERENCES departments(department_id)
+	);
+	CREATE INDEX IF NOT EXISTS idx_timetable_slots_department ON timetable_slots (department_id);
+	CREATE INDEX IF NOT EXISTS idx_timetable_slots_code ON timetable_slots (course_code);`
+
+	// Kişisel ders programı takvimleri; adresteki anahtarın sadece özeti saklanır.
+	timetableFeedTable := `
+	CREATE TABLE IF NOT EXISTS timetable_feeds (
+		feed_id INTEGER PRIMARY KEY AUTOINCREMENT,
+		token_hash TEXT NOT NULL UNIQUE,
+		department_id INTEGER,
+		courses TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		last_used_at DATETIME,
+		FOREIGN KEY(department_id) REFERENCES departments(department_id)
 	);`
 
 	if _, err := db.Exec(facultyTable); err != nil {
@@ -65,6 +335,135 @@ func CreateTables(db *sql.DB) error {
 	if _, err := db.Exec(courseDetailTable); err != nil {
 		return err
 	}
+	if _, err := db.Exec(prerequisiteTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(detailFetchTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(electiveGroupTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(electiveGroupCourseTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(equivalenceTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(scrapeRunTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(dataStateTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(announcementStateTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(syncStateTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(syncTombstoneTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(apiKeyTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(courseChangeTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(changeCursorTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(announcementTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(announcementCategoryTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(deviceTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(deviceSubscriptionTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(calendarTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(webhookTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(webhookEventTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(webhookDeliveryTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(timetableSourceTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(timetableSlotTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(timetableFeedTable); err != nil {
+		return err
+	}
+
+	// Eski veritabanlarında row_version kolonu yok.
+	for _, table := range []string{"faculties", "departments", "courses", "course_details"} {
+		if err := addColumnIfMissing(db, table, "row_version", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+	// İngilizce içerik kolonları sonradan eklendi.
+	if err := addColumnIfMissing(db, "courses", "course_name_en", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	for _, column := range []string{"aim_en", "content_en", "resources_en", "outcomes_en"} {
+		if err := addColumnIfMissing(db, "course_details", column, "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return err
+		}
+	}
+	// Kullanılmayan akışların silinmesi için sonradan eklendi; boşsa created_at geçerlidir.
+	if err := addColumnIfMissing(db, "timetable_feeds", "last_used_at", "DATETIME"); err != nil {
+		return err
+	}
+	if err := createSyncTriggers(db); err != nil {
+		return err
+	}
+	if err := createChangeTriggers(db); err != nil {
+		return err
+	}
 
 	return nil
 }
+
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, kind string
+			notNull    bool
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"context"
 	"database/sql"
 	"log"
 
@@ -19,3 +20,45 @@ func OpenDB() *sql.DB {
 
 	return db
 }
+
+// Depo fonksiyonlarının kullandığı bağlantı. *sql.DB doğrudan, istek bağlamına bağlı
+// bağlantı ise WithContext ile elde edilir.
+type DB interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Begin() (*sql.Tx, error)
+}
+
+// DB'nin işlem (*sql.Tx) içinde de kullanılabilen kısmı
+type execer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+type ctxDB struct {
+	ctx context.Context
+	db  *sql.DB
+}
+
+// Sorguları verilen bağlama bağlar; bağlam iptal edildiğinde ya da süresi dolduğunda
+// çalışan sorgu da durdurulur.
+func WithContext(ctx context.Context, db *sql.DB) DB {
+	return ctxDB{ctx: ctx, db: db}
+}
+
+func (c ctxDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(c.ctx, query, args...)
+}
+
+func (c ctxDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(c.ctx, query, args...)
+}
+
+func (c ctxDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(c.ctx, query, args...)
+}
+
+func (c ctxDB) Begin() (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, nil)
+}
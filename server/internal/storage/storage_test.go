@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+// Tabloları kurulmuş, tek bölümlü bellek içi veritabanı
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Bellek içi veritabanı bağlantıya özeldir.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if err := CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertFaculty(db, models.Faculty{ID: 1, GUID: "f1", Name: "Mühendislik"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := InsertDepartment(db, models.Department{ID: 10, FacultyID: 1, GUID: "d1", Name: "Bilgisayar"}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestReplaceElectiveGroups(t *testing.T) {
+	db := testDB(t)
+	for _, c := range []models.Course{
+		{Code: "SEC101", Name: "Felsefe", Semester: "1. Yarıyıl"},
+		{Code: "SEC102", Name: "Sanat", Semester: "1. Yarıyıl"},
+		{Code: "TEK301", Name: "Robotik", Semester: "5. Yarıyıl"},
+	} {
+		if err := InsertCourse(db, c, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := ReplaceElectiveGroups(db, 10, []models.ElectiveGroup{
+		{Name: "Seçmeli I", Semester: "1. Yarıyıl", RequiredCount: 1, CourseCodes: []string{"SEC101", "SEC102"}},
+		{Name: "Teknik Seçmeli", Semester: "5. Yarıyıl", RequiredCount: 1, CourseCodes: []string{"TEK301"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err := GetElectiveGroupsByDepartmentID(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("groups = %+v, want 2", groups)
+	}
+	firstID := groups[0].ID
+
+	// Teknik seçmeli müfredattan kalktı, Seçmeli I'den bir ders çıktı.
+	err = ReplaceElectiveGroups(db, 10, []models.ElectiveGroup{
+		{Name: "Seçmeli I", Semester: "1. Yarıyıl", RequiredCount: 2, CourseCodes: []string{"SEC102"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	groups, err = GetElectiveGroupsByDepartmentID(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("groups = %+v, want 1", groups)
+	}
+	g := groups[0]
+	if g.ID != firstID || g.RequiredCount != 2 || len(g.CourseCodes) != 1 || g.CourseCodes[0] != "SEC102" {
+		t.Errorf("group = %+v", g)
+	}
+
+	var members int
+	if err := db.QueryRow("SELECT COUNT(*) FROM elective_group_courses").Scan(&members); err != nil {
+		t.Fatal(err)
+	}
+	if members != 1 {
+		t.Errorf("elective_group_courses rows = %d, want 1", members)
+	}
+}
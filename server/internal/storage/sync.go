@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+// Eşitlemeye dahil tablolar: tablo adı, silinen kaydın anahtar ifadesi (ROW, NEW/OLD ile
+// değiştirilir) ve değişimi sürüm artıran veri kolonları.
+var syncedTables = []struct {
+	table   string
+	key     string
+	columns string
+}{
+	{"faculties", "ROW.faculty_id", "faculty_guid, faculty_name, faculty_name_en"},
+	{"departments", "ROW.department_id", "faculty_id, department_guid, department_name, department_name_en"},
+	{"courses", "ROW.course_code || ':' || ROW.department_id",
+		"course_name, course_name_en, credit, ects, is_mandatory, theory_hours, practice_hours, lab_hours, semester, link_id, unit_id, year, is_removed"},
+	{"course_details", "ROW.course_code", "instructor, language, aim, content, resources, outcomes, aim_en, content_en, resources_en, outcomes_en"},
+}
+
+const nextSyncVersion = "UPDATE sync_state SET seq = seq + 1 WHERE id = 1;"
+const currentSyncVersion = "(SELECT seq FROM sync_state WHERE id = 1)"
+
+// Satır sürümleri tetikleyicilerle tutulur; böylece tablolara yazan her kod yolu kapsanır.
+func createSyncTriggers(db *sql.DB) error {
+	for _, t := range syncedTables {
+		triggers := []string{
+			fmt.Sprintf(`
+			CREATE TRIGGER IF NOT EXISTS %[1]s_sync_insert AFTER INSERT ON %[1]s BEGIN
+				%[3]s
+				UPDATE %[1]s SET row_version = %[4]s WHERE rowid = NEW.rowid;
+				DELETE FROM sync_tombstones WHERE entity = '%[1]s' AND entity_key = %[2]s;
+			END;`, t.table, strings.ReplaceAll(t.key, "ROW.", "NEW."), nextSyncVersion, currentSyncVersion),
+			// Kolon listesi değişebildiği için güncelleme tetikleyicisi her açılışta yeniden kurulur.
+			fmt.Sprintf(`DROP TRIGGER IF EXISTS %s_sync_update;`, t.table),
+			fmt.Sprintf(`
+			CREATE TRIGGER %[1]s_sync_update AFTER UPDATE OF %[2]s ON %[1]s BEGIN
+				%[3]s
+				UPDATE %[1]s SET row_version = %[4]s WHERE rowid = NEW.rowid;
+			END;`, t.table, t.columns, nextSyncVersion, currentSyncVersion),
+			fmt.Sprintf(`
+			CREATE TRIGGER IF NOT EXISTS %[1]s_sync_delete AFTER DELETE ON %[1]s BEGIN
+				%[3]s
+				INSERT OR REPLACE INTO sync_tombstones (entity, entity_key, row_version)
+				VALUES ('%[1]s', %[2]s, %[4]s);
+			END;`, t.table, strings.ReplaceAll(t.key, "ROW.", "OLD."), nextSyncVersion, currentSyncVersion),
+		}
+		for _, trigger := range triggers {
+			if _, err := db.Exec(trigger); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Ön koşullar ders detayının parçası olarak eşitlenir.
+	for _, event := range []struct{ name, row string }{{"insert", "NEW"}, {"delete", "OLD"}} {
+		trigger := fmt.Sprintf(`
+		CREATE TRIGGER IF NOT EXISTS course_prerequisites_sync_%[1]s AFTER %[2]s ON course_prerequisites BEGIN
+			%[3]s
+			UPDATE course_details SET row_version = %[4]s WHERE course_code = %[5]s.course_code;
+		END;`, event.name, event.name, nextSyncVersion, currentSyncVersion, event.row)
+		if _, err := db.Exec(trigger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func currentSyncSeq(db execer) (int64, error) {
+	var seq int64
+	err := db.QueryRow("SELECT seq FROM sync_state WHERE id = 1").Scan(&seq)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return seq, err
+}
+
+// Eşitleme okuması. Jeton ve bütün kayıt sorguları tek bir okuma işleminde (BEGIN…COMMIT)
+// çalışır; böylece hepsi aynı anlık görüntüden okunur. Kayıtlar bölüm bölüm Cursor ile
+// okunur, tam eşitleme de belleğe alınmaz. Bir bölümün cursor'ı sonrakinden önce
+// kapatılmalı, okuma bitince Close çağrılmalıdır.
+type SyncReader struct {
+	// Sonraki eşitlemede gönderilecek jeton
+	Token string
+	// Jeton geçersizse (0 ya da sunucudaki sayaçtan büyük) tüm veri döner.
+	Full  bool
+	tx    *sql.Tx
+	since int64
+}
+
+// since jetonundan sonra değişen kayıtlar için okuma işlemi başlatır.
+func BeginSync(db DB, since int64) (*SyncReader, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	seq, err := currentSyncSeq(tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	s := &SyncReader{Token: strconv.FormatInt(seq, 10), tx: tx, since: since}
+	if since <= 0 || since > seq {
+		s.Full = true
+		s.since = 0
+	}
+	return s, nil
+}
+
+// Okuma işlemini bitirir.
+func (s *SyncReader) Close() error {
+	return s.tx.Commit()
+}
+
+func syncCursor[T any](s *SyncReader, query string, scan func(*sql.Rows) (T, error)) (*Cursor[T], error) {
+	rows, err := s.tx.Query(query, s.since, s.since)
+	if err != nil {
+		return nil, err
+	}
+	return &Cursor[T]{rows: rows, scan: scan}, nil
+}
+
+func (s *SyncReader) Faculties() (*Cursor[models.Faculty], error) {
+	return syncCursor(s, `
+		SELECT faculty_id, faculty_guid, faculty_name, faculty_name_en
+		FROM faculties WHERE row_version > ? OR ? = 0
+		ORDER BY faculty_id`, func(rows *sql.Rows) (models.Faculty, error) {
+		var f models.Faculty
+		err := rows.Scan(&f.ID, &f.GUID, &f.Name, &f.NameEn)
+		return f, err
+	})
+}
+
+func (s *SyncReader) Departments() (*Cursor[models.Department], error) {
+	return syncCursor(s, `
+		SELECT department_id, faculty_id, department_guid, department_name, department_name_en
+		FROM departments WHERE row_version > ? OR ? = 0
+		ORDER BY department_id`, func(rows *sql.Rows) (models.Department, error) {
+		var d models.Department
+		err := rows.Scan(&d.ID, &d.FacultyID, &d.GUID, &d.Name, &d.NameEn)
+		return d, err
+	})
+}
+
+func (s *SyncReader) Courses() (*Cursor[models.Course], error) {
+	return syncCursor(s, `
+		SELECT
+			`+courseColumns("")+`
+		FROM courses WHERE row_version > ? OR ? = 0
+		ORDER BY department_id, course_code`, func(rows *sql.Rows) (models.Course, error) {
+		var c models.Course
+		err := rows.Scan(courseFields(&c)...)
+		return c, err
+	})
+}
+
+// Ders detayları ön koşullarıyla birlikte döner; ön koşullar satır başına JSON dizisi olarak okunur.
+func (s *SyncReader) Details() (*Cursor[models.CourseDetail], error) {
+	return syncCursor(s, `
+		SELECT d.course_code, `+detailColumns("d.")+`,
+			(SELECT json_group_array(prerequisite_code) FROM (
+				SELECT prerequisite_code FROM course_prerequisites p
+				WHERE p.course_code = d.course_code
+				ORDER BY prerequisite_code))
+		FROM course_details d WHERE d.row_version > ? OR ? = 0
+		ORDER BY d.course_code`, func(rows *sql.Rows) (models.CourseDetail, error) {
+		var d models.CourseDetail
+		var outcomes outcomesJSON
+		var prerequisites string
+		fields := append([]interface{}{&d.BaseInfo.Code}, detailFields(&d, &outcomes)...)
+		if err := rows.Scan(append(fields, &prerequisites)...); err != nil {
+			return d, err
+		}
+		outcomes.decode(&d)
+		d.Prerequisites = []string{}
+		err := json.Unmarshal([]byte(prerequisites), &d.Prerequisites)
+		return d, err
+	})
+}
+
+// Jetondan sonra silinen kayıtlar; tam eşitlemede boştur.
+func (s *SyncReader) Removed() (*Cursor[models.SyncRemoval], error) {
+	return syncCursor(s, `
+		SELECT entity, entity_key FROM sync_tombstones
+		WHERE row_version > ? AND ? != 0
+		ORDER BY row_version`, func(rows *sql.Rows) (models.SyncRemoval, error) {
+		var r models.SyncRemoval
+		err := rows.Scan(&r.Type, &r.Key)
+		return r, err
+	})
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+// Okuyucunun bütün bölümlerini toplar.
+func readSync(t *testing.T, db DB, since int64) *models.SyncChanges {
+	t.Helper()
+	s, err := BeginSync(db, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	changes := &models.SyncChanges{Token: s.Token, Full: s.Full}
+	if changes.Faculties, _, err = collect(s.Faculties()); err != nil {
+		t.Fatal(err)
+	}
+	if changes.Departments, _, err = collect(s.Departments()); err != nil {
+		t.Fatal(err)
+	}
+	if changes.Courses, _, err = collect(s.Courses()); err != nil {
+		t.Fatal(err)
+	}
+	if changes.Details, _, err = collect(s.Details()); err != nil {
+		t.Fatal(err)
+	}
+	if changes.Removed, _, err = collect(s.Removed()); err != nil {
+		t.Fatal(err)
+	}
+	return changes
+}
+
+func TestSyncReader(t *testing.T) {
+	db := testDB(t)
+	for _, code := range []string{"MAT101", "MAT102", "FIZ101"} {
+		if err := InsertCourse(db, models.Course{Code: code, Name: code}, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := InsertCourseDetail(db, models.CourseDetail{BaseInfo: models.Course{Code: "MAT102"}, Aim: "Analiz",
+		Outcomes: []string{"türev"}, Prerequisites: []string{"MAT101", "FIZ101"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	full := readSync(t, db, 0)
+	if !full.Full || len(full.Faculties) != 1 || len(full.Departments) != 1 || len(full.Courses) != 3 || len(full.Removed) != 0 {
+		t.Fatalf("full sync = %+v", full)
+	}
+	if len(full.Details) != 1 || !reflect.DeepEqual(full.Details[0].Prerequisites, []string{"FIZ101", "MAT101"}) ||
+		!reflect.DeepEqual(full.Details[0].Outcomes, []string{"türev"}) {
+		t.Errorf("details = %+v", full.Details)
+	}
+
+	token, _ := strconv.ParseInt(full.Token, 10, 64)
+	if again := readSync(t, db, token); again.Full || len(again.Courses)+len(again.Details)+len(again.Removed) != 0 {
+		t.Errorf("no changes: %+v", again)
+	}
+
+	if err := InsertCourse(db, models.Course{Code: "MAT101", Name: "Matematik I"}, 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("DELETE FROM courses WHERE course_code = 'FIZ101'"); err != nil {
+		t.Fatal(err)
+	}
+	delta := readSync(t, db, token)
+	if delta.Full || len(delta.Courses) != 1 || delta.Courses[0].Name != "Matematik I" {
+		t.Errorf("changed courses = %+v", delta.Courses)
+	}
+	if want := []models.SyncRemoval{{Type: "courses", Key: "FIZ101:10"}}; !reflect.DeepEqual(delta.Removed, want) {
+		t.Errorf("removed = %+v, want %+v", delta.Removed, want)
+	}
+
+	// Sunucudaki sayaçtan büyük jeton tam eşitlemeye döner.
+	if reset := readSync(t, db, token+1000); !reset.Full || len(reset.Courses) != 2 {
+		t.Errorf("unknown token: full = %v, courses = %d", reset.Full, len(reset.Courses))
+	}
+}
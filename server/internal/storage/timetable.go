@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Bölümün ders programı kaynağını ayarlar. Sayfa değişirse önceki alım bilgisi silinir.
+func SetTimetableSource(db DB, departmentID int, pageURL string) error {
+	_, err := db.Exec(`
+		INSERT INTO timetable_sources (department_id, page_url)
+		VALUES (?, ?)
+		ON CONFLICT(department_id) DO UPDATE SET
+			page_url = excluded.page_url,
+			pdf_url = '',
+			content_hash = '',
+			last_error = ''
+		WHERE page_url != excluded.page_url`,
+		departmentID, pageURL,
+	)
+	return err
+}
+
+// Keşfedilen kaynağı ekler. Bölümün kaynağı zaten varsa (ör. elle ayarlandıysa)
+// dokunulmaz ve false döner.
+func AddTimetableSource(db DB, departmentID int, pageURL string) (bool, error) {
+	res, err := db.Exec(`
+		INSERT INTO timetable_sources (department_id, page_url)
+		VALUES (?, ?)
+		ON CONFLICT(department_id) DO NOTHING`,
+		departmentID, pageURL,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Ders programı kaynağı olmayan bölümler
+func GetDepartmentsWithoutTimetableSource(db DB) ([]models.Department, error) {
+	rows, err := db.Query(`
+		SELECT d.department_id, d.faculty_id, d.department_guid, d.department_name, d.department_name_en
+		FROM departments d
+		LEFT JOIN timetable_sources s ON s.department_id = d.department_id
+		WHERE s.department_id IS NULL
+		ORDER BY d.department_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var departments []models.Department
+	for rows.Next() {
+		var d models.Department
+		if err := rows.Scan(&d.ID, &d.FacultyID, &d.GUID, &d.Name, &d.NameEn); err != nil {
+			return nil, err
+		}
+		departments = append(departments, d)
+	}
+	return departments, rows.Err()
+}
+
+func GetTimetableSources(db DB) ([]models.TimetableSource, error) {
+	rows, err := db.Query(`
+		SELECT s.department_id, COALESCE(d.department_guid, ''), s.page_url, s.pdf_url, s.content_hash,
+			s.fetched_at, s.slot_count, s.matched_count, s.last_error
+		FROM timetable_sources s
+		LEFT JOIN departments d ON d.department_id = s.department_id
+		ORDER BY s.department_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []models.TimetableSource
+	for rows.Next() {
+		var s models.TimetableSource
+		var fetched sql.NullTime
+		if err := rows.Scan(&s.DepartmentID, &s.DepartmentGUID, &s.PageURL, &s.PDFURL, &s.ContentHash,
+			&fetched, &s.SlotCount, &s.MatchedCount, &s.LastError); err != nil {
+			return nil, err
+		}
+		if fetched.Valid {
+			s.FetchedAt = &fetched.Time
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// Alım sonucunu kaydeder. Hata varsa önceki PDF bilgisi ve sayılar korunur.
+func RecordTimetableFetch(db DB, src models.TimetableSource, fetchErr error) error {
+	if fetchErr != nil {
+		_, err := db.Exec(
+			"UPDATE timetable_sources SET fetched_at = ?, last_error = ? WHERE department_id = ?",
+			time.Now().UTC(), fetchErr.Error(), src.DepartmentID,
+		)
+		return err
+	}
+	_, err := db.Exec(`
+		UPDATE timetable_sources
+		SET pdf_url = ?, content_hash = ?, fetched_at = ?, slot_count = ?, matched_count = ?, last_error = ''
+		WHERE department_id = ?`,
+		src.PDFURL, src.ContentHash, time.Now().UTC(), src.SlotCount, src.MatchedCount, src.DepartmentID,
+	)
+	return err
+}
+
+// Bölümün ders programını verilen satırlarla değiştirir. Program değiştiyse true döner;
+// aynı PDF'in yeniden alınması satır kimliklerini değiştirmez.
+func ReplaceTimetableSlots(db DB, departmentID int, slots []models.TimetableSlot) (bool, error) {
+	current, _, err := ListTimetableSlots(db, TimetableQuery{DepartmentID: departmentID})
+	if err != nil {
+		return false, err
+	}
+	if sameSlots(current, slots) {
+		return false, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM timetable_slots WHERE department_id = ?", departmentID); err != nil {
+		return false, err
+	}
+	stmt, err := tx.Prepare(`
+		INSERT INTO timetable_slots
+		(department_id, course_code, course_name, day, weekday, start_time, end_time,
+		 room, instructor, semester, section, match_score)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return false, err
+	}
+	defer stmt.Close()
+
+	for _, s := range slots {
+		if _, err := stmt.Exec(departmentID, s.CourseCode, s.CourseName, s.Day, weekday(s.Day), s.Start, s.End,
+			s.Room, s.Instructor, s.Semester, s.Section, s.MatchScore); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+func sameSlots(a, b []models.TimetableSlot) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	key := func(s models.TimetableSlot) models.TimetableSlot {
+		s.ID, s.DepartmentID = 0, 0
+		return s
+	}
+	seen := make(map[models.TimetableSlot]int)
+	for _, s := range a {
+		seen[key(s)]++
+	}
+	for _, s := range b {
+		k := key(s)
+		if seen[k] == 0 {
+			return false
+		}
+		seen[k]--
+	}
+	return true
+}
+
+var timetableDays = []string{"Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi", "Pazar"}
+
+func weekday(day string) int {
+	for i, d := range timetableDays {
+		if d == day {
+			return i
+		}
+	}
+	return len(timetableDays)
+}
+
+var TimetableSortFields = map[string]string{
+	"day":         "weekday",
+	"start":       "start_time",
+	"course_code": "course_code",
+	"course_name": "course_name",
+	"semester":    "semester",
+}
+
+type TimetableQuery struct {
+	ListOptions
+	DepartmentID int
+	Semester     string
+	Day          string
+	// nil değilse sadece bu derslerin saatleri döner.
+	CourseCodes []string
+}
+
+func ListTimetableSlots(db DB, q TimetableQuery) ([]models.TimetableSlot, int, error) {
+	return collect(QueryTimetableSlots(db, q))
+}
+
+func QueryTimetableSlots(db DB, q TimetableQuery) (*Cursor[models.TimetableSlot], error) {
+	where := &whereBuilder{}
+	if q.DepartmentID != 0 {
+		where.add("department_id = ?", q.DepartmentID)
+	}
+	if q.Semester != "" {
+		where.add("semester = ?", q.Semester)
+	}
+	if q.Day != "" {
+		where.add("day = ?", q.Day)
+	}
+	if q.CourseCodes != nil {
+		where.inStrings("course_code", q.CourseCodes)
+	}
+
+	order, err := orderClause(q.Sort, TimetableSortFields, "semester, weekday, start_time, section, slot_id")
+	if err != nil {
+		return nil, err
+	}
+
+	const from = "FROM timetable_slots"
+	total, err := countRows(db, from, where)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, limitArgs := limitClause(q.ListOptions)
+	rows, err := db.Query(`
+		SELECT slot_id, department_id, course_code, course_name, day, start_time, end_time,
+			room, instructor, semester, section, match_score
+		`+from+where.String()+order+limit,
+		append(where.args, limitArgs...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cursor[models.TimetableSlot]{Total: total, rows: rows, scan: func(rows *sql.Rows) (models.TimetableSlot, error) {
+		var s models.TimetableSlot
+		err := rows.Scan(&s.ID, &s.DepartmentID, &s.CourseCode, &s.CourseName, &s.Day, &s.Start, &s.End,
+			&s.Room, &s.Instructor, &s.Semester, &s.Section, &s.MatchScore)
+		return s, err
+	}}, nil
+}
+
+const timetableFeedPrefix = "itf_"
+
+// Kişisel takvim akışı oluşturur. Adres anahtarı sadece burada döner; veritabanında
+// API anahtarları gibi özeti saklanır. departmentID 0 ise bütün bölümlerin programı kullanılır.
+func CreateTimetableFeed(db DB, departmentID int, courses []models.CourseSelection) (*models.TimetableFeed, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(courses)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &models.TimetableFeed{
+		Token:        timetableFeedPrefix + base64.RawURLEncoding.EncodeToString(raw),
+		DepartmentID: departmentID,
+		Courses:      courses,
+		CreatedAt:    time.Now().UTC(),
+	}
+	_, err = db.Exec(`
+		INSERT INTO timetable_feeds (token_hash, department_id, courses, created_at, last_used_at)
+		VALUES (?, NULLIF(?, 0), ?, ?, ?)`,
+		HashAPIKey(f.Token), departmentID, string(encoded), f.CreatedAt, f.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Akışın kullanım zamanı en fazla bu sıklıkla güncellenir; takvim uygulamaları akışı
+// saatte birkaç kez okuyabilir.
+const feedTouchInterval = 24 * time.Hour
+
+// Anahtara ait akışı döner; yoksa sql.ErrNoRows. Akışın son kullanım zamanı da güncellenir
+// (bkz. DeleteUnusedTimetableFeeds).
+func GetTimetableFeed(db DB, token string) (*models.TimetableFeed, error) {
+	var f models.TimetableFeed
+	var courses string
+	err := db.QueryRow(`
+		SELECT COALESCE(f.department_id, 0), COALESCE(d.department_guid, ''), f.courses, f.created_at
+		FROM timetable_feeds f
+		LEFT JOIN departments d ON d.department_id = f.department_id
+		WHERE f.token_hash = ?`, HashAPIKey(token),
+	).Scan(&f.DepartmentID, &f.DepartmentGUID, &courses, &f.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(courses), &f.Courses); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := db.Exec(`
+		UPDATE timetable_feeds SET last_used_at = ?
+		WHERE token_hash = ? AND COALESCE(last_used_at, created_at) < ?`,
+		now, HashAPIKey(token), now.Add(-feedTouchInterval),
+	); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// before'dan beri okunmamış akışları siler; silinen akış sayısını döner.
+func DeleteUnusedTimetableFeeds(db DB, before time.Time) (int64, error) {
+	res, err := db.Exec("DELETE FROM timetable_feeds WHERE COALESCE(last_used_at, created_at) < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// Akışı siler; adres bir daha çalışmaz. Akış yoksa false döner.
+func DeleteTimetableFeed(db DB, token string) (bool, error) {
+	res, err := db.Exec("DELETE FROM timetable_feeds WHERE token_hash = ?", HashAPIKey(token))
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+func TestDeleteUnusedTimetableFeeds(t *testing.T) {
+	db := testDB(t)
+	courses := []models.CourseSelection{{Code: "MAT101"}}
+	used, err := CreateTimetableFeed(db, 10, courses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused, err := CreateTimetableFeed(db, 10, courses)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().UTC().Add(-100 * 24 * time.Hour)
+	if _, err := db.Exec("UPDATE timetable_feeds SET created_at = ?, last_used_at = ?", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Okunan akışın kullanım zamanı yenilenir.
+	if _, err := GetTimetableFeed(db, used.Token); err != nil {
+		t.Fatal(err)
+	}
+	n, err := DeleteUnusedTimetableFeeds(db, time.Now().Add(-90*24*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("deleted = %d, %v; want 1", n, err)
+	}
+	if _, err := GetTimetableFeed(db, used.Token); err != nil {
+		t.Errorf("used feed: %v", err)
+	}
+	if _, err := GetTimetableFeed(db, unused.Token); err != sql.ErrNoRows {
+		t.Errorf("unused feed: err = %v, want sql.ErrNoRows", err)
+	}
+}
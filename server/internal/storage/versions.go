@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+// Verinin o anki sürümü. Tarama ya da yönetici düzeltmesi verileri değiştirdiğinde artar.
+type DataVersion struct {
+	Version   int64
+	UpdatedAt time.Time
+}
+
+// Ayrı sürümlenen veri grubu; değeri sürümün tutulduğu tablodur.
+type VersionScope string
+
+const (
+	// Fakülte, bölüm, ders, takvim ve ders programı
+	CatalogVersion VersionScope = "data_state"
+	// Duyurular sık değiştiği için katalog ETag'lerini ve paketlerini geçersiz kılmaz.
+	AnnouncementVersion VersionScope = "announcement_state"
+)
+
+func GetVersion(db DB, scope VersionScope) (DataVersion, error) {
+	var v DataVersion
+	err := db.QueryRow("SELECT version, updated_at FROM "+string(scope)+" WHERE id = 1").Scan(&v.Version, &v.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return DataVersion{}, nil
+	}
+	return v, err
+}
+
+func BumpVersion(db DB, scope VersionScope) (DataVersion, error) {
+	_, err := db.Exec(`
+		INSERT INTO `+string(scope)+` (id, version, updated_at) VALUES (1, 1, ?)
+		ON CONFLICT(id) DO UPDATE SET version = version + 1, updated_at = excluded.updated_at`,
+		time.Now().UTC().Truncate(time.Second),
+	)
+	if err != nil {
+		return DataVersion{}, err
+	}
+	return GetVersion(db, scope)
+}
+
+func GetDataVersion(db DB) (DataVersion, error) {
+	return GetVersion(db, CatalogVersion)
+}
+
+func BumpDataVersion(db DB) (DataVersion, error) {
+	return BumpVersion(db, CatalogVersion)
+}
+
+func StartScrapeRun(db DB) (int64, error) {
+	res, err := db.Exec("INSERT INTO scrape_runs (started_at, status) VALUES (?, ?)",
+		time.Now().UTC(), models.ScrapeRunning)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// Taramayı bitirir ve scrape.completed olayını yayar. Başarılı tarama veriyi
+// değiştirdiği için veri sürümü de artırılır.
+func FinishScrapeRun(db DB, runID int64, runErr error) error {
+	run := models.ScrapeRun{ID: runID, Status: models.ScrapeSucceeded}
+	if runErr != nil {
+		run.Status, run.Error = models.ScrapeFailed, runErr.Error()
+	}
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+
+	if err := db.QueryRow(`
+		UPDATE scrape_runs SET finished_at = ?, status = ?, error = ?
+		WHERE run_id = ?
+		RETURNING started_at`, finished, run.Status, run.Error, runID).Scan(&run.StartedAt); err != nil {
+		return err
+	}
+	if _, err := EnqueueEvent(db, models.EventScrapeCompleted, run); err != nil {
+		return err
+	}
+
+	if runErr == nil {
+		_, err := BumpDataVersion(db)
+		return err
+	}
+	return nil
+}
+
+func GetScrapeRuns(db DB, limit int) ([]models.ScrapeRun, error) {
+	rows, err := db.Query(`
+		SELECT run_id, started_at, finished_at, status, COALESCE(error, '')
+		FROM scrape_runs
+		ORDER BY run_id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []models.ScrapeRun{}
+	for rows.Next() {
+		var run models.ScrapeRun
+		if err := rows.Scan(&run.ID, &run.StartedAt, &run.FinishedAt, &run.Status, &run.Error); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
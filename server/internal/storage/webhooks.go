@@ -0,0 +1,271 @@
+package storage
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+)
+
+const webhookSecretPrefix = "whsec_"
+
+// Webhook'u oluşturur ve imza anahtarını üretir. Anahtar dönen kayıtta bulunur.
+func CreateWebhook(db DB, url string, events []string) (*models.Webhook, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	w := &models.Webhook{
+		URL:       url,
+		Events:    events,
+		Secret:    webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(raw),
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	res, err := db.Exec(`
+		INSERT INTO webhooks (url, events, secret, created_at) VALUES (?, ?, ?, ?)`,
+		w.URL, strings.Join(w.Events, ","), w.Secret, w.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	w.ID, err = res.LastInsertId()
+	return w, err
+}
+
+// Webhook'ları imza anahtarları olmadan döner.
+func ListWebhooks(db DB) ([]models.Webhook, error) {
+	rows, err := db.Query("SELECT webhook_id, url, events, created_at FROM webhooks ORDER BY webhook_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hooks := []models.Webhook{}
+	for rows.Next() {
+		var w models.Webhook
+		var events string
+		if err := rows.Scan(&w.ID, &w.URL, &events, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		w.Events = strings.Split(events, ",")
+		hooks = append(hooks, w)
+	}
+	return hooks, rows.Err()
+}
+
+// Webhook'u ve gönderim kayıtlarını siler. Webhook yoksa false döner.
+func DeleteWebhook(db DB, id int64) (bool, error) {
+	if _, err := db.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return false, err
+	}
+	res, err := db.Exec("DELETE FROM webhooks WHERE webhook_id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+const webhookMatch = "',' || events || ',' LIKE '%,' || ? || ',%' OR ',' || events || ',' LIKE '%,*,%'"
+
+// Olayı kaydeder ve olaya abone her webhook için bekleyen gönderim oluşturur. Gönderimi
+// webhooks paketindeki işçi yapar; böylece olaylar sunucu yeniden başlasa da kaybolmaz.
+// Abone webhook yoksa olay kaydedilmez ve 0 döner.
+func EnqueueEvent(db DB, event string, data interface{}) (int64, error) {
+	return enqueueEvent(db, event, data)
+}
+
+// Değişiklikleri webhook olayı olarak kaydeder ve webhook imlecini son değişikliğe ilerletir.
+// İkisi aynı işlemde yapılır: hata olursa imleç yerinde kalır, değişiklikler sonraki
+// çalıştırmada yeniden kuyruğa eklenir.
+func EnqueueChanges(db DB, changes []models.CourseChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var last int64
+	for _, c := range changes {
+		if _, err := enqueueEvent(tx, c.Kind, c); err != nil {
+			return err
+		}
+		last = max(last, c.ID)
+	}
+	if err := advanceChangeCursor(tx, WebhookCursor, last); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func enqueueEvent(db execer, event string, data interface{}) (int64, error) {
+	var subscribers int
+	if err := db.QueryRow("SELECT COUNT(*) FROM webhooks WHERE "+webhookMatch, event).Scan(&subscribers); err != nil {
+		return 0, err
+	}
+	if subscribers == 0 {
+		return 0, nil
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+
+	res, err := db.Exec(
+		"INSERT INTO webhook_events (event, payload, created_at) VALUES (?, ?, ?)",
+		event, string(payload), now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	eventID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, status, attempts, next_attempt_at, created_at)
+		SELECT webhook_id, ?, ?, 0, ?, ? FROM webhooks WHERE `+webhookMatch,
+		eventID, models.DeliveryPending, now, now, event,
+	)
+	return eventID, err
+}
+
+// Gönderim işçisinin ihtiyaç duyduğu bilgiler
+type DueDelivery struct {
+	models.WebhookDelivery
+	URL            string
+	Secret         string
+	Payload        json.RawMessage
+	EventCreatedAt time.Time
+}
+
+// Deneme zamanı gelmiş bekleyen gönderimleri en eskiden başlayarak döner.
+func GetDueDeliveries(db DB, now time.Time, limit int) ([]DueDelivery, error) {
+	rows, err := db.Query(`
+		SELECT d.delivery_id, d.webhook_id, d.event_id, e.event, d.attempts, d.created_at,
+			w.url, w.secret, e.payload, e.created_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.webhook_id = d.webhook_id
+		JOIN webhook_events e ON e.event_id = d.event_id
+		WHERE d.status = ? AND d.next_attempt_at <= ?
+		ORDER BY d.next_attempt_at, d.delivery_id
+		LIMIT ?`, models.DeliveryPending, now.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []DueDelivery
+	for rows.Next() {
+		d := DueDelivery{}
+		d.Status = models.DeliveryPending
+		var payload string
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Attempts, &d.CreatedAt,
+			&d.URL, &d.Secret, &payload, &d.EventCreatedAt); err != nil {
+			return nil, err
+		}
+		d.Payload = json.RawMessage(payload)
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+// Deneme sonucunu yazar. nextAttempt nil ise gönderim sonuçlanmıştır (status succeeded ya da failed).
+func RecordDeliveryAttempt(db DB, id int64, status models.DeliveryStatus, statusCode int, errText string, nextAttempt *time.Time) error {
+	var deliveredAt *time.Time
+	if status == models.DeliverySucceeded {
+		now := time.Now().UTC().Truncate(time.Second)
+		deliveredAt = &now
+	}
+	_, err := db.Exec(`
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = attempts + 1, last_status_code = ?, last_error = ?,
+			next_attempt_at = ?, delivered_at = ?
+		WHERE delivery_id = ?`,
+		status, statusCode, errText, nextAttempt, deliveredAt, id,
+	)
+	return err
+}
+
+const deliveryColumns = `
+	d.delivery_id, d.webhook_id, d.event_id, e.event, d.status, d.attempts, d.next_attempt_at,
+	COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+func scanDelivery(row interface{ Scan(...interface{}) error }) (models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	err := row.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Status, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	return d, err
+}
+
+// Webhook'un gönderim kayıtlarını yeniden eskiye döner; status boş değilse ona göre süzülür.
+func ListDeliveries(db DB, webhookID int64, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	where := &whereBuilder{}
+	where.add("d.webhook_id = ?", webhookID)
+	if status != "" {
+		where.add("d.status = ?", status)
+	}
+	rows, err := db.Query(`
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.event_id = d.event_id`+where.String()+`
+		ORDER BY d.delivery_id DESC
+		LIMIT ?`, append(where.args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Gönderimin olayını aynı webhook'a hemen gönderilmek üzere yeni bir gönderim olarak
+// kuyruğa ekler; eski kayıt günlükte kalır. Gönderim yoksa sql.ErrNoRows döner.
+func ReplayDelivery(db DB, id int64) (*models.WebhookDelivery, error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	res, err := db.Exec(`
+		INSERT INTO webhook_deliveries (webhook_id, event_id, status, attempts, next_attempt_at, created_at)
+		SELECT webhook_id, event_id, ?, 0, ?, ? FROM webhook_deliveries WHERE delivery_id = ?`,
+		models.DeliveryPending, now, now, id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	newID, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := scanDelivery(db.QueryRow(`
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.event_id = d.event_id
+		WHERE d.delivery_id = ?`, newID))
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
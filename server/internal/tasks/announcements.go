@@ -0,0 +1,166 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/scraper"
+	"companion_server/internal/storage"
+)
+
+// Kaynak başına en fazla taranacak sayfa; ilk taramada geçmiş duyurular da bu kadar alınır.
+const MaxNoticePages = 10
+
+// "siteKey:kategori" çiftlerinin virgülle ayrılmış listesini çözer (ör. "abc123:1,abc123:2,xyz:1").
+func ParseAnnouncementSources(s string) ([]models.AnnouncementSource, error) {
+	var sources []models.AnnouncementSource
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		site, category, ok := strings.Cut(part, ":")
+		id, err := strconv.Atoi(category)
+		if !ok || site == "" || err != nil {
+			return nil, fmt.Errorf("geçersiz duyuru kaynağı: %q (siteKey:kategori bekleniyor)", part)
+		}
+		sources = append(sources, models.AnnouncementSource{SiteKey: site, CategoryID: id})
+	}
+	return sources, nil
+}
+
+// Duyuru kaynaklarını sayfa sayfa tarar. Bir sayfadaki duyuruların hepsi daha önce
+// görülmüşse sonraki sayfalara geçilmez. Yeni duyuruları döner ve her biri için
+// announcement.published olayı yayar; kaynağın ilk taramasında alınan geçmiş duyurular
+// yeni sayılmaz; stored bunlar dahil kaydedilen duyuru sayısıdır. Yeni kayıt varsa duyuru
+// sürümü artırılır. Sadece bütün kaynaklar başarısız olursa hata döner.
+func RunAnnouncementScraper(ctx context.Context, db *sql.DB, s *scraper.Service, sources []models.AnnouncementSource) (fresh []models.Announcement, stored int, err error) {
+	failed := 0
+	var lastErr error
+
+	for _, src := range sources {
+		known, err := storage.HasAnnouncements(db, src)
+		if err != nil {
+			return fresh, stored, err
+		}
+		added, err := scrapeAnnouncementSource(ctx, db, s, src)
+		stored += len(added)
+		if known {
+			fresh = append(fresh, added...)
+			for _, a := range added {
+				if _, err := storage.EnqueueEvent(db, models.EventAnnouncementPublished, a); err != nil {
+					log.Printf("Webhook olayı kaydedilemedi: %v", err)
+				}
+			}
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return fresh, stored, ctx.Err()
+			}
+			log.Printf("%s/%d duyuruları taranırken hata: %v", src.SiteKey, src.CategoryID, err)
+			failed++
+			lastErr = err
+		}
+	}
+
+	if stored > 0 {
+		log.Printf("%d yeni duyuru bulundu.", stored)
+		if _, err := storage.BumpVersion(db, storage.AnnouncementVersion); err != nil {
+			return fresh, stored, err
+		}
+	}
+	if len(sources) > 0 && failed == len(sources) {
+		return fresh, stored, lastErr
+	}
+	return fresh, stored, nil
+}
+
+func scrapeAnnouncementSource(ctx context.Context, db *sql.DB, s *scraper.Service, src models.AnnouncementSource) ([]models.Announcement, error) {
+	var added []models.Announcement
+	for page := 1; page <= MaxNoticePages; page++ {
+		select {
+		case <-ctx.Done():
+			return added, ctx.Err()
+		default:
+		}
+
+		notices, err := s.GetNotices(src, page)
+		if err != nil {
+			return added, err
+		}
+		if len(notices) == 0 {
+			break
+		}
+
+		newInPage := 0
+		for i := range notices {
+			isNew, err := storage.SaveAnnouncement(db, &notices[i])
+			if err != nil {
+				return added, err
+			}
+			if isNew {
+				added = append(added, notices[i])
+				newInPage++
+			}
+		}
+		if newInPage == 0 {
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+	return added, nil
+}
+
+// Duyuru kaynaklarını belirli aralıklarla tarar. EBS taramasından bağımsızdır ve çok
+// daha sık çalışır; cihazlar CMS yerine sunucuyu sorgular.
+type AnnouncementPoller struct {
+	db      *sql.DB
+	scraper *scraper.Service
+	sources []models.AnnouncementSource
+	quit    chan struct{}
+
+	// Yeni duyuru bulunduğunda çağrılır (ör. API yanıt önbelleğini temizlemek için).
+	OnCommit func()
+	// Kaynakların ilk taraması dışında bulunan yeni duyurularla çağrılır (ör. bildirimler).
+	OnAnnouncements func(ctx context.Context, announcements []models.Announcement)
+}
+
+func NewAnnouncementPoller(db *sql.DB, s *scraper.Service, sources []models.AnnouncementSource) *AnnouncementPoller {
+	return &AnnouncementPoller{
+		db:      db,
+		scraper: s,
+		sources: sources,
+		quit:    make(chan struct{}),
+	}
+}
+
+func (p *AnnouncementPoller) Start(interval time.Duration) {
+	log.Printf("Duyuru taraması başlatıldı: %d kaynak, %v aralıkla", len(p.sources), interval)
+
+	every(interval, true, p.quit, p.run)
+}
+
+func (p *AnnouncementPoller) run(ctx context.Context) {
+	fresh, stored, err := RunAnnouncementScraper(ctx, p.db, p.scraper, p.sources)
+	if err != nil {
+		log.Printf("Duyuru tarama hatası: %v", err)
+	}
+	// İlk taramanın geçmiş duyuruları da kayıttır; önbellek fresh'e değil kayda göre temizlenir.
+	if stored > 0 && p.OnCommit != nil {
+		p.OnCommit()
+	}
+	if len(fresh) > 0 && p.OnAnnouncements != nil {
+		p.OnAnnouncements(ctx, fresh)
+	}
+}
+
+func (p *AnnouncementPoller) Stop() {
+	close(p.quit)
+}
@@ -0,0 +1,62 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"companion_server/internal/scraper"
+	"companion_server/internal/storage"
+)
+
+// Akademik takvimi tarar ve kaydeder. Takvim değiştiyse veri sürümü artırılır ve true döner.
+func RunCalendarScraper(db *sql.DB, s *scraper.Service) (bool, error) {
+	events, err := s.GetAcademicCalendar()
+	if err != nil {
+		return false, err
+	}
+	changed, err := storage.ReplaceCalendarEvents(db, events)
+	if err != nil || !changed {
+		return false, err
+	}
+	log.Printf("Akademik takvim güncellendi: %d etkinlik", len(events))
+	_, err = storage.BumpDataVersion(db)
+	return true, err
+}
+
+// Akademik takvimi belirli aralıklarla tarar. Takvim tek sayfadır ve seyrek değişir;
+// EBS taramasını beklemeden başlangıçta da alınır.
+type CalendarPoller struct {
+	db      *sql.DB
+	scraper *scraper.Service
+	quit    chan struct{}
+
+	// Takvim değiştiğinde çağrılır (ör. API yanıt önbelleğini temizlemek için).
+	OnCommit func()
+}
+
+func NewCalendarPoller(db *sql.DB, s *scraper.Service) *CalendarPoller {
+	return &CalendarPoller{db: db, scraper: s, quit: make(chan struct{})}
+}
+
+func (p *CalendarPoller) Start(interval time.Duration) {
+	log.Printf("Akademik takvim taraması başlatıldı, %v aralıkla", interval)
+
+	every(interval, true, p.quit, func(context.Context) { p.run() })
+}
+
+func (p *CalendarPoller) run() {
+	changed, err := RunCalendarScraper(p.db, p.scraper)
+	if err != nil {
+		log.Printf("Akademik takvim tarama hatası: %v", err)
+		return
+	}
+	if changed && p.OnCommit != nil {
+		p.OnCommit()
+	}
+}
+
+func (p *CalendarPoller) Stop() {
+	close(p.quit)
+}
@@ -0,0 +1,35 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"companion_server/internal/equivalence"
+	"companion_server/internal/storage"
+)
+
+// Bölümler arası ders eşdeğerlik adaylarını yeniden hesaplar.
+func RunEquivalenceScan(ctx context.Context, db *sql.DB) (int, error) {
+	log.Println("Eşdeğerlik taraması başlatılıyor...")
+	conn := storage.WithContext(ctx, db)
+
+	profiles, err := storage.GetCourseProfiles(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	candidates := equivalence.FindCandidates(profiles)
+	if err := storage.UpsertEquivalenceCandidates(conn, candidates); err != nil {
+		return 0, err
+	}
+
+	log.Printf("%d ders karşılaştırıldı, %d eşdeğerlik adayı bulundu.", len(profiles), len(candidates))
+	return len(candidates), nil
+}
@@ -0,0 +1,36 @@
+package tasks
+
+import (
+	"context"
+	"time"
+)
+
+// Zamanlayıcıların ortak döngüsü: run'ı immediate ise hemen, sonra her interval'de çalıştırır.
+// quit kapandığında run'a verilen bağlam iptal edilir, çalışan iş de durur.
+func every(interval time.Duration, immediate bool, quit <-chan struct{}, run func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-quit
+		cancel()
+	}()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		if immediate {
+			run(ctx)
+		}
+		for {
+			select {
+			case <-ticker.C:
+				// Durdurma ile tik aynı anda gelmiş olabilir.
+				if ctx.Err() == nil {
+					run(ctx)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	stopped := make(chan struct{})
+	quit := make(chan struct{})
+	every(10*time.Millisecond, true, quit, func(ctx context.Context) {
+		runs <- struct{}{}
+		// Durdurma çalışan işin bağlamını da iptal etmeli.
+		if len(runs) == 3 {
+			close(quit)
+			<-ctx.Done()
+			close(stopped)
+		}
+	})
+
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("loop did not stop")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := len(runs); n != 3 {
+		t.Errorf("runs = %d, want 3", n)
+	}
+}
@@ -14,13 +14,27 @@ const (
 	BackfillYears = 7
 )
 
-func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
+// EBS'yi tarar ve sonucu tarama geçmişine kaydeder. Başarılı tarama veri sürümünü artırır.
+func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) error {
 	log.Println("Tarama işlemi başlatılıyor...")
 
+	runID, err := storage.StartScrapeRun(db)
+	if err != nil {
+		return err
+	}
+
+	err = scrape(ctx, db, s)
+	if finishErr := storage.FinishScrapeRun(db, runID, err); finishErr != nil {
+		log.Printf("Tarama kaydı güncellenemedi: %v", finishErr)
+	}
+	return err
+}
+
+func scrape(ctx context.Context, db *sql.DB, s *scraper.Service) error {
 	faculties, departments, err := s.GetStructure()
 	if err != nil {
 		log.Printf("Hata: %v", err)
-		return
+		return err
 	}
 
 	log.Printf(" %d fakülte ve %d bölüm bulundu. Kaydediliyor...", len(faculties), len(departments))
@@ -52,7 +66,7 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 	for _, d := range departments {
 		select {
 		case <-ctx.Done():
-			return
+			return ctx.Err()
 		default:
 		}
 
@@ -62,17 +76,24 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 
 		// Bulunan dersleri tutacağımız map. Yeniden eskiye gittiğimiz için ilk bulunan en doğru.
 		existingCoursesMap, _ := storage.GetExistingCourseCodes(db, d.ID)
+		missingEn, err := storage.GetCoursesMissingNameEn(db, d.ID)
+		if err != nil {
+			missingEn = make(map[string]bool)
+		}
+
+		// Seçmeli havuzları sadece bulunan en güncel müfredattan alınır.
+		electivesStored := false
 
 		for year := currentAcademicYear; year >= endYear; year-- {
 			select {
 			case <-ctx.Done():
-				return
+				return ctx.Err()
 			default:
 			}
 
 			time.Sleep(100 * time.Millisecond)
 
-			courses, err := s.GetCourses(d.GUID, year)
+			courses, groups, err := s.GetCourses(d.GUID, year)
 			if err != nil {
 				log.Printf("%s (%d) için taramada hata oluştu : %v", d.Name, year, err)
 				continue
@@ -82,9 +103,44 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 				continue
 			}
 
+			if !electivesStored {
+				for i := range groups {
+					groups[i].DepartmentID = d.ID
+					groups[i].Year = year
+				}
+				if err := storage.ReplaceElectiveGroups(db, d.ID, groups); err != nil {
+					log.Printf("%s seçmeli havuzları kaydedilirken hata: %v", d.Name, err)
+				}
+				electivesStored = true
+			}
+
+			// Güncel müfredatta artık bulunmayan dersler kaldırılmış sayılır.
+			if year == currentAcademicYear {
+				codes := make([]string, len(courses))
+				for i, c := range courses {
+					codes[i] = c.Code
+				}
+				if _, err := storage.MarkRemovedCourses(db, d.ID, codes); err != nil {
+					log.Printf("%s kaldırılan dersler işaretlenemedi: %v", d.Name, err)
+				}
+			}
+
+			// İngilizce adlar bu yılda eklenecek ya da İngilizce adı eksik ders varsa çekilir.
+			var namesEn map[string]string
+			for _, c := range courses {
+				if !existingCoursesMap[c.Code] || missingEn[c.Code] {
+					namesEn, err = s.GetCourseNamesEn(d.GUID, year)
+					if err != nil {
+						log.Printf("%s (%d) İngilizce ders adları alınamadı: %v", d.Name, year, err)
+					}
+					break
+				}
+			}
+
 			for _, c := range courses {
 				c.DepartmentID = d.ID
 				c.Year = year
+				c.NameEn = namesEn[c.Code]
 
 				// Ders kodu güncel yılda yoksa ders kaldırılmıştır.
 				if year < currentAcademicYear {
@@ -93,15 +149,31 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 					c.IsRemoved = false
 				}
 
-				if !existingCoursesMap[c.Code] {
+				// Güncel yılın dersleri her taramada güncellenir; böylece ad/kredi
+				// değişiklikleri ve geri eklenen dersler değişiklik günlüğüne düşer.
+				if year == currentAcademicYear || !existingCoursesMap[c.Code] {
 					if err := storage.InsertCourse(db, c, d.ID); err != nil {
 						log.Printf("%s dersi eklenirken hata: %v", c.Code, err)
 					} else {
+						if !existingCoursesMap[c.Code] && c.NameEn == "" {
+							missingEn[c.Code] = true
+						}
 						existingCoursesMap[c.Code] = true
+						if c.NameEn != "" {
+							delete(missingEn, c.Code)
+						}
+					}
+				} else if missingEn[c.Code] && c.NameEn != "" {
+					// Eski yıllarda kalan derslerin eksik İngilizce adları önceki yılların listesinden doldurulur.
+					if err := storage.FillCourseNameEn(db, d.ID, c.Code, c.NameEn); err != nil {
+						log.Printf("%s İngilizce adı kaydedilemedi: %v", c.Code, err)
+					} else {
+						delete(missingEn, c.Code)
 					}
 				}
 
-				// Güncelde detay yoksa bir önceki senelerden alınır
+				// Güncelde detay yoksa bir önceki senelerden alınır. Eski ayrıştırıcıyla alınmış
+				// izlenceler (ör. ön koşulları okunmamış) de yeniden alınır.
 				if !validDetailsMap[c.Code] {
 					if c.LinkID != "" && c.UnitID != "" {
 						detail, err := s.GetCourseDetail(c.LinkID, c.UnitID)
@@ -110,9 +182,23 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 								detail.BaseInfo.Code = c.Code
 							}
 
+							// İngilizce izlence alınamazsa Türkçe içerik yine kaydedilir, İngilizcesi
+							// sonraki taramada yeniden denenir.
+							en, enErr := s.GetCourseDetailEn(c.LinkID, c.UnitID)
+							if enErr == nil {
+								scraper.MergeEnglish(detail, en)
+							} else {
+								log.Printf("%s İngilizce izlencesi alınamadı: %v", c.Code, enErr)
+							}
+
 							if detail.HasContent() {
 								if err := storage.InsertCourseDetail(db, *detail); err == nil {
 									validDetailsMap[c.Code] = true
+									if enErr == nil {
+										if err := storage.MarkDetailFetched(db, c.Code); err != nil {
+											log.Printf("%s izlence sürümü kaydedilemedi: %v", c.Code, err)
+										}
+									}
 								}
 							}
 						}
@@ -124,4 +210,5 @@ func RunScraper(ctx context.Context, db *sql.DB, s *scraper.Service) {
 	}
 
 	log.Println("Tarama tamamlandı.")
+	return nil
 }
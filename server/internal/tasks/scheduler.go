@@ -1,10 +1,13 @@
 package tasks
 
 import (
+	"companion_server/internal/models"
 	"companion_server/internal/scraper"
+	"companion_server/internal/storage"
 	"context"
 	"database/sql"
 	"log"
+	"sync"
 	"time"
 )
 
@@ -12,47 +15,133 @@ import (
 type Scheduler struct {
 	db      *sql.DB
 	scraper *scraper.Service
-	ticker  *time.Ticker
 	quit    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	// Zamanlanmış ve elle başlatılan taramalar üst üste binmez.
+	running sync.Mutex
+
+	// Tarama başarıyla tamamlandığında çağrılır (ör. API yanıt önbelleğini temizlemek için).
+	OnCommit func()
+	// Taramada tespit edilen müfredat değişiklikleriyle çağrılır (ör. bildirimler). Hata
+	// dönerse değişiklikler sonraki taramada yeniden verilir.
+	OnChanges func(ctx context.Context, changes []models.CourseChange) error
 }
 
 func NewScheduler(db *sql.DB, s *scraper.Service) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Scheduler{
 		db:      db,
 		scraper: s,
 		quit:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
 	}
 }
 
-func (s *Scheduler) Start(interval time.Duration) {
-	s.ticker = time.NewTicker(interval)
-
+// Taramayı interval aralıkla çalıştırır. Tam tarama uzun sürdüğü için immediate false ise
+// ilk tarama açılışta değil, ilk aralık dolduğunda yapılır.
+func (s *Scheduler) Start(interval time.Duration, immediate bool) {
 	log.Printf("Scheduler başlatıldı. Tarama şu aralıklarla yapılacak: %v ", interval)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	if immediate {
+		log.Println("İlk tarama gerçekleştiriliyor...")
+		s.Trigger()
+	}
+
+	every(interval, false, s.quit, func(context.Context) {
+		log.Println("Zamanlanan tarama işlemi başlatılıyor...")
+		if !s.running.TryLock() {
+			log.Println("Önceki tarama sürüyor, zamanlanan tarama atlandı.")
+			return
+		}
+		defer s.running.Unlock()
+		s.run(s.ctx)
+	})
+}
 
-	// Başlatıldığında hemen çalış
+// Taramayı hemen arka planda başlatır. Tarama zaten sürüyorsa false döner.
+func (s *Scheduler) Trigger() bool {
+	if !s.running.TryLock() {
+		return false
+	}
 	go func() {
-		log.Println("İlk tarama gerçekleştiriliyor...")
-		RunScraper(ctx, s.db, s.scraper)
+		defer s.running.Unlock()
+		s.run(s.ctx)
 	}()
+	return true
+}
 
-	go func() {
-		for {
-			select {
-			case <-s.ticker.C:
-				log.Println("Zamanlanan tarama işlemi başlatılıyor...")
-				RunScraper(ctx, s.db, s.scraper)
-			case <-s.quit:
-				s.ticker.Stop()
-				cancel()
-				return
-			}
+// Tarama ve taramaya bağlı hesaplamalar
+func (s *Scheduler) run(ctx context.Context) {
+	// İmleçler ilk taramadan önce kurulur ki ilk taramanın değişiklikleri de bildirilsin.
+	for _, cursor := range []string{storage.WebhookCursor, storage.PushCursor} {
+		if _, err := storage.ChangeCursor(s.db, cursor); err != nil {
+			log.Printf("Değişiklik imleci okunamadı: %v", err)
 		}
-	}()
+	}
+
+	if err := RunScraper(ctx, s.db, s.scraper); err != nil {
+		log.Printf("Tarama hatası: %v", err)
+		return
+	}
+
+	if _, err := RunEquivalenceScan(ctx, s.db); err != nil {
+		log.Printf("Eşdeğerlik taraması hatası: %v", err)
+	}
+
+	// Ders kodları değişmiş olabilir; ders programı yeniden eşleştirilir.
+	if _, err := RunTimetableIngest(ctx, s.db, s.scraper); err != nil {
+		log.Printf("Ders programı alım hatası: %v", err)
+	}
+
+	if s.OnCommit != nil {
+		s.OnCommit()
+	}
+
+	s.emitChanges(ctx)
+}
+
+// İmleçlerden sonraki müfredat değişikliklerini webhook kuyruğuna ekler ve OnChanges ile
+// bildirir. Her imleç sadece kendi işi başarılı olursa ilerler; başarısız bir çalıştırmanın
+// değişiklikleri sonrakinde yeniden gönderilir.
+func (s *Scheduler) emitChanges(ctx context.Context) {
+	if changes, err := s.pendingChanges(storage.WebhookCursor); err != nil {
+		log.Printf("Müfredat değişiklikleri okunamadı: %v", err)
+	} else if err := storage.EnqueueChanges(s.db, changes); err != nil {
+		log.Printf("Webhook olayları kaydedilemedi: %v", err)
+	}
+
+	if s.OnChanges == nil {
+		return
+	}
+	changes, err := s.pendingChanges(storage.PushCursor)
+	if err != nil {
+		log.Printf("Müfredat değişiklikleri okunamadı: %v", err)
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+	if err := s.OnChanges(ctx, changes); err != nil {
+		log.Printf("Müfredat değişiklikleri bildirilemedi, sonraki taramada yeniden denenecek: %v", err)
+		return
+	}
+	if err := storage.AdvanceChangeCursor(s.db, storage.PushCursor, changes[len(changes)-1].ID); err != nil {
+		log.Printf("Bildirim imleci güncellenemedi: %v", err)
+	}
+}
+
+func (s *Scheduler) pendingChanges(cursor string) ([]models.CourseChange, error) {
+	after, err := storage.ChangeCursor(s.db, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return storage.GetChangesAfter(s.db, after)
 }
 
 func (s *Scheduler) Stop() {
 	close(s.quit)
+	s.cancel()
 	log.Println("Scheduler çalışmayı durdurdu.")
 }
@@ -0,0 +1,78 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestEmitChangesCursors(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertFaculty(db, models.Faculty{ID: 1, GUID: "f1", Name: "Mühendislik"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.InsertDepartment(db, models.Department{ID: 10, FacultyID: 1, GUID: "d1", Name: "Bilgisayar"}); err != nil {
+		t.Fatal(err)
+	}
+	run, err := storage.StartScrapeRun(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.FinishScrapeRun(db, run, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewScheduler(db, nil)
+	var notified [][]models.CourseChange
+	notifyErr := errors.New("servis kapalı")
+	s.OnChanges = func(ctx context.Context, changes []models.CourseChange) error {
+		notified = append(notified, changes)
+		return notifyErr
+	}
+	// İlk çalıştırma imleçleri kurar; öncesindeki değişiklikler bildirilmez.
+	s.emitChanges(context.Background())
+	if len(notified) != 0 {
+		t.Fatalf("notified before any change: %+v", notified)
+	}
+
+	if err := storage.InsertCourse(db, models.Course{Code: "MAT101", Name: "Matematik I", Semester: "1. Yarıyıl"}, 10); err != nil {
+		t.Fatal(err)
+	}
+	s.emitChanges(context.Background())
+	if len(notified) != 1 || len(notified[0]) != 1 {
+		t.Fatalf("notified = %+v", notified)
+	}
+	id := notified[0][0].ID
+	if cur, _ := storage.ChangeCursor(db, storage.WebhookCursor); cur != id {
+		t.Errorf("webhook cursor = %d, want %d", cur, id)
+	}
+	if cur, _ := storage.ChangeCursor(db, storage.PushCursor); cur == id {
+		t.Error("push cursor advanced after a failed notification")
+	}
+
+	// Başarısız bildirimin değişikliği sonraki çalıştırmada yeniden verilir.
+	notifyErr = nil
+	s.emitChanges(context.Background())
+	if len(notified) != 2 || len(notified[1]) != 1 || notified[1][0].ID != id {
+		t.Fatalf("retry notified = %+v", notified)
+	}
+	if cur, _ := storage.ChangeCursor(db, storage.PushCursor); cur != id {
+		t.Errorf("push cursor = %d, want %d", cur, id)
+	}
+	s.emitChanges(context.Background())
+	if len(notified) != 2 {
+		t.Errorf("notified again after success: %+v", notified[2:])
+	}
+}
@@ -0,0 +1,308 @@
+package tasks
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/scraper"
+	"companion_server/internal/storage"
+	"companion_server/internal/timetable"
+)
+
+// Zamanlanmış, taramadan sonra ve elle başlatılan alımlar üst üste binmez.
+var timetableRunning sync.Mutex
+
+// Kaynağı tanımlı bölümlerin ders programı PDF'lerini indirir, ayrıştırır ve müfredat
+// dersleriyle eşleştirir. Önce kaynağı olmayan bölümlerin programları üniversite
+// sitesinden aranır. EBS taramasından sonra da çalışır; PDF değişmese bile yeni
+// eklenen dersler eşleşsin diye her seferinde yeniden eşleştirilir.
+// Program değiştiyse veri sürümü artırılır ve true döner. Hatalar kaynağa yazılır;
+// sadece bütün kaynaklar başarısız olursa hata döner. Başka bir alım sürüyorsa bitmesi beklenir.
+func RunTimetableIngest(ctx context.Context, db *sql.DB, s *scraper.Service) (bool, error) {
+	timetableRunning.Lock()
+	defer timetableRunning.Unlock()
+	return runTimetableIngest(ctx, db, s)
+}
+
+func runTimetableIngest(ctx context.Context, db *sql.DB, s *scraper.Service) (bool, error) {
+	if added, err := discoverTimetableSources(ctx, db, s); err != nil {
+		log.Printf("Ders programı kaynakları aranamadı: %v", err)
+	} else if added > 0 {
+		log.Printf("%d bölümün ders programı kaynağı bulundu", added)
+	}
+
+	sources, err := storage.GetTimetableSources(db)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	failed := 0
+	var lastErr error
+	for _, src := range sources {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		updated, err := ingestTimetable(db, s, &src)
+		if err != nil {
+			log.Printf("Bölüm %d ders programı alınamadı: %v", src.DepartmentID, err)
+			failed++
+			lastErr = err
+		}
+		if recErr := storage.RecordTimetableFetch(db, src, err); recErr != nil {
+			return changed, recErr
+		}
+		changed = changed || updated
+	}
+
+	if changed {
+		if _, err := storage.BumpDataVersion(db); err != nil {
+			return true, err
+		}
+	}
+	if len(sources) > 0 && failed == len(sources) {
+		return changed, lastErr
+	}
+	return changed, nil
+}
+
+// Kaynağı olmayan bölümlerin ders programı sayfalarını bulur: birim listesinden
+// (scraper.Service.UnitsURL) bölüm sitesine, gerekirse fakülte sitesi üzerinden gidilir,
+// sitede "ders programı" bağlantısı aranır. Elle ayarlanmış kaynaklara dokunulmaz.
+// Eklenen kaynak sayısı döner.
+func discoverTimetableSources(ctx context.Context, db *sql.DB, s *scraper.Service) (int, error) {
+	if s.UnitsURL == "" {
+		return 0, nil
+	}
+	departments, err := storage.GetDepartmentsWithoutTimetableSource(db)
+	if err != nil || len(departments) == 0 {
+		return 0, err
+	}
+	faculties, err := storage.GetAllFaculties(db)
+	if err != nil {
+		return 0, err
+	}
+
+	byFaculty := make(map[int][]models.Department)
+	nameCount := make(map[string]int)
+	var names []string
+	for _, d := range departments {
+		byFaculty[d.FacultyID] = append(byFaculty[d.FacultyID], d)
+		nameCount[d.Name]++
+		names = append(names, d.Name)
+	}
+	for _, f := range faculties {
+		if len(byFaculty[f.ID]) > 0 {
+			names = append(names, f.Name)
+		}
+	}
+
+	sites, err := s.FindUnitSites(s.UnitsURL, names)
+	if err != nil {
+		return 0, err
+	}
+	// Aynı adlı bölümler (ör. iki fakültede "Matematik") sadece fakülte sitesinden ayırt edilir.
+	deptSites := make(map[int]string)
+	for _, d := range departments {
+		if site, ok := sites[d.Name]; ok && nameCount[d.Name] == 1 {
+			deptSites[d.ID] = site
+		}
+	}
+	for _, f := range faculties {
+		site, ok := sites[f.Name]
+		if !ok {
+			continue
+		}
+		var missing []string
+		for _, d := range byFaculty[f.ID] {
+			if _, found := deptSites[d.ID]; !found {
+				missing = append(missing, d.Name)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		found, err := s.FindUnitSites(site, missing)
+		if err != nil {
+			log.Printf("%s sitesi taranamadı: %v", f.Name, err)
+			continue
+		}
+		for _, d := range byFaculty[f.ID] {
+			if site, ok := found[d.Name]; ok {
+				deptSites[d.ID] = site
+			}
+		}
+	}
+
+	added := 0
+	for _, d := range departments {
+		site, ok := deptSites[d.ID]
+		if !ok {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return added, err
+		}
+		page, err := s.FindTimetablePage(site)
+		if err != nil {
+			log.Printf("Bölüm %d (%s): %v", d.ID, site, err)
+			continue
+		}
+		ok, err = storage.AddTimetableSource(db, d.ID, page)
+		if err != nil {
+			return added, err
+		}
+		if ok {
+			added++
+		}
+	}
+	return added, nil
+}
+
+func ingestTimetable(db *sql.DB, s *scraper.Service, src *models.TimetableSource) (changed bool, err error) {
+	// Ayrıştırıcıyı çökerten bir PDF sadece kendi bölümünü başarısız sayar.
+	defer func() {
+		if p := recover(); p != nil {
+			changed, err = false, fmt.Errorf("ders programı işlenemedi: %v", p)
+		}
+	}()
+
+	pdfURL, err := s.FindTimetablePDF(src.PageURL)
+	if err != nil {
+		return false, err
+	}
+	data, err := s.DownloadPDF(pdfURL)
+	if err != nil {
+		return false, err
+	}
+	parsed, err := timetable.ParsePDF(data)
+	if err != nil {
+		return false, err
+	}
+
+	courses, err := storage.GetCoursesByDepartmentID(db, src.DepartmentID)
+	if err != nil {
+		return false, err
+	}
+	current := courses[:0]
+	for _, c := range courses {
+		if !c.IsRemoved {
+			current = append(current, c)
+		}
+	}
+	matcher := timetable.NewMatcher(current)
+
+	slots := make([]models.TimetableSlot, len(parsed))
+	matched := 0
+	for i, p := range parsed {
+		code, score := matcher.Match(p.CourseName, p.Semester)
+		if code != "" {
+			matched++
+		}
+		slots[i] = models.TimetableSlot{
+			CourseCode: code,
+			CourseName: p.CourseName,
+			Day:        p.Day,
+			Start:      p.Start,
+			End:        p.End,
+			Room:       p.Room,
+			Instructor: p.Instructor,
+			Semester:   p.Semester,
+			Section:    p.Section,
+			MatchScore: float64(int(score*100+0.5)) / 100,
+		}
+	}
+
+	changed, err = storage.ReplaceTimetableSlots(db, src.DepartmentID, slots)
+	if err != nil {
+		return false, err
+	}
+
+	sum := sha256.Sum256(data)
+	src.PDFURL = pdfURL
+	src.ContentHash = hex.EncodeToString(sum[:])
+	src.SlotCount = len(slots)
+	src.MatchedCount = matched
+	if changed {
+		log.Printf("Bölüm %d ders programı güncellendi: %d satır, %d eşleşme", src.DepartmentID, len(slots), matched)
+	}
+	return changed, nil
+}
+
+// Takvim uygulamaları akışları günde birkaç kez okur; bu kadar süre okunmayan akış
+// terk edilmiş sayılır ve silinir.
+const unusedFeedExpiry = 90 * 24 * time.Hour
+
+// Ders programlarını belirli aralıklarla alır ve kullanılmayan takvim akışlarını siler.
+// EBS taramasından bağımsız çalışır; programlar dönem başında sıkça güncellenir.
+type TimetablePoller struct {
+	db      *sql.DB
+	scraper *scraper.Service
+	quit    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// Program değiştiğinde çağrılır (ör. API yanıt önbelleğini temizlemek için).
+	OnCommit func()
+}
+
+func NewTimetablePoller(db *sql.DB, s *scraper.Service) *TimetablePoller {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TimetablePoller{db: db, scraper: s, quit: make(chan struct{}), ctx: ctx, cancel: cancel}
+}
+
+func (p *TimetablePoller) Start(interval time.Duration) {
+	log.Printf("Ders programı alımı başlatıldı, %v aralıkla", interval)
+
+	every(interval, true, p.quit, func(ctx context.Context) {
+		if !timetableRunning.TryLock() {
+			log.Println("Önceki ders programı alımı sürüyor, zamanlanan alım atlandı.")
+			return
+		}
+		defer timetableRunning.Unlock()
+		p.run(ctx)
+	})
+}
+
+// Alımı hemen arka planda başlatır. Alım (ya da taramadan sonraki alım) zaten sürüyorsa false döner.
+func (p *TimetablePoller) Trigger() bool {
+	if !timetableRunning.TryLock() {
+		return false
+	}
+	go func() {
+		defer timetableRunning.Unlock()
+		p.run(p.ctx)
+	}()
+	return true
+}
+
+func (p *TimetablePoller) run(ctx context.Context) {
+	if n, err := storage.DeleteUnusedTimetableFeeds(p.db, time.Now().Add(-unusedFeedExpiry)); err != nil {
+		log.Printf("Kullanılmayan takvim akışları silinemedi: %v", err)
+	} else if n > 0 {
+		log.Printf("%d kullanılmayan takvim akışı silindi", n)
+	}
+
+	changed, err := runTimetableIngest(ctx, p.db, p.scraper)
+	if err != nil {
+		log.Printf("Ders programı alım hatası: %v", err)
+	}
+	if changed && p.OnCommit != nil {
+		p.OnCommit()
+	}
+}
+
+func (p *TimetablePoller) Stop() {
+	close(p.quit)
+	p.cancel()
+}
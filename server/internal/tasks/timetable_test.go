@@ -0,0 +1,109 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"companion_server/internal/models"
+	"companion_server/internal/scraper"
+	"companion_server/internal/storage"
+)
+
+func TestDiscoverTimetableSources(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []models.Faculty{{ID: 1, GUID: "f1", Name: "Mühendislik"}, {ID: 2, GUID: "f2", Name: "Fen"}} {
+		if err := storage.InsertFaculty(db, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, d := range []models.Department{
+		{ID: 10, FacultyID: 1, GUID: "d1", Name: "Bilgisayar Mühendisliği"},
+		{ID: 11, FacultyID: 1, GUID: "d2", Name: "Matematik"},
+		{ID: 20, FacultyID: 2, GUID: "d3", Name: "Matematik"},
+		{ID: 21, FacultyID: 2, GUID: "d4", Name: "Fizik"},
+	} {
+		if err := storage.InsertDepartment(db, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := storage.SetTimetableSource(db, 21, "https://example.com/fizik.pdf"); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := map[string]string{
+		// Ana sayfadaki "Matematik" bağlantısı iki bölümden hangisine ait olduğu bilinmediği için kullanılmaz.
+		"/":        `<a href="/muh">Mühendislik Fakültesi</a> <a href="/fen">Fen Fakültesi</a> <a href="/mat">Matematik</a> <a href="/fiz">Fizik</a>`,
+		"/muh":     `<a href="/bm">Bilgisayar Mühendisliği Bölümü</a> <a href="/muh-mat">Matematik Bölümü</a>`,
+		"/fen":     `<a href="/fen-mat">Matematik Bölümü</a>`,
+		"/bm":      `<a href="/bm/ders-programi">Ders Programı</a>`,
+		"/muh-mat": `<a href="/muh-mat/duyurular">Duyurular</a>`,
+		"/fen-mat": `<a href="/fen-mat/program.pdf">Haftalık Ders Programı</a>`,
+		"/mat":     `<a href="/mat/ders-programi">Ders Programı</a>`,
+		"/fiz":     `<a href="/fiz/ders-programi">Ders Programı</a>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer srv.Close()
+
+	s := &scraper.Service{UnitsURL: srv.URL + "/"}
+	added, err := discoverTimetableSources(context.Background(), db, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added != 2 {
+		t.Errorf("added = %d, want 2", added)
+	}
+
+	sources, err := storage.GetTimetableSources(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[int]string)
+	for _, src := range sources {
+		got[src.DepartmentID] = src.PageURL
+	}
+	want := map[int]string{
+		10: srv.URL + "/bm/ders-programi",
+		20: srv.URL + "/fen-mat/program.pdf",
+		// Elle ayarlanmış kaynak korunur.
+		21: "https://example.com/fizik.pdf",
+	}
+	if len(got) != len(want) {
+		t.Errorf("sources = %v, want %v", got, want)
+	}
+	for id, u := range want {
+		if got[id] != u {
+			t.Errorf("department %d = %q, want %q", id, got[id], u)
+		}
+	}
+}
+
+func TestTimetableTriggerBusy(t *testing.T) {
+	p := NewTimetablePoller(nil, &scraper.Service{})
+	defer p.Stop()
+
+	// Taramadan sonraki alım sürerken elle başlatılan alım reddedilir.
+	timetableRunning.Lock()
+	defer timetableRunning.Unlock()
+	if p.Trigger() {
+		t.Error("Trigger = true while an ingest is running")
+	}
+}
@@ -0,0 +1,194 @@
+package timetable
+
+import (
+	"regexp"
+	"strings"
+
+	"companion_server/internal/models"
+)
+
+// Bu skorun altındaki eşleşmeler kaydedilmez; ders kodu boş kalır.
+const MatchThreshold = 0.65
+
+// Aynı yarıyıldaki derse verilen ek puan; adı benzer iki dersten doğru dönemdeki seçilir.
+const semesterBonus = 0.05
+
+var (
+	cleanSection    = regexp.MustCompile(`(?i)Grup\s*\d+[:\s]*`)
+	cleanKind       = regexp.MustCompile(`(?i)\b(Ders|Uygulama)\b`)
+	cleanInstructor = regexp.MustCompile(`(Prof\.|Doç\.|Dr\.|Öğr\.|Arş\.|Grv\.)\s*\S+`)
+	semesterNumber  = regexp.MustCompile(`\d+`)
+	stopWords       = map[string]bool{"ve": true, "veya": true, "ile": true, "and": true, "or": true, "for": true, "of": true, "the": true, "to": true, "in": true}
+	asciiFold       = strings.NewReplacer("ı", "i", "ğ", "g", "ü", "u", "ş", "s", "ö", "o", "ç", "c", " ", " ")
+)
+
+// Ders programındaki adları müfredat dersleriyle karşılaştırır.
+type Matcher struct {
+	courses []matchCourse
+}
+
+type matchCourse struct {
+	code, semester string
+	name           string
+	tokens         []string
+}
+
+func NewMatcher(courses []models.Course) *Matcher {
+	m := &Matcher{}
+	for _, c := range courses {
+		name := normalize(c.Name)
+		m.courses = append(m.courses, matchCourse{
+			code:     c.Code,
+			semester: semesterNumber.FindString(c.Semester),
+			name:     name,
+			tokens:   tokenize(name),
+		})
+	}
+	return m
+}
+
+// En iyi eşleşen dersin kodunu ve skorunu döner; eşik aşılmazsa kod boştur.
+func (m *Matcher) Match(name, semester string) (string, float64) {
+	clean := normalize(cleanInstructor.ReplaceAllString(cleanKind.ReplaceAllString(cleanSection.ReplaceAllString(name, " "), " "), " "))
+	tokens := tokenize(clean)
+	if len(tokens) == 0 {
+		return "", 0
+	}
+	semester = semesterNumber.FindString(semester)
+
+	bestCode, bestScore, bestRank := "", 0.0, 0.0
+	for _, c := range m.courses {
+		score := 0.0
+		if c.name == clean {
+			score = 1
+		} else {
+			if strings.Contains(clean, c.name) || strings.Contains(c.name, clean) {
+				score = 0.9
+				if diff := abs(len(clean) - len(c.name)); diff > 20 {
+					score -= 0.25
+				} else if diff > 10 {
+					score -= 0.1
+				}
+			}
+			if len(c.tokens) > 0 {
+				inclusion := max(tokenInclusion(c.tokens, tokens), tokenInclusion(tokens, c.tokens))
+				score = max(score, inclusion*0.8+jaccard(tokens, c.tokens)*0.2)
+			}
+		}
+
+		rank := score
+		if semester != "" && c.semester == semester {
+			rank += semesterBonus
+		}
+		if rank > bestRank {
+			bestCode, bestScore, bestRank = c.code, score, rank
+		}
+	}
+
+	if bestScore < MatchThreshold {
+		return "", bestScore
+	}
+	return bestCode, bestScore
+}
+
+func normalize(s string) string {
+	s = asciiFold.Replace(lower(s))
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '_' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') {
+			return r
+		}
+		if r == '\t' || r == '\n' || r == '-' || r == '/' {
+			return ' '
+		}
+		return -1
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func tokenize(s string) []string {
+	var tokens []string
+	for _, w := range strings.Fields(s) {
+		if !stopWords[w] {
+			tokens = append(tokens, w)
+		}
+	}
+	return tokens
+}
+
+func tokenInclusion(source, target []string) float64 {
+	total := 0.0
+	for _, s := range source {
+		best := 0.0
+		for _, t := range target {
+			best = max(best, tokenSimilarity(s, t))
+			if best == 1 {
+				break
+			}
+		}
+		total += best
+	}
+	return total / float64(len(source))
+}
+
+func jaccard(a, b []string) float64 {
+	intersection := 0.0
+	for _, x := range a {
+		for _, y := range b {
+			if tokenSimilarity(x, y) > 0.85 {
+				intersection++
+				break
+			}
+		}
+	}
+	union := float64(len(a)+len(b)) - intersection
+	if union == 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+// Roma rakamları ("Matematik II") ve rakamlar ("Matematik 2") denk sayılır.
+func tokenSimilarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	one := func(s string) bool { return s == "i" || s == "1" || s == "l" }
+	if one(a) && one(b) {
+		return 1
+	}
+	if (a == "ii" && b == "2") || (a == "2" && b == "ii") || (a == "iii" && b == "3") || (a == "3" && b == "iii") {
+		return 0.95
+	}
+	longest := max(len(a), len(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+func levenshtein(s, t string) int {
+	v0 := make([]int, len(t)+1)
+	v1 := make([]int, len(t)+1)
+	for j := range v0 {
+		v0[j] = j
+	}
+	for i := 0; i < len(s); i++ {
+		v1[0] = i + 1
+		for j := 0; j < len(t); j++ {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+			v1[j+1] = min(v1[j]+1, v0[j+1]+1, v0[j]+cost)
+		}
+		v0, v1 = v1, v0
+	}
+	return v0[len(t)]
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,36 @@
+package timetable
+
+import (
+	"testing"
+
+	"companion_server/internal/models"
+)
+
+func TestMatcher(t *testing.T) {
+	m := NewMatcher([]models.Course{
+		{Code: "BM201", Name: "Veri Yapıları", Semester: "3. Yarıyıl"},
+		{Code: "BM203", Name: "Olasılık ve İstatistik", Semester: "3. Yarıyıl"},
+		{Code: "TD101", Name: "Türk Dili I", Semester: "1. Yarıyıl"},
+		{Code: "TD102", Name: "Türk Dili II", Semester: "2. Yarıyıl"},
+		{Code: "FIZ101", Name: "Fizik I", Semester: "1. Yarıyıl"},
+		{Code: "FIZ102", Name: "Fizik I", Semester: "2. Yarıyıl"},
+	})
+
+	tests := []struct {
+		name, semester string
+		want           string
+	}{
+		{"Veri Yapıları", "3. Yarıyıl", "BM201"},
+		{"VERİ YAPILARI", "", "BM201"},
+		{"Grup 2 Olasılık ve İstatistik (Uygulama)", "3. Yarıyıl", "BM203"},
+		{"Türk Dili II", "2. Yarıyıl", "TD102"},
+		{"Fizik I", "2. Yarıyıl", "FIZ102"},
+		{"Diferansiyel Denklemler", "3. Yarıyıl", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		if code, score := m.Match(tt.name, tt.semester); code != tt.want {
+			t.Errorf("Match(%q, %q) = %s (%.2f), want %q", tt.name, tt.semester, code, score, tt.want)
+		}
+	}
+}
@@ -0,0 +1,452 @@
+// Bölüm ders programı PDF'lerini gün/saat/derslik/öğretim elemanı satırlarına çevirir ve
+// müfredat dersleriyle eşleştirir. Mobil uygulamadaki koordinat tabanlı ayrıştırmanın
+// sunucu karşılığıdır.
+package timetable
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"companion_server/internal/pdf"
+)
+
+// PDF'ten okunmuş, henüz derse eşlenmemiş program satırı
+type Slot struct {
+	Day        string
+	Start, End string
+	CourseName string
+	Instructor string
+	Room       string
+	Semester   string
+	Section    string
+}
+
+var (
+	timePattern       = regexp.MustCompile(`(\d{1,2})[:.](\d{2})\D{1,4}?(\d{1,2})[:.](\d{2})`)
+	semesterPattern   = regexp.MustCompile(`(?i)(\d+)\.\s*YARIYIL`)
+	instructorPattern = regexp.MustCompile(`(Prof\.|Doç\.|Dr\.|Öğr\.|Arş\.|Yrd\.|Grv\.)`)
+	// Parantez içindeki tür satırları ("(Laboratuvar)") atılır; ders adının parçası değildir.
+	courseTypePattern = regexp.MustCompile(`(?i)^\(?\s*(Uygulama|Laboratuvar|Teori|Pratik)\s*\)?$`)
+	footerPattern     = regexp.MustCompile(`(?i)(Ortak Dersleri|Bölüm Başkanı|Dekan|[DEH] Grubu)`)
+	sectionPattern    = regexp.MustCompile(`(?i)\bGrup\s*(\d+)[:\s]*`)
+	roomCodePattern   = regexp.MustCompile(`\b[A-Z]{1,2}-?\d{2,4}\b`)
+	labPattern        = regexp.MustCompile(`\bLab(\.|\b)`)
+)
+
+// Ders programı günleri; sıraları takvim gün numarasıyla (Pazartesi = 0) aynıdır.
+var Days = []string{"Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma"}
+
+var locationKeywords = []string{"bina", "amfi", "blok", "salon", "derslik", "rektörlük", "uzaktan", "online"}
+
+// Adı olmayan hücrelerin yer tutucusu; önceki saatteki dersin devamıdır.
+const continuation = "Ders"
+
+const (
+	columnHalfWidth = 75.0
+	clusterGap      = 60.0
+	rowGap          = 8.0
+	footerMargin    = 30.0
+)
+
+func lower(s string) string {
+	return strings.ToLowerSpecial(unicode.TurkishCase, s)
+}
+
+// Gün sırası (Pazartesi = 0); gün değilse -1
+func DayIndex(day string) int {
+	day = lower(strings.TrimSpace(day))
+	for i, d := range Days {
+		if lower(d) == day {
+			return i
+		}
+	}
+	return -1
+}
+
+func dayIn(text string) int {
+	l := lower(text)
+	for i, d := range Days {
+		if strings.Contains(l, lower(d)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// PDF'teki bütün sayfaları ayrıştırır. Ardışık saatlerde süren dersler tek satırda birleşir.
+func ParsePDF(data []byte) ([]Slot, error) {
+	doc, err := pdf.Open(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []Slot
+	for i, page := range doc.Pages() {
+		lines, err := page.Lines()
+		if err != nil {
+			return nil, fmt.Errorf("%d. sayfa okunamadı: %w", i+1, err)
+		}
+		slots = append(slots, parsePage(lines, page.Width, page.Height)...)
+	}
+	if len(slots) == 0 {
+		return nil, fmt.Errorf("ders programında ders bulunamadı")
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		a, b := slots[i], slots[j]
+		if a.Semester != b.Semester {
+			return a.Semester < b.Semester
+		}
+		if da, db := DayIndex(a.Day), DayIndex(b.Day); da != db {
+			return da < db
+		}
+		if a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		return a.Section < b.Section
+	})
+	return mergeConsecutive(fillContinuations(slots)), nil
+}
+
+type column struct {
+	start, end float64
+}
+
+type row struct {
+	start, end  string
+	top, bottom float64
+}
+
+type cellKey struct{ day, row int }
+
+func parsePage(lines []pdf.Line, width, height float64) []Slot {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	semester := ""
+	for _, l := range lines {
+		if m := semesterPattern.FindStringSubmatch(l.Text); m != nil {
+			semester = m[1] + ". Yarıyıl"
+			break
+		}
+	}
+
+	columns := detectColumns(lines, width)
+	rows := detectRows(lines, columns[0].start, height)
+	if len(rows) == 0 {
+		return nil
+	}
+
+	cells := make(map[cellKey][]pdf.Line)
+	for _, l := range lines {
+		text := strings.TrimSpace(l.Text)
+		if len([]rune(text)) < 2 || semesterPattern.MatchString(text) || DayIndex(text) >= 0 {
+			continue
+		}
+		if l.Right <= columns[0].start {
+			continue
+		}
+
+		cx, cy := l.CenterX(), l.CenterY()
+		day, r := -1, -1
+		for i, c := range columns {
+			if cx >= c.start && cx < c.end {
+				day = i
+				break
+			}
+		}
+		for i, rw := range rows {
+			if cy >= rw.top && cy < rw.bottom {
+				r = i
+				break
+			}
+		}
+		if day >= 0 && r >= 0 {
+			k := cellKey{day, r}
+			cells[k] = append(cells[k], l)
+		}
+	}
+
+	var slots []Slot
+	for k, cellLines := range cells {
+		sort.SliceStable(cellLines, func(i, j int) bool { return cellLines[i].Top < cellLines[j].Top })
+		for _, slot := range parseCell(cellLines) {
+			slot.Day = Days[k.day]
+			slot.Start, slot.End = rows[k.row].start, rows[k.row].end
+			slot.Semester = semester
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// Sütunlar gün başlıklarından bulunur. Başlığı okunamayan günün merkezi, sayfa
+// genişliğine göre beklenen yere en yakın metin kümesinden tahmin edilir.
+func detectColumns(lines []pdf.Line, width float64) []column {
+	centers := make([]float64, len(Days))
+	for i := range centers {
+		centers[i] = math.NaN()
+	}
+	found := 0
+	for _, l := range lines {
+		if d := DayIndex(l.Text); d >= 0 && math.IsNaN(centers[d]) {
+			centers[d] = l.CenterX()
+			found++
+		}
+	}
+
+	if found < len(Days) {
+		clusters := clusterCenters(lines)
+		for i := range centers {
+			if !math.IsNaN(centers[i]) {
+				continue
+			}
+			ideal := width * (0.20 + 0.16*float64(i))
+			centers[i] = ideal
+			best := columnHalfWidth
+			for _, c := range clusters {
+				if diff := math.Abs(c - ideal); diff < best {
+					best, centers[i] = diff, c
+				}
+			}
+		}
+	}
+
+	// Sütun sınırları komşu merkezlerin orta noktalarıdır.
+	columns := make([]column, len(centers))
+	for i, c := range centers {
+		columns[i] = column{start: c - columnHalfWidth, end: c + columnHalfWidth}
+		if i > 0 {
+			columns[i].start = (centers[i-1] + c) / 2
+		}
+		if i+1 < len(centers) {
+			columns[i].end = (c + centers[i+1]) / 2
+		}
+	}
+	return columns
+}
+
+func clusterCenters(lines []pdf.Line) []float64 {
+	var xs []float64
+	for _, l := range lines {
+		if !timePattern.MatchString(l.Text) && dayIn(l.Text) < 0 && len([]rune(l.Text)) > 2 {
+			xs = append(xs, l.CenterX())
+		}
+	}
+	sort.Float64s(xs)
+
+	var centers []float64
+	var sum float64
+	var n int
+	for i, x := range xs {
+		if i > 0 && x-xs[i-1] >= clusterGap {
+			centers = append(centers, sum/float64(n))
+			sum, n = 0, 0
+		}
+		sum += x
+		n++
+	}
+	if n > 0 {
+		centers = append(centers, sum/float64(n))
+	}
+	return centers
+}
+
+// Satırlar, gün sütunlarının solundaki saat aralıklarından ("09:00-09:50") çıkarılır.
+func detectRows(lines []pdf.Line, firstColumn, height float64) []row {
+	var times []row
+	for _, l := range lines {
+		if l.CenterX() > firstColumn {
+			continue
+		}
+		m := timePattern.FindStringSubmatch(l.Text)
+		if m == nil {
+			continue
+		}
+		times = append(times, row{
+			start:  clock(m[1], m[2]),
+			end:    clock(m[3], m[4]),
+			top:    l.Top,
+			bottom: l.Bottom,
+		})
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].top < times[j].top })
+
+	var unique []row
+	for _, t := range times {
+		if n := len(unique); n == 0 || t.top-unique[n-1].top > rowGap {
+			unique = append(unique, t)
+		}
+	}
+
+	rows := make([]row, len(unique))
+	for i, t := range unique {
+		r := row{start: t.start, end: t.end}
+		if i == 0 {
+			r.top = t.top - (t.bottom - t.top) - rowGap
+		} else {
+			r.top = (unique[i-1].bottom + t.top) / 2
+		}
+		if i == len(unique)-1 {
+			r.bottom = height - footerMargin
+		} else {
+			r.bottom = (t.bottom + unique[i+1].top) / 2
+		}
+		rows[i] = r
+	}
+	return rows
+}
+
+func clock(h, m string) string {
+	hour, _ := strconv.Atoi(h)
+	return fmt.Sprintf("%02d:%s", hour, m)
+}
+
+// Hücre satırlarını ders adı, öğretim elemanı, derslik ve gruba ayırır. "Grup N" ile
+// başlayan her satır aynı hücrede yeni bir şube başlatır.
+func parseCell(lines []pdf.Line) []Slot {
+	var slots []Slot
+	var cur Slot
+	var name []string
+	seen := false
+	flush := func() {
+		cur.CourseName = strings.Join(name, " ")
+		if cur.CourseName == "" {
+			cur.CourseName = continuation
+		}
+		if seen {
+			slots = append(slots, cur)
+		}
+		cur, name, seen = Slot{}, nil, false
+	}
+
+	for _, l := range lines {
+		text := strings.TrimSpace(l.Text)
+		if text == "" || footerPattern.MatchString(text) || courseTypePattern.MatchString(text) {
+			continue
+		}
+		seen = true
+		if m := sectionPattern.FindStringSubmatchIndex(text); m != nil && m[0] == 0 {
+			if len(name) > 0 || cur.Section != "" {
+				flush()
+			}
+			cur.Section = "Grup " + text[m[2]:m[3]]
+			if text = strings.TrimSpace(text[m[1]:]); text == "" {
+				continue
+			}
+		}
+		if instructorPattern.MatchString(text) {
+			cur.Instructor = joinNonEmpty(cur.Instructor, text, ", ")
+			continue
+		}
+		if isLocation(text) {
+			cur.Room = joinNonEmpty(cur.Room, text, " ")
+			continue
+		}
+		if lower(text) == lower(continuation) {
+			continue
+		}
+		name = append(name, text)
+	}
+	flush()
+	return slots
+}
+
+func isLocation(text string) bool {
+	if roomCodePattern.MatchString(text) || labPattern.MatchString(text) {
+		return true
+	}
+	l := lower(text)
+	for _, kw := range locationKeywords {
+		if strings.Contains(l, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func joinNonEmpty(a, b, sep string) string {
+	if a == "" {
+		return b
+	}
+	return a + sep + b
+}
+
+// Adı okunamayan hücre, aynı gün ve şubede bir önceki saatteki dersin devamıdır.
+func fillContinuations(slots []Slot) []Slot {
+	var result []Slot
+	for i, s := range slots {
+		if s.CourseName == continuation {
+			prev := -1
+			for j := i - 1; j >= 0; j-- {
+				p := slots[j]
+				if p.Day != s.Day || p.Semester != s.Semester {
+					break
+				}
+				if p.CourseName != continuation && p.Section == s.Section {
+					prev = j
+					break
+				}
+			}
+			if prev < 0 {
+				continue
+			}
+			p := slots[prev]
+			s.CourseName = p.CourseName
+			if s.Instructor == "" {
+				s.Instructor = p.Instructor
+			}
+			if s.Room == "" {
+				s.Room = p.Room
+			}
+			slots[i] = s
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+// Aynı dersin art arda gelen saatlerini (09:00-09:50, 10:00-10:50) tek satıra indirir.
+func mergeConsecutive(slots []Slot) []Slot {
+	var merged []Slot
+	for _, s := range slots {
+		found := false
+		for i := len(merged) - 1; i >= 0; i-- {
+			last := &merged[i]
+			if last.Day != s.Day || last.Semester != s.Semester {
+				break
+			}
+			if last.CourseName == s.CourseName && last.Section == s.Section &&
+				last.Room == s.Room && last.Instructor == s.Instructor && adjacent(last.End, s.Start) {
+				last.End = s.End
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, s)
+		}
+	}
+	return merged
+}
+
+// İki ders saati arasında en fazla bir teneffüs var mı?
+func adjacent(end, start string) bool {
+	e, ok1 := minutes(end)
+	s, ok2 := minutes(start)
+	return ok1 && ok2 && s >= e && s-e <= 15
+}
+
+func minutes(clock string) (int, bool) {
+	var h, m int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &h, &m); err != nil {
+		return 0, false
+	}
+	return h*60 + m, true
+}
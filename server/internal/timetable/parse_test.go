@@ -0,0 +1,138 @@
+package timetable
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"companion_server/internal/pdf"
+)
+
+// testdata/schedule.pdf: tek sayfalık 3. yarıyıl programı. Pazartesi iki saatlik ders
+// ("Ders" devam hücresiyle), salı aynı hücrede iki grup, çarşamba öğle arasıyla bölünen
+// üç saatlik laboratuvar, cuma uzaktan eğitim dersi ve imza satırı içerir.
+func TestParsePDF(t *testing.T) {
+	data, err := os.ReadFile("testdata/schedule.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	slots, err := ParsePDF(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const sem = "3. Yarıyıl"
+	want := []Slot{
+		{Day: "Pazartesi", Start: "08:00", End: "09:50", CourseName: "Veri Yapıları", Instructor: "Dr. Öğr. Üyesi Ayşe Yılmaz", Room: "D301", Semester: sem},
+		{Day: "Salı", Start: "10:00", End: "10:50", CourseName: "Olasılık ve İstatistik", Instructor: "Prof. Dr. Mehmet Kaya", Room: "Amfi 2", Semester: sem, Section: "Grup 1"},
+		{Day: "Salı", Start: "10:00", End: "10:50", CourseName: "Olasılık ve İstatistik", Instructor: "Doç. Dr. Zeynep Ak", Room: "D105", Semester: sem, Section: "Grup 2"},
+		{Day: "Çarşamba", Start: "11:00", End: "11:50", CourseName: "Bilgisayar Ağları", Instructor: "Arş. Gör. Can Demir", Room: "Lab 3", Semester: sem},
+		{Day: "Çarşamba", Start: "13:00", End: "14:50", CourseName: "Bilgisayar Ağları", Instructor: "Arş. Gör. Can Demir", Room: "Lab 3", Semester: sem},
+		{Day: "Cuma", Start: "08:00", End: "08:50", CourseName: "Türk Dili II", Instructor: "Öğr. Gör. Ali Veli", Room: "Uzaktan Eğitim", Semester: sem},
+	}
+	if len(slots) != len(want) {
+		t.Fatalf("got %d slots, want %d: %+v", len(slots), len(want), slots)
+	}
+	for i := range want {
+		if slots[i] != want[i] {
+			t.Errorf("slot %d = %+v, want %+v", i, slots[i], want[i])
+		}
+	}
+}
+
+func TestParsePDFErrors(t *testing.T) {
+	empty := []byte("%PDF-1.4\n" +
+		"1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		"2 0 obj\n<< /Type /Pages /Kids [3 0 R] /Count 1 >>\nendobj\n" +
+		"3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 842 595] >>\nendobj\n" +
+		"trailer\n<< /Root 1 0 R >>\n")
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"PDF değil", []byte("<html>Ders programı</html>")},
+		{"ders yok", empty},
+	}
+	for _, tt := range tests {
+		if slots, err := ParsePDF(tt.data); err == nil {
+			t.Errorf("%s: got %d slots, want error", tt.name, len(slots))
+		}
+	}
+}
+
+func TestParseCell(t *testing.T) {
+	cell := func(texts ...string) []pdf.Line {
+		lines := make([]pdf.Line, len(texts))
+		for i, s := range texts {
+			lines[i] = pdf.Line{Text: s}
+		}
+		return lines
+	}
+
+	tests := []struct {
+		name  string
+		lines []pdf.Line
+		want  []Slot
+	}{
+		{"boş", nil, nil},
+		{
+			"tek ders",
+			cell("Veri", "Yapıları", "(Uygulama)", "Dr. Öğr. Üyesi Ayşe Yılmaz", "B-204"),
+			[]Slot{{CourseName: "Veri Yapıları", Instructor: "Dr. Öğr. Üyesi Ayşe Yılmaz", Room: "B-204"}},
+		},
+		{
+			"iki grup",
+			cell("Grup 1: Fizik I", "Amfi 1", "Grup 2 Fizik I", "Amfi 2"),
+			[]Slot{
+				{CourseName: "Fizik I", Room: "Amfi 1", Section: "Grup 1"},
+				{CourseName: "Fizik I", Room: "Amfi 2", Section: "Grup 2"},
+			},
+		},
+		{"devam hücresi", cell("Ders"), []Slot{{CourseName: continuation}}},
+		{"sadece imza", cell("Bölüm Başkanı"), nil},
+	}
+	for _, tt := range tests {
+		if got := parseCell(tt.lines); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseCell = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMergeConsecutive(t *testing.T) {
+	slot := func(day, start, end, name string) Slot {
+		return Slot{Day: day, Start: start, End: end, CourseName: name}
+	}
+
+	tests := []struct {
+		name  string
+		slots []Slot
+		want  []Slot
+	}{
+		{
+			"teneffüslü saatler",
+			[]Slot{slot("Pazartesi", "09:00", "09:50", "Fizik"), slot("Pazartesi", "10:00", "10:50", "Fizik")},
+			[]Slot{slot("Pazartesi", "09:00", "10:50", "Fizik")},
+		},
+		{
+			"öğle arası",
+			[]Slot{slot("Pazartesi", "11:00", "11:50", "Fizik"), slot("Pazartesi", "13:00", "13:50", "Fizik")},
+			[]Slot{slot("Pazartesi", "11:00", "11:50", "Fizik"), slot("Pazartesi", "13:00", "13:50", "Fizik")},
+		},
+		{
+			"farklı gün",
+			[]Slot{slot("Pazartesi", "09:00", "09:50", "Fizik"), slot("Salı", "10:00", "10:50", "Fizik")},
+			[]Slot{slot("Pazartesi", "09:00", "09:50", "Fizik"), slot("Salı", "10:00", "10:50", "Fizik")},
+		},
+		{
+			"araya giren ders",
+			[]Slot{slot("Salı", "09:00", "09:50", "Fizik"), slot("Salı", "09:00", "09:50", "Kimya"), slot("Salı", "10:00", "10:50", "Fizik")},
+			[]Slot{slot("Salı", "09:00", "10:50", "Fizik"), slot("Salı", "09:00", "09:50", "Kimya")},
+		},
+	}
+	for _, tt := range tests {
+		if got := mergeConsecutive(tt.slots); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: mergeConsecutive = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+const (
+	// Bir gönderim için en fazla deneme; sonra gönderim failed olur.
+	MaxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+	// Tek turda işlenen en fazla gönderim
+	batchSize = 50
+	// Günlüğe yazılan yanıt gövdesi sınırı
+	maxErrorBody = 512
+)
+
+// Alıcıya giden gövde
+type envelope struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// X-Webhook-Signature değerini üretir: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Alıcı aynı hesabı yapıp zaman damgasının güncel olduğunu da kontrol etmelidir.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// n. başarısız denemeden sonraki bekleme: 30s, 1m, 2m, ... en fazla 6 saat.
+func Backoff(attempt int) time.Duration {
+	d := baseBackoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		return maxBackoff
+	}
+	return d
+}
+
+// Bekleyen webhook gönderimlerini belirli aralıklarla yapar. Olaylar storage.EnqueueEvent
+// ile kuyruğa yazılır; işçi sadece teslimden sorumludur.
+type Worker struct {
+	db     *sql.DB
+	client *http.Client
+	quit   chan struct{}
+}
+
+func NewWorker(db *sql.DB) *Worker {
+	return &Worker{
+		db:     db,
+		client: &http.Client{Timeout: 10 * time.Second},
+		quit:   make(chan struct{}),
+	}
+}
+
+func (wk *Worker) Start(interval time.Duration) {
+	log.Printf("Webhook gönderimi başlatıldı, %v aralıkla", interval)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				wk.RunOnce(ctx)
+			case <-wk.quit:
+				cancel()
+				return
+			}
+		}
+	}()
+}
+
+func (wk *Worker) Stop() {
+	close(wk.quit)
+}
+
+// Zamanı gelmiş gönderimleri sırayla dener.
+func (wk *Worker) RunOnce(ctx context.Context) {
+	due, err := storage.GetDueDeliveries(wk.db, time.Now(), batchSize)
+	if err != nil {
+		log.Printf("Webhook gönderimleri okunamadı: %v", err)
+		return
+	}
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		wk.deliver(ctx, d)
+	}
+}
+
+func (wk *Worker) deliver(ctx context.Context, d storage.DueDelivery) {
+	statusCode, sendErr := wk.send(ctx, d)
+
+	status, errText := models.DeliverySucceeded, ""
+	var next *time.Time
+	if sendErr != nil {
+		errText = sendErr.Error()
+		status = models.DeliveryFailed
+		if attempt := d.Attempts + 1; attempt < MaxAttempts {
+			status = models.DeliveryPending
+			at := time.Now().UTC().Add(Backoff(attempt)).Truncate(time.Second)
+			next = &at
+		}
+	}
+
+	if err := storage.RecordDeliveryAttempt(wk.db, d.ID, status, statusCode, errText, next); err != nil {
+		log.Printf("Webhook gönderim sonucu yazılamadı: %v", err)
+	}
+	if status == models.DeliveryFailed {
+		log.Printf("Webhook gönderimi %d denemede başarısız: %s %s", MaxAttempts, d.URL, errText)
+	}
+}
+
+func (wk *Worker) send(ctx context.Context, d storage.DueDelivery) (int, error) {
+	body, err := json.Marshal(envelope{ID: d.EventID, Type: d.Event, CreatedAt: d.EventCreatedAt, Data: d.Payload})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "IUC-Companion-Webhooks")
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(d.Secret, timestamp, body))
+
+	resp, err := wk.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return resp.StatusCode, fmt.Errorf("alıcı %s döndü: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"companion_server/internal/models"
+	"companion_server/internal/storage"
+)
+
+func TestSign(t *testing.T) {
+	got := Sign("gizli", 1700000000, []byte(`{"id":1}`))
+	want := "sha256=89db8472e9b1dd5815abe299dd9c3298ce5fcf0f0efdbd85ed17bf05d5ab5c9c"
+	if got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+	if Sign("başka", 1700000000, []byte(`{"id":1}`)) == want || Sign("gizli", 1700000001, []byte(`{"id":1}`)) == want {
+		t.Error("signature does not depend on secret and timestamp")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{10, 256 * time.Minute},
+		{11, maxBackoff},
+		{100, maxBackoff},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if err := storage.CreateTables(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func delivery(t *testing.T, db *sql.DB, webhookID, id int64) models.WebhookDelivery {
+	t.Helper()
+	deliveries, err := storage.ListDeliveries(db, webhookID, "", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range deliveries {
+		if d.ID == id {
+			return d
+		}
+	}
+	t.Fatalf("delivery %d not found", id)
+	return models.WebhookDelivery{}
+}
+
+func TestWorkerRetryAndReplay(t *testing.T) {
+	db := testDB(t)
+
+	var status atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	var secret string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		if r.Header.Get("X-Webhook-Signature") != Sign(secret, ts, body) {
+			t.Errorf("bad signature for %s", body)
+		}
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer srv.Close()
+
+	hook, err := storage.CreateWebhook(db, srv.URL, []string{models.EventAll})
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret = hook.Secret
+	if _, err := storage.EnqueueEvent(db, models.EventScrapeCompleted, map[string]int{"courses": 3}); err != nil {
+		t.Fatal(err)
+	}
+	wk := NewWorker(db)
+	ctx := context.Background()
+
+	// İlk başarısız deneme: gönderim beklemede kalır, sonraki deneme ileri atılır.
+	wk.RunOnce(ctx)
+	d := delivery(t, db, hook.ID, 1)
+	if d.Status != models.DeliveryPending || d.Attempts != 1 || d.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("after first failure: %+v", d)
+	}
+	if d.NextAttemptAt == nil || !d.NextAttemptAt.After(time.Now()) {
+		t.Errorf("next attempt = %v, want in the future", d.NextAttemptAt)
+	}
+	// Zamanı gelmeden tekrar denenmez.
+	wk.RunOnce(ctx)
+	if d := delivery(t, db, hook.ID, 1); d.Attempts != 1 {
+		t.Errorf("attempts = %d before backoff elapsed", d.Attempts)
+	}
+
+	// Son deneme hakkı da başarısız olursa gönderim failed olur.
+	if _, err := db.Exec("UPDATE webhook_deliveries SET attempts = ?, next_attempt_at = ?",
+		MaxAttempts-1, time.Now().UTC().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	wk.RunOnce(ctx)
+	d = delivery(t, db, hook.ID, 1)
+	if d.Status != models.DeliveryFailed || d.Attempts != MaxAttempts || d.NextAttemptAt != nil {
+		t.Fatalf("after last failure: %+v", d)
+	}
+
+	// Yeniden gönderim yeni bir kayıt açar, eskisi günlükte kalır.
+	replay, err := storage.ReplayDelivery(db, d.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.ID == d.ID || replay.Status != models.DeliveryPending || replay.Attempts != 0 || replay.EventID != d.EventID {
+		t.Fatalf("replay = %+v", replay)
+	}
+	status.Store(http.StatusNoContent)
+	wk.RunOnce(ctx)
+	if r := delivery(t, db, hook.ID, replay.ID); r.Status != models.DeliverySucceeded || r.DeliveredAt == nil || r.Attempts != 1 {
+		t.Errorf("replayed delivery = %+v", r)
+	}
+	if d := delivery(t, db, hook.ID, d.ID); d.Status != models.DeliveryFailed {
+		t.Errorf("original delivery = %+v", d)
+	}
+
+	if _, err := storage.ReplayDelivery(db, 999); err != sql.ErrNoRows {
+		t.Errorf("replay of unknown delivery: err = %v", err)
+	}
+}